@@ -23,8 +23,34 @@
 #include "vm/evmzero/profiler.h"
 #include "vm/evmzero/sha3_cache.h"
 
+extern "C" {
+// Profiling data exported through the evmzero_get_profile function. Instruction
+// statistics are indexed by opcode.
+struct evmzero_profile {
+  uint64_t interpreter_calls;
+  uint64_t interpreter_time_ns;
+  uint64_t instruction_calls[256];
+  uint64_t instruction_time_ns[256];
+};
+}
+
 namespace tosca::evmzero {
 
+static_assert(op::kNumUsedAndUnusedOpCodes <= 256);
+
+template <ProfilerMode Mode>
+void CopyProfile(const Profile<Mode>& profile, evmzero_profile& out) {
+  out = {};
+  const auto interpreter_stats = profile.GetInterpreterStats();
+  out.interpreter_calls = interpreter_stats.num_calls;
+  out.interpreter_time_ns = static_cast<uint64_t>(interpreter_stats.total_time.count());
+  for (std::size_t i = 0; i < op::kNumUsedAndUnusedOpCodes; ++i) {
+    const auto opcode_stats = profile.GetInstructionStats(static_cast<op::OpCode>(i));
+    out.instruction_calls[i] = opcode_stats.num_calls;
+    out.instruction_time_ns[i] = static_cast<uint64_t>(opcode_stats.total_time.count());
+  }
+}
+
 evmc_status_code ToEvmcStatusCode(RunState state) {
   switch (state) {
     case RunState::kRunning:
@@ -303,6 +329,16 @@ class VM : public evmc_vm {
     }
   }
 
+  void GetProfile(evmzero_profile& out) {
+    if (profiling_enabled_) {
+      CopyProfile(profiler_.Collect(), out);
+    } else if (profiling_external_enabled_) {
+      CopyProfile(profiler_external_.Collect(), out);
+    } else {
+      out = {};
+    }
+  }
+
   void ResetProfiler() {
     if (profiling_enabled_) {
       profiler_.Reset();
@@ -379,6 +415,10 @@ EVMC_EXPORT evmc_vm_steppable* evmc_create_steppable_evmzero() noexcept {
 
 EVMC_EXPORT void evmzero_dump_profile(evmc_vm* vm) noexcept { reinterpret_cast<VM*>(vm)->DumpProfile(); }
 
+EVMC_EXPORT void evmzero_get_profile(evmc_vm* vm, evmzero_profile* out) noexcept {
+  reinterpret_cast<VM*>(vm)->GetProfile(*out);
+}
+
 EVMC_EXPORT void evmzero_reset_profiler(evmc_vm* vm) noexcept { reinterpret_cast<VM*>(vm)->ResetProfiler(); }
 }
 
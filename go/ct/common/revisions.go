@@ -17,7 +17,9 @@ import (
 )
 
 // Newest tosca.Revision currently supported by the CT specification
-const NewestSupportedRevision = tosca.R13_Cancun
+const NewestSupportedRevision = tosca.R14_Prague
+
+// Newest tosca.Revision supported by all EVM implementations under test
 const NewestFullySupportedRevision = tosca.R13_Cancun
 
 const R99_UnknownNextRevision = tosca.Revision(99)
@@ -42,8 +44,10 @@ func GetForkBlock(revision tosca.Revision) uint64 {
 		return 4000
 	case tosca.R13_Cancun:
 		return 5000
-	default: // R99_UnknownNextRevision:
+	case tosca.R14_Prague:
 		return 6000
+	default: // R99_UnknownNextRevision:
+		return 7000
 	}
 }
 
@@ -66,8 +70,10 @@ func GetForkTime(revision tosca.Revision) uint64 {
 		return 4000
 	case tosca.R13_Cancun:
 		return 5000
-	default:
+	case tosca.R14_Prague:
 		return 6000
+	default:
+		return 7000
 	}
 }
 
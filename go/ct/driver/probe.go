@@ -29,7 +29,6 @@ import (
 	"github.com/Fantom-foundation/Tosca/go/ct/st"
 	"github.com/dsnet/golib/unitconv"
 	"github.com/urfave/cli/v2"
-	"golang.org/x/exp/maps"
 )
 
 var ProbeCmd = cliUtils.AddCommonFlags(cli.Command{
@@ -54,9 +53,9 @@ func doProbe(context *cli.Context) error {
 		evmIdentifier = context.Args().Get(0)
 	}
 
-	evm, ok := evms[evmIdentifier]
-	if !ok {
-		return fmt.Errorf("invalid EVM identifier, use one of: %v", maps.Keys(evms))
+	evm, info, err := getEvm(evmIdentifier)
+	if err != nil {
+		return err
 	}
 
 	jobCount := cliUtils.JobsFlag.Fetch(context)
@@ -73,7 +72,10 @@ func doProbe(context *cli.Context) error {
 	// The constraints to be placed on generated states.
 	condition := rlz.And(
 		rlz.IsCode(rlz.Pc()),
-		rlz.RevisionBounds(common.MinRevision, common.NewestFullySupportedRevision),
+		rlz.RevisionBounds(
+			max(common.MinRevision, info.OldestSupportedRevision),
+			min(common.NewestSupportedRevision, info.NewestSupportedRevision),
+		),
 	)
 
 	fmt.Printf("Start random tests on %s using %d jobs, seed %d, timeout %v and constraints %s ...\n", evmIdentifier, jobCount, seed, timeout, condition)
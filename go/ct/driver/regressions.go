@@ -20,7 +20,6 @@ import (
 	"github.com/Fantom-foundation/Tosca/go/ct/spc"
 	"github.com/Fantom-foundation/Tosca/go/ct/st"
 	"github.com/urfave/cli/v2"
-	"golang.org/x/exp/maps"
 )
 
 var RegressionsCmd = cliUtils.AddCommonFlags(cli.Command{
@@ -83,13 +82,13 @@ func doRegressionTests(context *cli.Context) error {
 		evmIdentifier = context.Args().Get(0)
 	}
 
-	evm, ok := evms[evmIdentifier]
-	if !ok {
-		return fmt.Errorf("invalid EVM identifier, use one of: %v", maps.Keys(evms))
+	evm, _, err := getEvm(evmIdentifier)
+	if err != nil {
+		return err
 	}
 
 	inputs := context.StringSlice("input")
-	inputs, err := enumerateInputs(inputs)
+	inputs, err = enumerateInputs(inputs)
 	if err != nil {
 		return err
 	}
@@ -16,6 +16,7 @@ import (
 	"math"
 	"os"
 	"regexp"
+	"slices"
 	"sync/atomic"
 	"time"
 
@@ -33,7 +34,6 @@ import (
 	"github.com/Fantom-foundation/Tosca/go/tosca"
 	"github.com/dsnet/golib/unitconv"
 	"github.com/urfave/cli/v2"
-	"golang.org/x/exp/maps"
 )
 
 var RunCmd = cliUtils.AddCommonFlags(cli.Command{
@@ -54,11 +54,39 @@ var RunCmd = cliUtils.AddCommonFlags(cli.Command{
 	},
 })
 
-var evms = map[string]ct.Evm{
-	"lfvm":    lfvm.NewConformanceTestingTarget(),
-	"geth":    geth.NewConformanceTestingTarget(),
-	"evmzero": evmzero.NewConformanceTestingTarget(),
-	"evmrs":   evmrs.NewConformanceTestingTarget(),
+// conformanceTestingTargets lists the factories of conformance testing
+// targets for interpreters, indexed by the name they are registered with.
+var conformanceTestingTargets = map[string]func() ct.Evm{
+	"lfvm":    lfvm.NewConformanceTestingTarget,
+	"geth":    geth.NewConformanceTestingTarget,
+	"evmzero": evmzero.NewConformanceTestingTarget,
+	"evmrs":   evmrs.NewConformanceTestingTarget,
+}
+
+// getEvm creates the conformance testing target for the interpreter
+// registered under the given name. Only interpreters registered as being
+// steppable can be tested. The registered capabilities are returned as well.
+func getEvm(name string) (ct.Evm, tosca.InterpreterInfo, error) {
+	info, found := tosca.GetInterpreterInfo(name)
+	newTarget, hasTarget := conformanceTestingTargets[name]
+	if !found || !info.Steppable || !hasTarget {
+		return nil, tosca.InterpreterInfo{}, fmt.Errorf("invalid EVM identifier, use one of: %v", getTestableEvms())
+	}
+	return newTarget(), info, nil
+}
+
+// getTestableEvms returns the names of all registered interpreters which can
+// be subject to conformance tests.
+func getTestableEvms() []string {
+	res := []string{}
+	for name := range tosca.GetAllRegisteredInterpreters() {
+		info, _ := tosca.GetInterpreterInfo(name)
+		if _, hasTarget := conformanceTestingTargets[name]; hasTarget && info.Steppable {
+			res = append(res, name)
+		}
+	}
+	slices.Sort(res)
+	return res
 }
 
 func doRun(context *cli.Context) error {
@@ -82,9 +110,9 @@ func doRun(context *cli.Context) error {
 	if context.Args().Len() >= 1 {
 		evmIdentifier = context.Args().Get(0)
 	}
-	evm, ok := evms[evmIdentifier]
-	if !ok {
-		return fmt.Errorf("invalid EVM identifier, use one of: %v", maps.Keys(evms))
+	evm, info, err := getEvm(evmIdentifier)
+	if err != nil {
+		return err
 	}
 
 	defer fmt.Printf("Seed Used: %d\n", seed)
@@ -118,6 +146,12 @@ func doRun(context *cli.Context) error {
 			return rlz.ConsumeContinue
 		}
 
+		// States of revisions not supported by the EVM are not tested.
+		if !info.SupportsRevision(state.Revision) {
+			numUnsupportedTests.Add(1)
+			return rlz.ConsumeContinue
+		}
+
 		if err := runTest(state, evm, filter); err != nil {
 			targetError := &tosca.ErrUnsupportedRevision{}
 			if errors.As(err, &targetError) {
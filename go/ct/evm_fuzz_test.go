@@ -29,24 +29,24 @@ import (
 // with the failures found in other tests.
 // So far failures will be stored in the folder: testdata/fuzz/FuzzerFunctionName/
 func FuzzGeth(f *testing.F) {
-	fuzzVm(geth.NewConformanceTestingTarget(), f)
+	fuzzVm("geth", geth.NewConformanceTestingTarget(), f)
 }
 
 // FuzzLfvm is a fuzzing test for lfvm
 func FuzzLfvm(f *testing.F) {
-	fuzzVm(lfvm.NewConformanceTestingTarget(), f)
+	fuzzVm("lfvm", lfvm.NewConformanceTestingTarget(), f)
 }
 
 // FuzzLfvm is a fuzzing test for evmzero, (issue #549 )
 // func FuzzEvmzero(f *testing.F) {
-// 	fuzzVm(evmzero.NewConformanceTestingTarget(), f)
+// 	fuzzVm("evmzero", evmzero.NewConformanceTestingTarget(), f)
 // }
 
 // FuzzDifferentialLfvmVsGeth compares state output between lfvm and geth
 func FuzzDifferentialLfvmVsGeth(f *testing.F) {
 	differentialFuzz(f,
-		lfvm.NewConformanceTestingTarget(),
-		geth.NewConformanceTestingTarget(),
+		"lfvm", lfvm.NewConformanceTestingTarget(),
+		"geth", geth.NewConformanceTestingTarget(),
 	)
 }
 
@@ -56,15 +56,15 @@ func FuzzDifferentialLfvmVsGeth(f *testing.F) {
 // invoke the all other tests in the file, and they will fail.
 // func FuzzDifferentialEvmzeroVsGeth(f *testing.F) {
 // 	differentialFuzz(f,
-// 		evmzero.NewConformanceTestingTarget(),
-// 		geth.NewConformanceTestingTarget(),
+// 		"evmzero", evmzero.NewConformanceTestingTarget(),
+// 		"geth", geth.NewConformanceTestingTarget(),
 // 	)
 // }
 
 //////////////////////////////////////////////////////////////////////////////
 // Fuzzing helpers
 
-func differentialFuzz(f *testing.F, testeeVm, referenceVm ct.Evm) {
+func differentialFuzz(f *testing.F, testeeName string, testeeVm ct.Evm, referenceName string, referenceVm ct.Evm) {
 
 	prepareFuzzingSeeds(f)
 
@@ -76,6 +76,7 @@ func differentialFuzz(f *testing.F, testeeVm, referenceVm ct.Evm) {
 			t.Skip(err)
 		}
 		defer state.Release()
+		skipUnsupportedRevision(t, state.Revision, testeeName, referenceName)
 
 		testeeResultState, err := testeeVm.StepN(state.Clone(), 1)
 		if err != nil {
@@ -118,7 +119,7 @@ func differentialFuzz(f *testing.F, testeeVm, referenceVm ct.Evm) {
 	})
 }
 
-func fuzzVm(testee ct.Evm, f *testing.F) {
+func fuzzVm(testeeName string, testee ct.Evm, f *testing.F) {
 
 	prepareFuzzingSeeds(f)
 
@@ -128,6 +129,7 @@ func fuzzVm(testee ct.Evm, f *testing.F) {
 		if err != nil {
 			t.Skip(err)
 		}
+		skipUnsupportedRevision(t, state.Revision, testeeName)
 
 		result, err := testee.StepN(state, 1)
 		if err != nil {
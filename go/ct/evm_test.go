@@ -89,6 +89,7 @@ func TestCt_ExplicitCases(t *testing.T) {
 
 			for name, evm := range evms {
 				t.Run(name, func(t *testing.T) {
+					skipUnsupportedRevision(t, input.Revision, name)
 					res, err := evm.StepN(input.Clone(), 1)
 					if err != nil {
 						t.Fatalf("failed to run test case: %v", err)
@@ -104,3 +105,19 @@ func TestCt_ExplicitCases(t *testing.T) {
 		})
 	}
 }
+
+// skipUnsupportedRevision skips the current test if any of the given
+// interpreters does not support the given revision, according to the
+// information they are registered with.
+func skipUnsupportedRevision(t *testing.T, revision tosca.Revision, interpreters ...string) {
+	t.Helper()
+	for _, name := range interpreters {
+		info, found := tosca.GetInterpreterInfo(name)
+		if !found {
+			t.Fatalf("interpreter %s is not registered", name)
+		}
+		if !info.SupportsRevision(revision) {
+			t.Skipf("interpreter %s does not support revision %v", name, revision)
+		}
+	}
+}
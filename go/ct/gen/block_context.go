@@ -160,6 +160,20 @@ func (b *BlockContextGenerator) bindVariablesInRangeConstraints(resultingBlockNu
 					return err
 				}
 				assignment[variable] = NewU256(blockNumber)
+			} else if resultingBlockNumber > 256 && rnd.Intn(2) == 0 {
+				// Since Prague (EIP-2935), hashes of blocks older than the last
+				// 256 blocks are retained by the history storage contract and
+				// are available to the context, making them an interesting
+				// corner case for BLOCKHASH.
+				lowerBound := uint64(0)
+				if resultingBlockNumber > tosca.HistoryServeWindow {
+					lowerBound = resultingBlockNumber - tosca.HistoryServeWindow
+				}
+				number, err := NewRangeSolver(lowerBound, resultingBlockNumber-257).Generate(rnd)
+				if err != nil {
+					return err
+				}
+				assignment[variable] = NewU256(number)
 			} else {
 				numberOutOfRangeGenerator := NewIntervalSolver[uint64](0, math.MaxUint64)
 				numberOutOfRangeGenerator.Exclude(resultingBlockNumber-256, resultingBlockNumber-1)
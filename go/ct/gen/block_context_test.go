@@ -349,6 +349,33 @@ func TestBlockContextGenerator_CanProduceSatisfyingBlockNumbersForConstraints(t
 	}
 }
 
+func TestBlockContextGenerator_OutOfRangeVariablesCoverHistoryServeWindow(t *testing.T) {
+	rnd := rand.New(0)
+	covered := false
+	for i := 0; i < 100 && !covered; i++ {
+		generator := NewBlockContextGenerator()
+		generator.SetRevision(tosca.R14_Prague)
+		generator.RestrictVariableToNoneOfTheLast256Blocks("a")
+		assignment := Assignment{}
+		context, err := generator.Generate(assignment, rnd)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		value := assignment["a"]
+		if !value.IsUint64() || value.Uint64() >= context.BlockNumber {
+			continue
+		}
+		offset := context.BlockNumber - value.Uint64()
+		if offset <= 256 {
+			t.Fatalf("variable is in range of the last 256 blocks, block number %d, value %d", context.BlockNumber, value.Uint64())
+		}
+		covered = offset <= tosca.HistoryServeWindow
+	}
+	if !covered {
+		t.Errorf("no block within the history serve window was generated")
+	}
+}
+
 func TestBlockContextGenerator_SignalsUnsatisfiableForUnsatisfiableConstraints(t *testing.T) {
 	// TODO: add support for pre-assigned values
 	tests := map[string]func(*BlockContextGenerator, Assignment){
@@ -578,14 +578,14 @@ func TestCondition_IsRevisionCondition(t *testing.T) {
 
 func TestCondition_GetTestValues(t *testing.T) {
 
-	inOutofRangeTestValues := []any{math.MinInt64, -1, 0, 1, 255, 256, 257, math.MaxInt64}
+	inOutofRangeTestValues := []any{math.MinInt64, -1, 0, 1, 255, 256, 257, tosca.HistoryServeWindow, tosca.HistoryServeWindow + 1, math.MaxInt64}
 
 	tests := []struct {
 		condition Condition
 		want      []any
 	}{
 		{IsRevision(tosca.R10_London), []any{tosca.R10_London}},
-		{AnyKnownRevision(), []any{tosca.R07_Istanbul, tosca.R09_Berlin, tosca.R10_London, tosca.R11_Paris, tosca.R12_Shanghai, tosca.R13_Cancun, R99_UnknownNextRevision}},
+		{AnyKnownRevision(), []any{tosca.R07_Istanbul, tosca.R09_Berlin, tosca.R10_London, tosca.R11_Paris, tosca.R12_Shanghai, tosca.R13_Cancun, tosca.R14_Prague, R99_UnknownNextRevision}},
 		{InRange256FromCurrentBlock(Param(0)), inOutofRangeTestValues},
 		{OutOfRange256FromCurrentBlock(Param(0)), inOutofRangeTestValues},
 		{IsCode(Param(0)), []any{true, false}},
@@ -395,7 +395,11 @@ func (d BlockNumberOffsetDomain) Samples(a int64) []int64 {
 }
 
 func (BlockNumberOffsetDomain) SamplesForAll(as []int64) []int64 {
-	res := []int64{math.MinInt64, -1, 0, 1, 255, 256, 257, math.MaxInt64}
+	res := []int64{
+		math.MinInt64, -1, 0, 1, 255, 256, 257,
+		tosca.HistoryServeWindow, tosca.HistoryServeWindow + 1,
+		math.MaxInt64,
+	}
 
 	// Test every element off by one.
 	for _, a := range as {
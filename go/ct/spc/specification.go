@@ -1042,6 +1042,27 @@ func getAllRules() []Rule {
 		pops:      1,
 		pushes:    1,
 		conditions: []Condition{
+			RevisionBounds(tosca.R07_Istanbul, tosca.R13_Cancun),
+			OutOfRange256FromCurrentBlock(Param(0)),
+		},
+		parameters: []Parameter{NumericParameter{}},
+		effect: func(s *st.State) {
+			s.Stack.Pop()
+			s.Stack.Push(NewU256(0))
+		},
+	})...)
+
+	// Since Prague (EIP-2935), the history storage contract retains the hashes
+	// of older blocks, which are available to the context. Nevertheless,
+	// BLOCKHASH only serves the 256 most recent blocks.
+	rules = append(rules, rulesFor(instruction{
+		op:        vm.BLOCKHASH,
+		name:      "_out_of_range_with_history_storage",
+		staticGas: 20,
+		pops:      1,
+		pushes:    1,
+		conditions: []Condition{
+			RevisionBounds(tosca.R14_Prague, NewestSupportedRevision),
 			OutOfRange256FromCurrentBlock(Param(0)),
 		},
 		parameters: []Parameter{NumericParameter{}},
@@ -2555,8 +2576,22 @@ func callEffect(s *st.State, addrAccessCost tosca.Gas, op vm.OpCode) {
 		valueToEmptyAccountCost = 25000
 	}
 
+	// Since Prague (EIP-7702), calls of accounts delegating their code
+	// execution also pay for accessing the delegation target.
+	delegationAccessCost := tosca.Gas(0)
+	delegate, delegated := tosca.Address{}, false
+	if s.Revision >= tosca.R14_Prague {
+		delegate, delegated = tosca.GetDelegationTarget(s.Accounts.GetCode(target.Bytes20be()).ToBytes())
+		if delegated {
+			delegationAccessCost = 2600
+			if delegate == target.Bytes20be() || s.Accounts.IsWarm(delegate) {
+				delegationAccessCost = 100
+			}
+		}
+	}
+
 	// Deduct the gas costs for this call, except the costs for the recursive call.
-	dynamicGas, overflow := sumWithOverflow(memoryExpansionCost, positiveValueCost, valueToEmptyAccountCost, addrAccessCost)
+	dynamicGas, overflow := sumWithOverflow(memoryExpansionCost, positiveValueCost, valueToEmptyAccountCost, addrAccessCost, delegationAccessCost)
 	if s.Gas < dynamicGas || overflow {
 		s.Status = st.Failed
 		return
@@ -2566,6 +2601,9 @@ func callEffect(s *st.State, addrAccessCost tosca.Gas, op vm.OpCode) {
 	if s.Revision >= tosca.R09_Berlin {
 		s.Accounts.MarkWarm(target.Bytes20be())
 	}
+	if delegated {
+		s.Accounts.MarkWarm(delegate)
+	}
 
 	// Grow the memory for which gas has just been deducted.
 	s.Memory.Grow(argsOffset64, argsSize64)
@@ -442,3 +442,54 @@ func TestSpecification_OpsWithDynamicCostHandleOverflowSizes(t *testing.T) {
 		}
 	}
 }
+
+func TestCallEffect_ChargesForAccessOfDelegationTargetSincePrague(t *testing.T) {
+	target := tosca.Address{1}
+	delegate := tosca.Address{2}
+	code := common.NewBytes(tosca.NewDelegationDesignator(delegate))
+
+	tests := map[string]struct {
+		revision tosca.Revision
+		warm     bool
+		want     tosca.Gas
+	}{
+		"cancun": {revision: tosca.R13_Cancun, want: 0},
+		"cold":   {revision: tosca.R14_Prague, want: 2600},
+		"warm":   {revision: tosca.R14_Prague, warm: true, want: 100},
+	}
+
+	for _, op := range []vm.OpCode{vm.CALL, vm.CALLCODE, vm.DELEGATECALL, vm.STATICCALL} {
+		for name, test := range tests {
+			t.Run(fmt.Sprintf("%v_%s", op, name), func(t *testing.T) {
+				builder := st.NewAccountsBuilder().SetCode(target, code)
+				if test.warm {
+					builder.SetWarm(delegate)
+				}
+
+				state := st.NewState(st.NewCode([]byte{byte(op)}))
+				defer state.Release()
+				state.Revision = test.revision
+				state.Gas = 10_000
+				state.Accounts = builder.Build()
+				zero := common.NewU256(0)
+				if op == vm.CALL || op == vm.CALLCODE {
+					state.Stack = st.NewStack(zero, zero, zero, zero, zero, common.NewU256FromBytes(target[:]...), zero)
+				} else {
+					state.Stack = st.NewStack(zero, zero, zero, zero, common.NewU256FromBytes(target[:]...), zero)
+				}
+
+				callEffect(state, 0, op)
+
+				if want, got := st.Running, state.Status; want != got {
+					t.Fatalf("unexpected status, wanted %v, got %v", want, got)
+				}
+				if want, got := test.want, 10_000-state.Gas; want != got {
+					t.Errorf("unexpected gas costs, wanted %d, got %d", want, got)
+				}
+				if want, got := test.revision >= tosca.R14_Prague, state.Accounts.IsWarm(delegate); want != got {
+					t.Errorf("unexpected warm state of delegation target, wanted %t, got %t", want, got)
+				}
+			})
+		}
+	}
+}
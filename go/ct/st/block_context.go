@@ -11,6 +11,7 @@
 package st
 
 import (
+	"encoding/binary"
 	"fmt"
 
 	. "github.com/Fantom-foundation/Tosca/go/ct/common"
@@ -89,3 +90,14 @@ func (b *BlockContext) String() string {
 		b.BaseFee, b.BlobBaseFee, b.BlockNumber, b.ChainID, b.CoinBase, b.GasLimit, b.GasPrice,
 		b.PrevRandao, b.TimeStamp)
 }
+
+// HistoricalBlockHash returns the hash the CT model assumes for the block with
+// the given number if it is older than the 256 most recent blocks covered by
+// the RecentBlockHashes of a state. Since Prague (EIP-2935), the history
+// storage contract retains the hashes of such blocks, which are available to
+// the context of an execution but must not be served by BLOCKHASH.
+func HistoricalBlockHash(number uint64) tosca.Hash {
+	hash := tosca.Hash{0xff}
+	binary.BigEndian.PutUint64(hash[24:], number)
+	return hash
+}
@@ -130,7 +130,15 @@ func (c *ctRunContext) GetBlockHash(number int64) tosca.Hash {
 	if max > 256 {
 		min = max - 256
 	}
-	if min > number || number >= max {
+	if number >= max || number < 0 {
+		return tosca.Hash{0x0}
+	}
+	if min > number {
+		// Since Prague, the history storage contract provides the hashes of
+		// older blocks, which are thus available to the context.
+		if c.state.Revision >= tosca.R14_Prague && max-number <= tosca.HistoryServeWindow {
+			return st.HistoricalBlockHash(uint64(number))
+		}
 		return tosca.Hash{0x0}
 	}
 	return c.state.RecentBlockHashes.Get(uint64(max - number - 1))
@@ -216,6 +224,10 @@ func (c *ctRunContext) CreateAccount(tosca.Address, tosca.Code) bool {
 	panic("should not be needed")
 }
 
+func (c *ctRunContext) CreateContract(tosca.Address) {
+	panic("should not be needed")
+}
+
 func (c *ctRunContext) SetNonce(tosca.Address, uint64) {
 	panic("should not be needed")
 }
@@ -344,3 +344,34 @@ func TestAdapter_ParameterConversion(t *testing.T) {
 		})
 	}
 }
+
+func TestCtRunContext_GetBlockHashServesHistoricalHashesSincePrague(t *testing.T) {
+	recent := tosca.Hash{1}
+	current := uint64(10_000)
+
+	tests := map[string]struct {
+		revision tosca.Revision
+		number   int64
+		want     tosca.Hash
+	}{
+		"recent block":                    {tosca.R13_Cancun, int64(current - 1), recent},
+		"current block":                   {tosca.R14_Prague, int64(current), tosca.Hash{}},
+		"old block before Prague":         {tosca.R13_Cancun, int64(current - 257), tosca.Hash{}},
+		"old block since Prague":          {tosca.R14_Prague, int64(current - 257), st.HistoricalBlockHash(current - 257)},
+		"oldest historical block":         {tosca.R14_Prague, int64(current - tosca.HistoryServeWindow), st.HistoricalBlockHash(current - tosca.HistoryServeWindow)},
+		"block beyond the history window": {tosca.R14_Prague, int64(current-tosca.HistoryServeWindow) - 1, tosca.Hash{}},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			state := st.NewState(st.NewCode([]byte{}))
+			state.Revision = test.revision
+			state.BlockContext.BlockNumber = current
+			state.RecentBlockHashes = cc.NewImmutableHashArray(recent)
+			context := &ctRunContext{state: state}
+			if want, got := test.want, context.GetBlockHash(test.number); want != got {
+				t.Errorf("unexpected hash, wanted %v, got %v", want, got)
+			}
+		})
+	}
+}
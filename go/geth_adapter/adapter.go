@@ -187,15 +187,40 @@ func (a *gethInterpreterAdapter) Run(contract *geth.Contract, input []byte, read
 	// The only two types that need to be differentiated are revert
 	// errors (in which gas is accounted for accurately) and any
 	// other error.
-	if (result.GasLeft > 0 || len(result.Output) > 0) && !result.Success {
-		return result.Output, geth.ErrExecutionReverted
+	if result.Success {
+		return result.Output, nil
 	}
-	if !result.Success {
+	switch result.FailureCause {
+	case tosca.FailureRevert:
+		return result.Output, geth.ErrExecutionReverted
+	case tosca.FailureNone:
+		if result.GasLeft > 0 || len(result.Output) > 0 {
+			return result.Output, geth.ErrExecutionReverted
+		}
 		return nil, geth.ErrOutOfGas // < they are all handled equally
+	case tosca.FailureOutOfGas:
+		return nil, geth.ErrOutOfGas
+	// Other failures are handled like running out of gas by geth, the
+	// matching geth errors retain the cause for diagnostic purposes only.
+	case tosca.FailureInvalidOpCode:
+		return nil, &geth.ErrInvalidOpCode{}
+	case tosca.FailureInvalidJump:
+		return nil, geth.ErrInvalidJump
+	case tosca.FailureStackViolation:
+		return nil, &geth.ErrStackUnderflow{}
+	case tosca.FailureStaticViolation:
+		return nil, geth.ErrWriteProtection
+	case tosca.FailureCodeSizeLimit:
+		return nil, geth.ErrMaxCodeSizeExceeded
+	default:
+		return nil, errExecutionFailed
 	}
-	return result.Output, nil
 }
 
+// errExecutionFailed is reported to geth for unsuccessful executions of Tosca
+// interpreters whose cause has no counterpart among geth's errors.
+const errExecutionFailed = tosca.ConstError("execution failed")
+
 func getPrevRandao(context *geth.BlockContext, revision tosca.Revision) (tosca.Hash, error) {
 	if revision < tosca.R11_Paris {
 		prevRandao, err := bigIntToHash(context.Difficulty)
@@ -241,7 +266,9 @@ func undoRefundShift(stateDB geth.StateDB, err error, refundShift uint64) {
 }
 
 func convertRevision(rules params.Rules) (tosca.Revision, error) {
-	if rules.IsCancun {
+	if rules.IsPrague {
+		return tosca.R14_Prague, nil
+	} else if rules.IsCancun {
 		return tosca.R13_Cancun, nil
 	} else if rules.IsShanghai {
 		return tosca.R12_Shanghai, nil
@@ -391,6 +418,9 @@ func gethToVMErrors(err error, gas tosca.Gas) (tosca.CallResult, error) {
 	if _, ok := err.(*geth.ErrInvalidOpCode); ok {
 		return tosca.CallResult{Success: false}, nil
 	}
+	if err == errExecutionFailed {
+		return tosca.CallResult{Success: false}, nil
+	}
 
 	return tosca.CallResult{Success: false}, err
 }
@@ -433,6 +463,10 @@ func (a *runContextAdapter) CreateAccount(addr tosca.Address, code tosca.Code) b
 	return true
 }
 
+func (a *runContextAdapter) CreateContract(addr tosca.Address) {
+	a.evm.StateDB.CreateContract(gc.Address(addr))
+}
+
 func (a *runContextAdapter) GetNonce(addr tosca.Address) uint64 {
 	return a.evm.StateDB.GetNonce(gc.Address(addr))
 }
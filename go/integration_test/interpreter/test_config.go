@@ -44,12 +44,15 @@ func getAllInterpreterVariantsForTests() []string {
 }
 
 // skipTestForVariant returns true, if the given test should be skipped for
-// the given variant.
+// the given variant. Tests are disabled for all variants of an implementation,
+// identified through the native library registered for them.
 func skipTestForVariant(testName string, variant string) bool {
 	disabledTest := map[string][]string{
-		"TestNoReturnDataForCreate": {
-			"evmone", "evmone-basic", "evmone-advanced",
-		},
+		"TestNoReturnDataForCreate": {"libevmone.so"},
 	}
-	return slices.Contains(disabledTest[testName], variant)
+	info, found := tosca.GetInterpreterInfo(variant)
+	if !found || info.NativeDependency == "" {
+		return false
+	}
+	return slices.Contains(disabledTest[testName], info.NativeDependency)
 }
@@ -13,6 +13,8 @@ package interpreter_test
 import (
 	"slices"
 	"testing"
+
+	"github.com/Fantom-foundation/Tosca/go/tosca"
 )
 
 func TestCoveredVariants_ContainsMainConfigurations(t *testing.T) {
@@ -26,3 +28,16 @@ func TestCoveredVariants_ContainsMainConfigurations(t *testing.T) {
 		}
 	}
 }
+
+func TestCoveredVariants_ProvideCapabilityInformation(t *testing.T) {
+	for _, variant := range getAllInterpreterVariantsForTests() {
+		info, found := tosca.GetInterpreterInfo(variant)
+		if !found {
+			t.Errorf("no info registered for variant %q", variant)
+			continue
+		}
+		if !info.SupportsRevision(tosca.R13_Cancun) {
+			t.Errorf("variant %q does not support Cancun, got %v", variant, info)
+		}
+	}
+}
@@ -11,6 +11,7 @@
 package interpreter_test
 
 import (
+	"encoding/json"
 	"fmt"
 	"testing"
 
@@ -218,7 +219,13 @@ func benchmark(b *testing.B, example examples.Example, arg int) {
 			}
 		})
 		if pvm, ok := evm.(tosca.ProfilingInterpreter); active && ok {
-			pvm.DumpProfile()
+			if profile := pvm.GetProfile(); profile.Steps > 0 {
+				data, err := json.Marshal(profile)
+				if err != nil {
+					b.Fatalf("failed to encode profile of %s: %v", variant, err)
+				}
+				fmt.Printf("%s\n", data)
+			}
 		}
 	}
 }
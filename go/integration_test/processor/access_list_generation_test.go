@@ -0,0 +1,93 @@
+// Copyright (c) 2024 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package processor
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Fantom-foundation/Tosca/go/tosca"
+	"github.com/Fantom-foundation/Tosca/go/tosca/vm"
+)
+
+func TestCreateAccessList_ListsAccessesOfContract(t *testing.T) {
+	sender := tosca.Address{1}
+	contract := tosca.Address{2}
+	other := tosca.Address{3}
+
+	code := []byte{
+		byte(vm.PUSH1), 5,
+		byte(vm.SLOAD),
+		byte(vm.POP),
+		byte(vm.PUSH20),
+	}
+	code = append(code, other[:]...)
+	code = append(code,
+		byte(vm.BALANCE),
+		byte(vm.POP),
+		byte(vm.PUSH1), 0, // < result size
+		byte(vm.DUP1),     // < result offset
+		byte(vm.DUP1),     // < argument size
+		byte(vm.DUP1),     // < argument offset
+		byte(vm.PUSH1), 1, // < precompiled contract, excluded from the access list
+		byte(vm.GAS),
+		byte(vm.STATICCALL),
+		byte(vm.POP),
+		byte(vm.STOP),
+	)
+
+	accounts := map[tosca.Address]tosca.Account{
+		sender:   {Balance: tosca.NewValue(1_000_000_000)},
+		contract: {Code: code},
+	}
+	transaction := tosca.Transaction{
+		Sender:    sender,
+		Recipient: &contract,
+		GasLimit:  100_000,
+		GasPrice:  tosca.NewValue(1),
+	}
+	block := tosca.BlockParameters{GasLimit: 1_000_000, Revision: tosca.R13_Cancun}
+	precompiles := []tosca.Address{}
+	for i := range 10 {
+		precompiles = append(precompiles, tosca.Address{19: byte(i + 1)})
+	}
+
+	for processorName, processor := range getProcessors() {
+		t.Run(processorName, func(t *testing.T) {
+			context := tosca.NewInMemoryTransactionContext(tosca.R13_Cancun, accounts)
+			result, err := tosca.CreateAccessList(processor, block, transaction, context, precompiles)
+			if err != nil {
+				t.Fatalf("failed to create access list: %v", err)
+			}
+
+			want := []tosca.AccessTuple{
+				{Address: contract, Keys: []tosca.Key{{31: 5}}},
+				{Address: other, Keys: []tosca.Key{}},
+			}
+			if got := result.AccessList; !reflect.DeepEqual(want, got) {
+				t.Errorf("unexpected access list, wanted %v, got %v", want, got)
+			}
+
+			transaction := transaction
+			transaction.AccessList = result.AccessList
+			receipt, err := processor.Run(block, transaction, tosca.NewInMemoryTransactionContext(tosca.R13_Cancun, accounts))
+			if err != nil || !receipt.Success {
+				t.Fatalf("failed to run transaction with access list, receipt %v, error %v", receipt, err)
+			}
+			if want, got := receipt.GasUsed, result.GasUsed; want != got {
+				t.Errorf("unexpected gas used, wanted %d, got %d", want, got)
+			}
+			if result.GasUsed == result.GasUsedWithoutAccessList {
+				t.Errorf("access list has no effect on gas used")
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright (c) 2024 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package processor
+
+import (
+	"testing"
+
+	"github.com/Fantom-foundation/Tosca/go/tosca"
+	"github.com/Fantom-foundation/Tosca/go/tosca/vm"
+)
+
+// blockHashCode returns the hash of the block with the given number.
+func blockHashCode(number byte) tosca.Code {
+	return tosca.Code{
+		byte(vm.PUSH1), number,
+		byte(vm.BLOCKHASH),
+		byte(vm.PUSH1), 0,
+		byte(vm.MSTORE),
+		byte(vm.PUSH1), 32,
+		byte(vm.PUSH1), 0,
+		byte(vm.RETURN),
+	}
+}
+
+func TestProcessor_BlockHashIsServedFromHistoryStorageSincePrague(t *testing.T) {
+	sender := tosca.Address{1}
+	recipient := tosca.Address{2}
+	hash := tosca.Word{0x41}
+
+	// The scenario context does not provide block hashes itself.
+	state := WorldState{
+		recipient: Account{Code: blockHashCode(41)},
+		tosca.HistoryStorageAddress: Account{
+			Storage: Storage{tosca.Key{31: 41}: hash},
+		},
+	}
+	transaction := tosca.Transaction{
+		Sender:    sender,
+		Recipient: &recipient,
+		GasLimit:  100_000,
+	}
+	block := tosca.BlockParameters{BlockNumber: 42, Revision: tosca.R14_Prague}
+
+	for processorName, processor := range getProcessorsSupporting(block.Revision) {
+		t.Run(processorName, func(t *testing.T) {
+			receipt, err := processor.Run(block, transaction, newScenarioContext(state))
+			if err != nil || !receipt.Success {
+				t.Fatalf("failed to run transaction, receipt %v, error %v", receipt, err)
+			}
+			if want, got := hash, tosca.Word(receipt.Output); want != got {
+				t.Errorf("unexpected block hash, wanted %v, got %v", want, got)
+			}
+		})
+	}
+}
+
+func TestBlockExecutor_RecordedParentHashIsServedToTransactionsSincePrague(t *testing.T) {
+	sender := tosca.Address{1}
+	recipient := tosca.Address{2}
+	hash := tosca.Hash{0x41}
+
+	for processorName, processor := range getProcessorsSupporting(tosca.R14_Prague) {
+		t.Run(processorName, func(t *testing.T) {
+			context := tosca.NewInMemoryTransactionContext(tosca.R14_Prague, map[tosca.Address]tosca.Account{
+				recipient:                   {Code: blockHashCode(41)},
+				tosca.HistoryStorageAddress: {Code: tosca.Code{byte(vm.STOP)}},
+			})
+			context.SetBlockHash(41, hash)
+			block := tosca.BlockParameters{
+				BlockNumber: 42,
+				GasLimit:    100_000,
+				Revision:    tosca.R14_Prague,
+			}
+			transactions := []tosca.Transaction{{
+				Sender:    sender,
+				Recipient: &recipient,
+				GasLimit:  100_000,
+			}}
+
+			result := tosca.NewBlockExecutor(processor).Run(block, transactions, context)
+
+			if want, got := 1, len(result.Receipts); want != got {
+				t.Fatalf("unexpected number of receipts, wanted %d, got %d, skipped %v", want, got, result.Skipped)
+			}
+			if want, got := tosca.Word(hash), context.GetStorage(tosca.HistoryStorageAddress, tosca.Key{31: 41}); want != got {
+				t.Errorf("parent hash was not recorded, wanted %v, got %v", want, got)
+			}
+			if want, got := hash, tosca.Hash(result.Receipts[0].Output); want != got {
+				t.Errorf("unexpected block hash, wanted %v, got %v", want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2024 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package processor
+
+import (
+	"testing"
+
+	"github.com/Fantom-foundation/Tosca/go/tosca"
+)
+
+const txGas = 21_000
+
+func TestBlockExecutor_ProcessesBlocksOfValueTransfers(t *testing.T) {
+	for processorName, processor := range getProcessors() {
+		t.Run(processorName, func(t *testing.T) {
+			sender := tosca.Address{1}
+			receiver := tosca.Address{2}
+			coinbase := tosca.Address{3}
+
+			context := tosca.NewInMemoryTransactionContext(tosca.R13_Cancun, map[tosca.Address]tosca.Account{
+				sender: {Balance: tosca.NewValue(1_000_000_000)},
+			})
+			block := tosca.BlockParameters{
+				GasLimit: 3 * txGas,
+				Coinbase: coinbase,
+				BaseFee:  tosca.NewValue(1),
+				Revision: tosca.R13_Cancun,
+			}
+			transactions := []tosca.Transaction{}
+			for i := range 3 {
+				transactions = append(transactions, tosca.Transaction{
+					Sender:    sender,
+					Recipient: &receiver,
+					Nonce:     uint64(i),
+					GasLimit:  txGas,
+					GasPrice:  tosca.NewValue(3),
+					Value:     tosca.NewValue(10),
+				})
+			}
+
+			result := tosca.NewBlockExecutor(processor).Run(block, transactions, context)
+
+			if want, got := 3, len(result.Receipts); want != got {
+				t.Fatalf("unexpected number of receipts, wanted %d, got %d, skipped %v", want, got, result.Skipped)
+			}
+			for i, receipt := range result.Receipts {
+				if !receipt.Success {
+					t.Errorf("transaction %d failed", i)
+				}
+				if want, got := tosca.Gas(i+1)*txGas, receipt.CumulativeGasUsed; want != got {
+					t.Errorf("unexpected cumulative gas used, wanted %d, got %d", want, got)
+				}
+			}
+			if want, got := tosca.NewValue(30), context.GetBalance(receiver); want != got {
+				t.Errorf("unexpected receiver balance, wanted %v, got %v", want, got)
+			}
+			if want, got := tosca.NewValue(2*3*txGas), context.GetBalance(coinbase); want != got {
+				t.Errorf("unexpected coinbase balance, wanted %v, got %v", want, got)
+			}
+			if want, got := tosca.NewValue(1_000_000_000-30-3*3*txGas), context.GetBalance(sender); want != got {
+				t.Errorf("unexpected sender balance, wanted %v, got %v", want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright (c) 2024 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package processor
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/Fantom-foundation/Tosca/go/tosca"
+	"github.com/Fantom-foundation/Tosca/go/tosca/vm"
+)
+
+func TestCallTracer_RecordsNestedCalls(t *testing.T) {
+	sender := tosca.Address{1}
+	caller := tosca.Address{2}
+	reverting := tosca.Address{3}
+	library := tosca.Address{4}
+
+	// The caller calls a reverting contract and delegates to a library.
+	callerCode := pushToStack([]*big.Int{
+		new(big.Int).SetBytes(reverting[:]),
+		big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0),
+	})
+	callerCode = append(callerCode, byte(vm.GAS), byte(vm.CALL), byte(vm.POP))
+	callerCode = append(callerCode, pushToStack([]*big.Int{
+		new(big.Int).SetBytes(library[:]),
+		big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0),
+	})...)
+	callerCode = append(callerCode, byte(vm.GAS), byte(vm.DELEGATECALL), byte(vm.POP), byte(vm.STOP))
+
+	// The reverting contract reverts with Error("nope").
+	reason := make([]byte, 128)
+	copy(reason, []byte{0x08, 0xc3, 0x79, 0xa0})
+	reason[4+31] = 32
+	reason[4+63] = 4
+	copy(reason[4+64:], "nope")
+	revertingCode := []byte{}
+	for i := 0; i < len(reason); i += 32 {
+		revertingCode = append(revertingCode, byte(vm.PUSH32))
+		revertingCode = append(revertingCode, reason[i:i+32]...)
+		revertingCode = append(revertingCode, byte(vm.PUSH1), byte(i), byte(vm.MSTORE))
+	}
+	revertingCode = append(revertingCode,
+		byte(vm.PUSH1), byte(4+3*32),
+		byte(vm.PUSH1), byte(0),
+		byte(vm.REVERT),
+	)
+
+	state := WorldState{
+		sender:    Account{Balance: tosca.NewValue(1_000_000)},
+		caller:    Account{Code: callerCode},
+		reverting: Account{Code: revertingCode},
+		library:   Account{Code: []byte{byte(vm.STOP)}},
+	}
+	transaction := tosca.Transaction{
+		Sender:    sender,
+		Recipient: &caller,
+		GasLimit:  200_000,
+	}
+
+	for processorName, processor := range getProcessors() {
+		t.Run(processorName, func(t *testing.T) {
+			tracer := tosca.NewCallTracer()
+			context := &tracingScenarioContext{newScenarioContext(state), tracer}
+			receipt, err := processor.Run(tosca.BlockParameters{Revision: tosca.R13_Cancun}, transaction, context)
+			if err != nil || !receipt.Success {
+				t.Fatalf("failed to run transaction, receipt %v, error %v", receipt, err)
+			}
+
+			root := tracer.GetResult()
+			if root == nil {
+				t.Fatalf("no call frames recorded")
+			}
+			if root.Kind != tosca.Call || root.From != sender || root.To == nil || *root.To != caller {
+				t.Errorf("unexpected root frame: %+v", root)
+			}
+			if want, got := 2, len(root.Calls); want != got {
+				t.Fatalf("unexpected number of nested calls, wanted %d, got %d", want, got)
+			}
+
+			call := root.Calls[0]
+			if call.Kind != tosca.Call || call.From != caller || call.To == nil || *call.To != reverting {
+				t.Errorf("unexpected call frame: %+v", call)
+			}
+			if want, got := "execution reverted", call.Error; want != got {
+				t.Errorf("unexpected error, wanted %q, got %q", want, got)
+			}
+			if want, got := "nope", call.RevertReason; want != got {
+				t.Errorf("unexpected revert reason, wanted %q, got %q", want, got)
+			}
+
+			delegate := root.Calls[1]
+			if delegate.Kind != tosca.DelegateCall || delegate.From != caller || delegate.To == nil || *delegate.To != library {
+				t.Errorf("unexpected delegate call frame: %+v", delegate)
+			}
+			if delegate.Error != "" {
+				t.Errorf("unexpected error in delegate call: %v", delegate.Error)
+			}
+
+			if _, err := json.Marshal(root); err != nil {
+				t.Errorf("failed to encode call frames: %v", err)
+			}
+		})
+	}
+}
@@ -12,6 +12,7 @@ package processor
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"math/big"
 	"slices"
@@ -719,6 +720,118 @@ func TestProcessor_CodeSizeIsLimited(t *testing.T) {
 	}
 }
 
+func TestProcessor_InitCodeSizeIsLimitedSinceShanghai(t *testing.T) {
+	maxInitCodeSize := 2 * 24576
+	tests := map[string]struct {
+		revision tosca.Revision
+		length   int
+		want     error
+	}{
+		"threshold": {
+			revision: tosca.R12_Shanghai,
+			length:   maxInitCodeSize,
+		},
+		"exceedingThreshold": {
+			revision: tosca.R12_Shanghai,
+			length:   maxInitCodeSize + 1,
+			want:     tosca.ErrMaxInitCodeSizeExceeded,
+		},
+		"exceedingThresholdBeforeShanghai": {
+			revision: tosca.R11_Paris,
+			length:   maxInitCodeSize + 1,
+		},
+	}
+
+	for processorName, processor := range getProcessors() {
+		for testName, test := range tests {
+			t.Run(processorName+"/"+testName, func(t *testing.T) {
+				sender := tosca.Address{1}
+				state := WorldState{sender: Account{}}
+				transaction := tosca.Transaction{
+					Sender:   sender,
+					GasLimit: sufficientGas,
+					Input:    make([]byte, test.length), // < STOP instructions only
+				}
+
+				blockParameters := tosca.BlockParameters{Revision: test.revision}
+				transactionContext := newScenarioContext(state)
+
+				result, err := processor.Run(blockParameters, transaction, transactionContext)
+				if !errors.Is(err, test.want) {
+					t.Fatalf("unexpected error, wanted %v, got %v", test.want, err)
+				}
+				if test.want != nil {
+					if !state.Equal(transactionContext.current) {
+						t.Errorf("rejected transaction modified the world state")
+					}
+					return
+				}
+				if !result.Success {
+					t.Errorf("execution was not successful")
+				}
+			})
+		}
+	}
+}
+
+func TestProcessor_InitCodeIsChargedPerWordSinceShanghai(t *testing.T) {
+	// 64 bytes of init code are charged with 53000 + 64*4 gas, and since
+	// Shanghai with additional 2*2 gas for its two words.
+	input := make([]byte, 64) // < STOP instructions only
+	tests := map[string]struct {
+		revision tosca.Revision
+		gasLimit tosca.Gas
+		gasUsed  tosca.Gas
+		want     error
+	}{
+		"beforeShanghai": {
+			revision: tosca.R11_Paris,
+			gasLimit: 53_256,
+			gasUsed:  53_256,
+		},
+		"sinceShanghai": {
+			revision: tosca.R12_Shanghai,
+			gasLimit: 53_260,
+			gasUsed:  53_260,
+		},
+		"sinceShanghaiWithoutGasForWords": {
+			revision: tosca.R12_Shanghai,
+			gasLimit: 53_259,
+			want:     tosca.ErrIntrinsicGas,
+		},
+	}
+
+	for processorName, processor := range getProcessors() {
+		for testName, test := range tests {
+			t.Run(processorName+"/"+testName, func(t *testing.T) {
+				sender := tosca.Address{1}
+				transaction := tosca.Transaction{
+					Sender:   sender,
+					GasLimit: test.gasLimit,
+					Input:    input,
+				}
+
+				blockParameters := tosca.BlockParameters{Revision: test.revision}
+				transactionContext := newScenarioContext(WorldState{sender: Account{}})
+
+				result, err := processor.Run(blockParameters, transaction, transactionContext)
+				if !errors.Is(err, test.want) {
+					t.Fatalf("unexpected error, wanted %v, got %v", test.want, err)
+				}
+				if test.want != nil {
+					return
+				}
+				if !result.Success {
+					t.Fatalf("execution was not successful")
+				}
+				if want, got := test.gasUsed, result.GasUsed; want != got {
+					t.Errorf("unexpected gas used, wanted %d, got %d", want, got)
+				}
+			})
+		}
+	}
+}
+
 func saveCodeFromAccountToMemory(account tosca.Address, length byte, offset byte) []byte {
 	addressPush := vm.PUSH1 + vm.OpCode(len(tosca.Address{0})-1)
 	code := []byte{}
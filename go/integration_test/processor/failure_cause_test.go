@@ -0,0 +1,119 @@
+// Copyright (c) 2024 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package processor
+
+import (
+	"testing"
+
+	"github.com/Fantom-foundation/Tosca/go/tosca"
+	"github.com/Fantom-foundation/Tosca/go/tosca/vm"
+)
+
+func TestProcessor_ReceiptsReportFailureCauses(t *testing.T) {
+	// Reverts with Error("nope").
+	reason := make([]byte, 128)
+	copy(reason, []byte{0x08, 0xc3, 0x79, 0xa0})
+	reason[4+31] = 32
+	reason[4+63] = 4
+	copy(reason[4+64:], "nope")
+	revertingCode := []byte{}
+	for i := 0; i < len(reason); i += 32 {
+		revertingCode = append(revertingCode, byte(vm.PUSH32))
+		revertingCode = append(revertingCode, reason[i:i+32]...)
+		revertingCode = append(revertingCode, byte(vm.PUSH1), byte(i), byte(vm.MSTORE))
+	}
+	revertingCode = append(revertingCode,
+		byte(vm.PUSH1), byte(4+3*32),
+		byte(vm.PUSH1), byte(0),
+		byte(vm.REVERT),
+	)
+
+	tests := map[string]struct {
+		code         []byte
+		recipient    tosca.Address
+		input        []byte
+		cause        tosca.FailureCause
+		revertReason string
+	}{
+		"success": {
+			code:  []byte{byte(vm.STOP)},
+			cause: tosca.FailureNone,
+		},
+		"revert": {
+			code:         revertingCode,
+			cause:        tosca.FailureRevert,
+			revertReason: "nope",
+		},
+		"out of gas": {
+			code:  []byte{byte(vm.JUMPDEST), byte(vm.PUSH1), 0, byte(vm.JUMP)},
+			cause: tosca.FailureOutOfGas,
+		},
+		"invalid opcode": {
+			code:  []byte{byte(vm.INVALID)},
+			cause: tosca.FailureInvalidOpCode,
+		},
+		"invalid jump": {
+			code:  []byte{byte(vm.PUSH1), 3, byte(vm.JUMP), byte(vm.STOP)},
+			cause: tosca.FailureInvalidJump,
+		},
+		"stack underflow": {
+			code:  []byte{byte(vm.ADD)},
+			cause: tosca.FailureStackViolation,
+		},
+		"precompile": {
+			// The point (1,1) is not on the curve of the bn256 precompile.
+			recipient: tosca.Address{19: 0x06},
+			input:     append(append(make([]byte, 31), 1), append(make([]byte, 31), 1)...),
+			cause:     tosca.FailurePrecompile,
+		},
+	}
+
+	sender := tosca.Address{1}
+	for name, test := range tests {
+		recipient := test.recipient
+		if recipient == (tosca.Address{}) {
+			recipient = tosca.Address{2}
+		}
+		state := WorldState{
+			sender:    Account{Balance: tosca.NewValue(1_000_000)},
+			recipient: Account{Code: test.code},
+		}
+		transaction := tosca.Transaction{
+			Sender:    sender,
+			Recipient: &recipient,
+			Input:     test.input,
+			GasLimit:  100_000,
+		}
+		for processorName, processor := range getProcessors() {
+			t.Run(name+"/"+processorName, func(t *testing.T) {
+				context := newScenarioContext(state)
+				receipt, err := processor.Run(tosca.BlockParameters{Revision: tosca.R13_Cancun}, transaction, context)
+				if err != nil {
+					t.Fatalf("failed to run transaction: %v", err)
+				}
+				if want, got := test.cause == tosca.FailureNone, receipt.Success; want != got {
+					t.Errorf("unexpected success, wanted %t, got %t", want, got)
+				}
+				if want, got := test.cause, receipt.FailureCause; want != got {
+					t.Errorf("unexpected failure cause, wanted %v, got %v", want, got)
+				}
+				if want, got := test.revertReason, receipt.RevertReason; want != got {
+					t.Errorf("unexpected revert reason, wanted %q, got %q", want, got)
+				}
+				if test.cause != tosca.FailureNone && test.cause != tosca.FailureRevert {
+					if want, got := transaction.GasLimit, receipt.GasUsed; want != got {
+						t.Errorf("failure did not consume all gas, wanted %d, got %d", want, got)
+					}
+				}
+			})
+		}
+	}
+}
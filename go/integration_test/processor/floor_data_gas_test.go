@@ -0,0 +1,136 @@
+// Copyright (c) 2024 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package processor
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/Fantom-foundation/Tosca/go/processor/floria"
+	"github.com/Fantom-foundation/Tosca/go/tosca"
+	"github.com/Fantom-foundation/Tosca/go/tosca/vm"
+)
+
+func TestProcessor_FloorDataGasIsChargedSincePrague(t *testing.T) {
+	sender := tosca.Address{1}
+	recipient := tosca.Address{2}
+
+	// The intrinsic gas of the transaction is 21000 + 100*16 = 22600, while
+	// its floor cost is 21000 + 100*4*10 = 25000.
+	input := bytes.Repeat([]byte{1}, 100)
+	intrinsicGas := tosca.Gas(floria.TxGas + 100*floria.TxDataNonZeroGasEIP2028)
+	floorGas := tosca.Gas(floria.TxGas + 100*floria.TxTokenPerNonZeroByte*floria.TxCostFloorPerToken)
+
+	transaction := tosca.Transaction{
+		Sender:    sender,
+		Recipient: &recipient,
+		Input:     input,
+		GasLimit:  floorGas,
+	}
+	// Without any code to be executed, only the 10% of the unused gas is
+	// charged in addition to the intrinsic gas.
+	unused := floorGas - intrinsicGas
+	gasUsedWithoutFloor := intrinsicGas + unused/10
+
+	tests := map[tosca.Revision]tosca.Gas{
+		tosca.R13_Cancun: gasUsedWithoutFloor,
+		tosca.R14_Prague: floorGas,
+	}
+
+	for revision, want := range tests {
+		for processorName, processor := range getProcessorsSupporting(revision) {
+			t.Run(fmt.Sprintf("%s/%v", processorName, revision), func(t *testing.T) {
+				block := tosca.BlockParameters{Revision: revision}
+				receipt, err := processor.Run(block, transaction, newScenarioContext(WorldState{}))
+				if err != nil || !receipt.Success {
+					t.Fatalf("failed to run transaction, receipt %v, error %v", receipt, err)
+				}
+				if got := receipt.GasUsed; want != got {
+					t.Errorf("unexpected gas used, wanted %d, got %d", want, got)
+				}
+			})
+		}
+	}
+}
+
+func TestProcessor_FloorDataGasIsNotChargedIfExceededByGasUsed(t *testing.T) {
+	sender := tosca.Address{1}
+	recipient := tosca.Address{2}
+
+	// The access of a cold account costs more than the floor cost of
+	// 21000 + 4*10 = 21040 exceeding the intrinsic gas of 21000 + 16.
+	code := tosca.Code{
+		byte(vm.PUSH1), 3,
+		byte(vm.BALANCE),
+		byte(vm.STOP),
+	}
+	state := WorldState{recipient: Account{Code: code}}
+	transaction := tosca.Transaction{
+		Sender:    sender,
+		Recipient: &recipient,
+		Input:     []byte{1},
+		GasLimit:  30_000,
+	}
+
+	for processorName, processor := range getProcessorsSupporting(tosca.R14_Prague) {
+		t.Run(processorName, func(t *testing.T) {
+			gasUsed := map[tosca.Revision]tosca.Gas{}
+			for _, revision := range []tosca.Revision{tosca.R13_Cancun, tosca.R14_Prague} {
+				block := tosca.BlockParameters{Revision: revision}
+				receipt, err := processor.Run(block, transaction, newScenarioContext(state))
+				if err != nil || !receipt.Success {
+					t.Fatalf("failed to run transaction in %v, receipt %v, error %v", revision, receipt, err)
+				}
+				gasUsed[revision] = receipt.GasUsed
+			}
+			if want, got := gasUsed[tosca.R13_Cancun], gasUsed[tosca.R14_Prague]; want != got {
+				t.Errorf("unexpected gas used, wanted %d, got %d", want, got)
+			}
+		})
+	}
+}
+
+func TestProcessor_TransactionsNotCoveringFloorDataGasAreRejected(t *testing.T) {
+	sender := tosca.Address{1}
+	recipient := tosca.Address{2}
+
+	// The gas limit covers the intrinsic gas of 21000 + 100*16 = 22600 but
+	// not the floor cost of 21000 + 100*4*10 = 25000.
+	transaction := tosca.Transaction{
+		Sender:    sender,
+		Recipient: &recipient,
+		Input:     bytes.Repeat([]byte{1}, 100),
+		GasLimit:  24_999,
+	}
+
+	tests := map[tosca.Revision]error{
+		tosca.R13_Cancun: nil,
+		tosca.R14_Prague: tosca.ErrFloorDataGas,
+	}
+
+	for revision, want := range tests {
+		for processorName, processor := range getProcessorsSupporting(revision) {
+			t.Run(fmt.Sprintf("%s/%v", processorName, revision), func(t *testing.T) {
+				state := WorldState{}
+				context := newScenarioContext(state)
+				block := tosca.BlockParameters{Revision: revision}
+				if _, err := processor.Run(block, transaction, context); !errors.Is(err, want) {
+					t.Fatalf("unexpected error, wanted %v, got %v", want, err)
+				}
+				if want != nil && !state.Equal(context.current) {
+					t.Errorf("rejected transaction modified the world state")
+				}
+			})
+		}
+	}
+}
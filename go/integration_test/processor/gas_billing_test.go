@@ -11,6 +11,7 @@
 package processor
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 
@@ -110,9 +111,122 @@ func processorsWithInterpreter(name string, interpreter tosca.Interpreter) map[s
 	factories := tosca.GetAllRegisteredProcessorFactories()
 	res := map[string]tosca.Processor{}
 	for processorName, factory := range factories {
-		processor := factory(interpreter)
+		processor, err := factory(interpreter, nil)
+		if err != nil {
+			panic(fmt.Sprintf("failed to create processor %s: %v", processorName, err))
+		}
 		res[fmt.Sprintf("%s/%s", processorName, name)] = processor
 	}
 
 	return res
 }
+
+func TestProcessor_DynamicFeeTransactionsAreBilledWithEffectiveGasPrice(t *testing.T) {
+	sender := tosca.Address{1}
+	recipient := tosca.Address{2}
+	senderBalance := uint64(1_000_000)
+
+	tests := map[string]struct {
+		transaction tosca.Transaction
+		gasPrice    uint64
+	}{
+		"legacy": {
+			transaction: tosca.Transaction{GasPrice: tosca.NewValue(12)},
+			gasPrice:    12,
+		},
+		"limited by tip cap": {
+			transaction: tosca.Transaction{GasFeeCap: tosca.NewValue(20), GasTipCap: tosca.NewValue(2)},
+			gasPrice:    12,
+		},
+		"limited by fee cap": {
+			transaction: tosca.Transaction{GasFeeCap: tosca.NewValue(11), GasTipCap: tosca.NewValue(2)},
+			gasPrice:    11,
+		},
+	}
+
+	for processorName, processor := range getProcessors() {
+		for name, test := range tests {
+			t.Run(fmt.Sprintf("%s/%s", processorName, name), func(t *testing.T) {
+				transaction := test.transaction
+				transaction.Sender = sender
+				transaction.Recipient = &recipient
+				transaction.GasLimit = 21_000
+
+				context := newScenarioContext(WorldState{
+					sender: Account{Balance: tosca.NewValue(senderBalance)},
+				})
+				blockParameters := tosca.BlockParameters{
+					BaseFee:  tosca.NewValue(10),
+					Revision: tosca.R13_Cancun,
+				}
+				receipt, err := processor.Run(blockParameters, transaction, context)
+				if err != nil || !receipt.Success {
+					t.Fatalf("execution was not successful or failed with error %v", err)
+				}
+				if want, got := tosca.NewValue(test.gasPrice), receipt.EffectiveGasPrice; want != got {
+					t.Errorf("unexpected effective gas price, wanted %v, got %v", want, got)
+				}
+				want := tosca.NewValue(senderBalance - test.gasPrice*21_000)
+				if got := context.GetBalance(sender); want != got {
+					t.Errorf("unexpected sender balance, wanted %v, got %v", want, got)
+				}
+			})
+		}
+	}
+}
+
+func TestProcessor_InvalidFeeCapsAreRejected(t *testing.T) {
+	sender := tosca.Address{1}
+	recipient := tosca.Address{2}
+
+	tests := map[string]struct {
+		revision    tosca.Revision
+		transaction tosca.Transaction
+		want        error
+	}{
+		"gas price below base fee": {
+			revision:    tosca.R13_Cancun,
+			transaction: tosca.Transaction{GasPrice: tosca.NewValue(9)},
+			want:        tosca.ErrFeeCapTooLow,
+		},
+		"fee cap below base fee": {
+			revision:    tosca.R13_Cancun,
+			transaction: tosca.Transaction{GasFeeCap: tosca.NewValue(9), GasTipCap: tosca.NewValue(1)},
+			want:        tosca.ErrFeeCapTooLow,
+		},
+		"tip cap above fee cap": {
+			revision:    tosca.R13_Cancun,
+			transaction: tosca.Transaction{GasFeeCap: tosca.NewValue(20), GasTipCap: tosca.NewValue(21)},
+			want:        tosca.ErrTipAboveFeeCap,
+		},
+		"dynamic fee before london": {
+			revision:    tosca.R09_Berlin,
+			transaction: tosca.Transaction{GasFeeCap: tosca.NewValue(20), GasTipCap: tosca.NewValue(1)},
+			want:        tosca.ErrTxTypeNotSupported,
+		},
+	}
+
+	for processorName, processor := range getProcessors() {
+		for name, test := range tests {
+			t.Run(fmt.Sprintf("%s/%s", processorName, name), func(t *testing.T) {
+				transaction := test.transaction
+				transaction.Sender = sender
+				transaction.Recipient = &recipient
+				transaction.GasLimit = 21_000
+
+				state := WorldState{sender: Account{Balance: tosca.NewValue(1_000_000)}}
+				context := newScenarioContext(state)
+				blockParameters := tosca.BlockParameters{
+					BaseFee:  tosca.NewValue(10),
+					Revision: test.revision,
+				}
+				if _, err := processor.Run(blockParameters, transaction, context); !errors.Is(err, test.want) {
+					t.Errorf("unexpected error, wanted %v, got %v", test.want, err)
+				}
+				if !state.Equal(context.current) {
+					t.Errorf("rejected transaction modified the world state")
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright (c) 2024 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package processor
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/Fantom-foundation/Tosca/go/tosca"
+	"github.com/Fantom-foundation/Tosca/go/tosca/vm"
+)
+
+func TestEstimateGas_FindsMinimalGasLimitForNestedCalls(t *testing.T) {
+	sender := tosca.Address{1}
+	caller := tosca.Address{2}
+	callee := tosca.Address{3}
+
+	// The caller forwards all its gas to the callee and reverts if the
+	// nested call fails. Due to the 63/64 rule, the minimal gas limit of
+	// the transaction exceeds the gas consumed by it.
+	callerCode := pushToStack([]*big.Int{
+		new(big.Int).SetBytes(callee[:]), // call target
+		big.NewInt(0),                    // value to transfer
+		big.NewInt(0),                    // argument offset
+		big.NewInt(0),                    // argument size
+		big.NewInt(0),                    // result offset
+		big.NewInt(0),                    // result size
+	})
+	callerCode = append(callerCode,
+		byte(vm.GAS),
+		byte(vm.CALL),
+		byte(vm.ISZERO),
+		byte(vm.PUSH1), byte(len(callerCode)+7),
+		byte(vm.JUMPI),
+		byte(vm.STOP),
+		byte(vm.JUMPDEST),
+		byte(vm.PUSH1), byte(0),
+		byte(vm.DUP1),
+		byte(vm.REVERT),
+	)
+	calleeCode := []byte{
+		byte(vm.PUSH1), byte(1),
+		byte(vm.PUSH1), byte(0),
+		byte(vm.SSTORE),
+		byte(vm.STOP),
+	}
+
+	accounts := map[tosca.Address]tosca.Account{
+		sender: {Balance: tosca.NewValue(1_000_000_000), Nonce: 4},
+		caller: {Code: callerCode},
+		callee: {Code: calleeCode},
+	}
+	newContext := func() *tosca.InMemoryTransactionContext {
+		return tosca.NewInMemoryTransactionContext(tosca.R13_Cancun, accounts)
+	}
+	blockParameters := tosca.BlockParameters{GasLimit: 10_000_000, Revision: tosca.R13_Cancun}
+	transaction := tosca.Transaction{
+		Sender:    sender,
+		Recipient: &caller,
+		Nonce:     4,
+		GasPrice:  tosca.NewValue(1),
+	}
+
+	for processorName, processor := range getProcessors() {
+		t.Run(processorName, func(t *testing.T) {
+			context := newContext()
+			estimate, err := tosca.EstimateGas(processor, blockParameters, transaction, context)
+			if err != nil {
+				t.Fatalf("failed to estimate gas: %v", err)
+			}
+			if want, got := uint64(4), context.GetNonce(sender); want != got {
+				t.Errorf("gas estimation modified the nonce, wanted %d, got %d", want, got)
+			}
+			if want, got := (tosca.Word{}), context.GetStorage(callee, tosca.Key{}); want != got {
+				t.Errorf("gas estimation modified the storage, wanted %v, got %v", want, got)
+			}
+
+			run := func(gasLimit tosca.Gas) bool {
+				transaction := transaction
+				transaction.GasLimit = gasLimit
+				receipt, err := processor.Run(blockParameters, transaction, newContext())
+				return err == nil && receipt.Success
+			}
+			if !run(estimate) {
+				t.Errorf("transaction failed with estimated gas limit %d", estimate)
+			}
+			if run(estimate - 1) {
+				t.Errorf("transaction succeeded with gas limit %d below estimate", estimate-1)
+			}
+		})
+	}
+}
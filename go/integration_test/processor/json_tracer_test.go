@@ -0,0 +1,98 @@
+// Copyright (c) 2024 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package processor
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/Fantom-foundation/Tosca/go/tosca"
+	"github.com/Fantom-foundation/Tosca/go/tosca/vm"
+)
+
+func TestJsonTracer_ProducesSameTraceForAllTracingInterpreters(t *testing.T) {
+	sender := tosca.Address{1}
+	caller := tosca.Address{2}
+	callee := tosca.Address{3}
+
+	// The caller transfers value to the callee, which updates its storage.
+	callerCode := pushToStack([]*big.Int{
+		new(big.Int).SetBytes(callee[:]),
+		big.NewInt(1),
+		big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0),
+	})
+	callerCode = append(callerCode, byte(vm.GAS), byte(vm.CALL), byte(vm.STOP))
+	calleeCode := []byte{
+		byte(vm.PUSH1), 1,
+		byte(vm.PUSH1), 0,
+		byte(vm.SSTORE),
+		byte(vm.STOP),
+	}
+
+	state := WorldState{
+		sender: Account{Balance: tosca.NewValue(1_000_000)},
+		caller: Account{Balance: tosca.NewValue(10), Code: callerCode},
+		callee: Account{Code: calleeCode},
+	}
+	transaction := tosca.Transaction{
+		Sender:    sender,
+		Recipient: &caller,
+		GasLimit:  100_000,
+	}
+
+	traces := map[string]string{}
+	for processorName, processor := range getProcessors() {
+		interpreterName := processorName[strings.Index(processorName, "/")+1:]
+		if info, _ := tosca.GetInterpreterInfo(interpreterName); !info.Tracing {
+			continue
+		}
+		t.Run(processorName, func(t *testing.T) {
+			log := &bytes.Buffer{}
+			tracer := tosca.NewJsonTracer(log)
+			context := &tracingScenarioContext{newScenarioContext(state), tracer}
+			receipt, err := processor.Run(tosca.BlockParameters{Revision: tosca.R13_Cancun}, transaction, context)
+			if err != nil || !receipt.Success {
+				t.Fatalf("failed to run transaction, receipt %v, error %v", receipt, err)
+			}
+			if err := tracer.Err(); err != nil {
+				t.Fatalf("failed to write trace: %v", err)
+			}
+
+			lines := strings.Split(strings.TrimSpace(log.String()), "\n")
+			// 9 instructions of the caller, 4 of the callee, and the summary.
+			if want, got := 14, len(lines); want != got {
+				t.Fatalf("unexpected number of lines, wanted %d, got %d", want, got)
+			}
+			for _, line := range lines {
+				if !json.Valid([]byte(line)) {
+					t.Errorf("invalid JSON line: %s", line)
+				}
+			}
+			if !strings.Contains(lines[len(lines)-1], `"pass":true`) {
+				t.Errorf("unexpected summary line: %s", lines[len(lines)-1])
+			}
+			traces[processorName] = log.String()
+		})
+	}
+
+	reference, found := traces["floria/lfvm"]
+	if !found {
+		t.Fatalf("no reference trace available")
+	}
+	for name, trace := range traces {
+		if trace != reference {
+			t.Errorf("trace of %s differs from reference\nwanted %s\n   got %s", name, reference, trace)
+		}
+	}
+}
@@ -19,7 +19,7 @@ import (
 	"testing"
 
 	test_utils "github.com/Fantom-foundation/Tosca/go/processor"
-	"github.com/Fantom-foundation/Tosca/go/processor/floria"
+	"github.com/Fantom-foundation/Tosca/go/processor/system_contract"
 	"github.com/Fantom-foundation/Tosca/go/tosca"
 	"github.com/Fantom-foundation/Tosca/go/tosca/vm"
 )
@@ -110,8 +110,8 @@ func TestPrecompiled_StatePrecompiledContractSetBalance(t *testing.T) {
 	accountToChange := tosca.Address{0x55}
 	newBalance := byte(0x05)
 	sender := tosca.Address{0x42}
-	receiver := floria.DriverAddress()
-	stateContractAddress := floria.StateContractAddress()
+	receiver := system_contract.DriverAddress()
+	stateContractAddress := system_contract.EvmWriterAddress()
 
 	setBalancePrefix := []byte{0xe3, 0x4, 0x43, 0xbc}
 	setBalanceValidInput := append(make([]byte, 12), accountToChange[:]...)
@@ -0,0 +1,83 @@
+// Copyright (c) 2024 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package processor
+
+import (
+	"testing"
+
+	"github.com/Fantom-foundation/Tosca/go/tosca"
+	"github.com/Fantom-foundation/Tosca/go/tosca/vm"
+)
+
+func TestPrestateTracer_RecordsStateAndDiffOfTransaction(t *testing.T) {
+	sender := tosca.Address{1}
+	contract := tosca.Address{2}
+
+	code := []byte{
+		byte(vm.PUSH1), 1, // < loaded but unmodified slot
+		byte(vm.SLOAD),
+		byte(vm.PUSH1), 0,
+		byte(vm.SSTORE),
+		byte(vm.STOP),
+	}
+	accounts := map[tosca.Address]tosca.Account{
+		sender:   {Balance: tosca.NewValue(1_000_000_000), Nonce: 4},
+		contract: {Code: code, Storage: map[tosca.Key]tosca.Word{{31: 1}: {31: 7}}},
+	}
+	transaction := tosca.Transaction{
+		Sender:    sender,
+		Recipient: &contract,
+		Nonce:     4,
+		GasLimit:  100_000,
+		GasPrice:  tosca.NewValue(1),
+		Value:     tosca.NewValue(10),
+	}
+	block := tosca.BlockParameters{GasLimit: 1_000_000, Revision: tosca.R13_Cancun}
+
+	for processorName, processor := range getProcessors() {
+		t.Run(processorName, func(t *testing.T) {
+			context := tosca.NewInMemoryTransactionContext(tosca.R13_Cancun, accounts)
+			tracer := tosca.NewPrestateTracer(context, tosca.R13_Cancun)
+			receipt, err := processor.Run(block, transaction, tracer)
+			if err != nil || !receipt.Success {
+				t.Fatalf("failed to run transaction, receipt %v, error %v", receipt, err)
+			}
+
+			prestate := tracer.GetPrestate()
+			if want, got := tosca.NewValue(1_000_000_000), prestate[sender].Balance; got == nil || want != *got {
+				t.Errorf("unexpected balance of sender in prestate, wanted %v, got %v", want, got)
+			}
+			if want, got := uint64(4), prestate[sender].Nonce; want != got {
+				t.Errorf("unexpected nonce of sender in prestate, wanted %d, got %d", want, got)
+			}
+			if want, got := (tosca.Word{31: 7}), prestate[contract].Storage[tosca.Key{31: 1}]; want != got {
+				t.Errorf("unexpected slot in prestate, wanted %v, got %v", want, got)
+			}
+
+			diff := tracer.GetStateDiff()
+			if want, got := uint64(5), diff.Post[sender].Nonce; want != got {
+				t.Errorf("unexpected nonce of sender in poststate, wanted %d, got %d", want, got)
+			}
+			if want, got := tosca.NewValue(10), diff.Post[contract].Balance; got == nil || want != *got {
+				t.Errorf("unexpected balance of contract in poststate, wanted %v, got %v", want, got)
+			}
+			if want, got := (tosca.Word{31: 7}), diff.Post[contract].Storage[tosca.Key{}]; want != got {
+				t.Errorf("unexpected slot in poststate, wanted %v, got %v", want, got)
+			}
+			if _, found := diff.Pre[contract].Storage[tosca.Key{31: 1}]; found {
+				t.Errorf("unmodified slot is listed in state diff")
+			}
+			if diff.Post[contract].Code != nil {
+				t.Errorf("unmodified code is listed in state diff")
+			}
+		})
+	}
+}
@@ -11,7 +11,6 @@
 package processor
 
 import (
-	"fmt"
 	"maps"
 	"testing"
 
@@ -155,9 +154,8 @@ func gasLimitTestCases() map[string]Scenario {
 	const executionGasCost = 3 + 3
 
 	cases := map[string]struct {
-		gasLimit   tosca.Gas
-		receipt    tosca.Receipt
-		OperaError error
+		gasLimit tosca.Gas
+		receipt  tosca.Receipt
 	}{
 		"SimpleCodeExact": {
 			gasLimit: floria.TxGas + executionGasCost,
@@ -179,7 +177,6 @@ func gasLimitTestCases() map[string]Scenario {
 				Success: false,
 				GasUsed: floria.TxGas + executionGasCost - 1,
 			},
-			OperaError: fmt.Errorf("gas too low"),
 		},
 	}
 
@@ -215,7 +212,6 @@ func gasLimitTestCases() map[string]Scenario {
 			Transaction: transaction,
 			After:       after,
 			Receipt:     test.receipt,
-			OperaError:  test.OperaError,
 		}
 	}
 
@@ -226,10 +222,10 @@ func gasPricingTestCases() map[string]Scenario {
 	gasPrice := uint64(10)
 	sender := tosca.Address{1}
 	tests := map[string]struct {
-		Before     Account
-		After      Account
-		Receipt    tosca.Receipt
-		OperaError error
+		Before  Account
+		After   Account
+		Receipt tosca.Receipt
+		Error   error
 	}{
 		"GasPriceCalculation": {
 			Before: Account{Balance: tosca.NewValue(floria.TxGas * gasPrice), Nonce: 4},
@@ -250,11 +246,7 @@ func gasPricingTestCases() map[string]Scenario {
 		"GasPriceCalculationInsufficientBalance": {
 			Before: Account{Balance: tosca.NewValue(floria.TxGas*gasPrice - 1), Nonce: 4},
 			After:  Account{Balance: tosca.NewValue(floria.TxGas*gasPrice - 1), Nonce: 4},
-			Receipt: tosca.Receipt{
-				Success: false,
-				GasUsed: 0,
-			},
-			OperaError: fmt.Errorf("insufficient balance"),
+			Error:  tosca.ErrInsufficientFunds,
 		},
 	}
 
@@ -273,7 +265,7 @@ func gasPricingTestCases() map[string]Scenario {
 			Transaction: transaction,
 			After:       WorldState{sender: test.After},
 			Receipt:     test.Receipt,
-			OperaError:  test.OperaError,
+			Error:       test.Error,
 		}
 	}
 
@@ -282,7 +274,7 @@ func gasPricingTestCases() map[string]Scenario {
 
 func gasSpecificTestCases() map[string]Scenario {
 	cases := map[string]Scenario{
-		"InternalCallDoesNotConsume10PercentOfRemainingGas": {
+		"SystemTransactionDoesNotConsume10PercentOfRemainingGas": {
 			Before: WorldState{
 				{}:  Account{Balance: tosca.NewValue(100), Nonce: 4},
 				{2}: Account{Balance: tosca.NewValue(0)},
@@ -292,6 +284,7 @@ func gasSpecificTestCases() map[string]Scenario {
 				Recipient: &tosca.Address{2},
 				GasLimit:  floria.TxGas + 100,
 				Nonce:     4,
+				Kind:      tosca.SystemTransaction,
 			},
 			After: WorldState{
 				{}:  Account{Balance: tosca.NewValue(100), Nonce: 5},
@@ -342,10 +335,9 @@ func exactSufficientAndInsufficientScenarios(exactScenario Scenario, name string
 
 	insufficient := exactScenario.Clone()
 	insufficient.Transaction.GasLimit -= 1
-	insufficient.Receipt.Success = false
-	insufficient.Receipt.GasUsed = insufficient.Transaction.GasLimit
-	insufficient.OperaError = fmt.Errorf("gas too low")
-	// Reset world state in case of failure
+	insufficient.Receipt = tosca.Receipt{}
+	insufficient.Error = tosca.ErrIntrinsicGas
+	// Rejected transactions do not modify the world state
 	beforeSender := insufficient.Before[insufficient.Transaction.Sender]
 	insufficient.After[insufficient.Transaction.Sender] = beforeSender
 	beforeReceiver := insufficient.Before[*insufficient.Transaction.Recipient]
@@ -13,6 +13,7 @@ package processor
 import (
 	"fmt"
 	"slices"
+	"strings"
 	"testing"
 
 	"github.com/Fantom-foundation/Tosca/go/tosca"
@@ -78,12 +79,9 @@ func getScenarios() map[string]Scenario {
 				Nonce:     4,
 			},
 			After: WorldState{
-				{1}: Account{Balance: tosca.NewValue(10), Nonce: 5},
-			},
-			Receipt: tosca.Receipt{
-				Success: false,
-				GasUsed: 21_000,
+				{1}: Account{Balance: tosca.NewValue(10), Nonce: 4},
 			},
+			Error: tosca.ErrInsufficientFunds,
 		},
 		"SuccessfulContractCall": {
 			Before: WorldState{
@@ -207,13 +205,36 @@ func getProcessors() map[string]tosca.Processor {
 			if err != nil {
 				panic(fmt.Sprintf("failed to load interpreter %s: %v", interpreterName, err))
 			}
-			processor := factory(interpreter)
+			processor, err := factory(interpreter, nil)
+			if err != nil {
+				panic(fmt.Sprintf("failed to create processor %s: %v", processorName, err))
+			}
 			res[fmt.Sprintf("%s/%s", processorName, interpreterName)] = processor
 		}
 	}
 	return res
 }
 
+// getProcessorsSupporting returns the subset of processors produced by
+// getProcessors for which both the processor and the interpreter support the
+// given revision.
+func getProcessorsSupporting(revision tosca.Revision) map[string]tosca.Processor {
+	res := map[string]tosca.Processor{}
+	for name, processor := range getProcessors() {
+		processorName, interpreterName, _ := strings.Cut(name, "/")
+		processorInfo, found := tosca.GetProcessorInfo(processorName)
+		if !found || !processorInfo.SupportsRevision(revision) {
+			continue
+		}
+		interpreterInfo, found := tosca.GetInterpreterInfo(interpreterName)
+		if !found || !interpreterInfo.SupportsRevision(revision) {
+			continue
+		}
+		res[name] = processor
+	}
+	return res
+}
+
 func TestGetProcessors_ContainsMainConfigurations(t *testing.T) {
 	// The main task of this job is to make sure that the essential processors
 	// and interpreters are registered and available for testing.
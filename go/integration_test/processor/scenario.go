@@ -12,6 +12,7 @@ package processor
 
 import (
 	"bytes"
+	"errors"
 	"slices"
 	"strings"
 	"testing"
@@ -22,25 +23,26 @@ import (
 
 // Scenario represents a test scenario for a transaction processor. A scenario
 // consists of a world state before and after the operation, a transaction to
-// be executed, block chain parameters, and the expected receipt.
+// be executed, block chain parameters, and the expected receipt. If the
+// transaction is expected to be rejected, Error is the expected error.
 type Scenario struct {
 	Before      WorldState
 	After       WorldState
 	Parameters  tosca.BlockParameters
 	Transaction tosca.Transaction
 	Receipt     tosca.Receipt
-	OperaError  error
+	Error       error
 }
 
 func (s *Scenario) Run(t *testing.T, processor tosca.Processor) {
 
 	context := newScenarioContext(s.Before)
 	receipt, err := processor.Run(s.Parameters, s.Transaction, context)
-	if err != nil && s.OperaError == nil {
+	if s.Error == nil && err != nil {
 		t.Fatalf("failed to run transaction: %v", err)
 	}
-	if s.OperaError != nil && receipt.Success {
-		t.Fatalf("expected error, got success")
+	if s.Error != nil && !errors.Is(err, s.Error) {
+		t.Fatalf("unexpected error, wanted %v, got %v", s.Error, err)
 	}
 
 	// check the world state after the operation
@@ -102,7 +104,7 @@ func (s *Scenario) Clone() Scenario {
 		Parameters:  s.Parameters,
 		Transaction: s.Transaction,
 		Receipt:     s.Receipt,
-		OperaError:  s.OperaError,
+		Error:       s.Error,
 	}
 }
 
@@ -160,6 +162,10 @@ func (c *scenarioContext) SetNonce(addr tosca.Address, value uint64) {
 	c.undo = append(c.undo, func() { c.current[addr] = original })
 }
 
+func (c *scenarioContext) CreateContract(tosca.Address) {
+	// -- ignored, since self-destructs are not supported by scenarios --
+}
+
 func (c *scenarioContext) GetCode(addr tosca.Address) tosca.Code {
 	return tosca.Code(bytes.Clone(c.current[addr].Code))
 }
@@ -221,8 +221,8 @@ func TestScenario_Clone(t *testing.T) {
 		"receipt": func(s *Scenario) {
 			s.Receipt = tosca.Receipt{Success: true}
 		},
-		"opera_error": func(s *Scenario) {
-			s.OperaError = fmt.Errorf("test")
+		"error": func(s *Scenario) {
+			s.Error = fmt.Errorf("test")
 		},
 	}
 
@@ -232,7 +232,7 @@ func TestScenario_Clone(t *testing.T) {
 			reflect.DeepEqual(a.Parameters, b.Parameters) &&
 			reflect.DeepEqual(a.Transaction, b.Transaction) &&
 			reflect.DeepEqual(a.Receipt, b.Receipt) &&
-			a.OperaError == b.OperaError
+			a.Error == b.Error
 	}
 
 	scenario := Scenario{
@@ -241,7 +241,7 @@ func TestScenario_Clone(t *testing.T) {
 		Parameters:  tosca.BlockParameters{},
 		Transaction: tosca.Transaction{},
 		Receipt:     tosca.Receipt{},
-		OperaError:  nil,
+		Error:       nil,
 	}
 
 	for name, change := range tests {
@@ -0,0 +1,85 @@
+// Copyright (c) 2024 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package processor
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Fantom-foundation/Tosca/go/tosca"
+	"github.com/Fantom-foundation/Tosca/go/tosca/vm"
+)
+
+func TestProcessor_SetCodeTransactionsAreRejectedBeforePrague(t *testing.T) {
+	sender := tosca.Address{1}
+	recipient := tosca.Address{2}
+	state := WorldState{sender: Account{Balance: tosca.NewValue(1_000_000)}}
+	transaction := tosca.Transaction{
+		Sender:            sender,
+		Recipient:         &recipient,
+		GasLimit:          100_000,
+		AuthorizationList: []tosca.SetCodeAuthorization{{Address: tosca.Address{3}}},
+	}
+	block := tosca.BlockParameters{Revision: tosca.R13_Cancun}
+
+	for processorName, processor := range getProcessors() {
+		t.Run(processorName, func(t *testing.T) {
+			context := newScenarioContext(state)
+			if _, err := processor.Run(block, transaction, context); !errors.Is(err, tosca.ErrTxTypeNotSupported) {
+				t.Errorf("unexpected error, wanted %v, got %v", tosca.ErrTxTypeNotSupported, err)
+			}
+			if !state.Equal(context.current) {
+				t.Errorf("rejected transaction modified the world state")
+			}
+		})
+	}
+}
+
+func TestProcessor_CallsOfDelegatedAccountsExecuteCodeOfTarget(t *testing.T) {
+	sender := tosca.Address{1}
+	delegated := tosca.Address{2}
+	target := tosca.Address{3}
+
+	// The target returns the address of the executing account.
+	code := tosca.Code{
+		byte(vm.ADDRESS),
+		byte(vm.PUSH1), 0,
+		byte(vm.MSTORE),
+		byte(vm.PUSH1), 32,
+		byte(vm.PUSH1), 0,
+		byte(vm.RETURN),
+	}
+	state := WorldState{
+		sender:    Account{Balance: tosca.NewValue(1_000_000)},
+		delegated: Account{Code: tosca.NewDelegationDesignator(target)},
+		target:    Account{Code: code},
+	}
+	transaction := tosca.Transaction{
+		Sender:    sender,
+		Recipient: &delegated,
+		GasLimit:  100_000,
+	}
+	block := tosca.BlockParameters{Revision: tosca.R14_Prague}
+
+	for processorName, processor := range getProcessorsSupporting(block.Revision) {
+		t.Run(processorName, func(t *testing.T) {
+			receipt, err := processor.Run(block, transaction, newScenarioContext(state))
+			if err != nil || !receipt.Success {
+				t.Fatalf("failed to run transaction, receipt %v, error %v", receipt, err)
+			}
+			want := tosca.Word{}
+			copy(want[12:], delegated[:])
+			if got := tosca.Word(receipt.Output); want != got {
+				t.Errorf("unexpected output, wanted %v, got %v", want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2024 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package processor
+
+import (
+	"testing"
+
+	"github.com/Fantom-foundation/Tosca/go/tosca"
+	"github.com/Fantom-foundation/Tosca/go/tosca/vm"
+)
+
+func TestSimulate_CallsContractsWithOverriddenStateAndBlock(t *testing.T) {
+	sender := tosca.Address{1}
+	contract := tosca.Address{2}
+
+	// The contract returns the sum of its first storage slot and the timestamp.
+	code := tosca.Code{
+		byte(vm.PUSH1), 0,
+		byte(vm.SLOAD),
+		byte(vm.TIMESTAMP),
+		byte(vm.ADD),
+		byte(vm.PUSH1), 0,
+		byte(vm.MSTORE),
+		byte(vm.PUSH1), 32,
+		byte(vm.PUSH1), 0,
+		byte(vm.RETURN),
+	}
+
+	timestamp := int64(12)
+	stateOverrides := tosca.StateOverrides{
+		contract: {Code: code, StateDiff: map[tosca.Key]tosca.Word{{}: tosca.Word(tosca.NewValue(30))}},
+	}
+	blockOverrides := &tosca.BlockOverrides{Timestamp: &timestamp}
+
+	// The sender has neither funds nor the nonce of the transaction.
+	transaction := tosca.Transaction{
+		Sender:    sender,
+		Recipient: &contract,
+		Nonce:     5,
+		GasPrice:  tosca.NewValue(1),
+	}
+	block := tosca.BlockParameters{GasLimit: 1_000_000, Revision: tosca.R13_Cancun}
+
+	for processorName, processor := range getProcessors() {
+		t.Run(processorName, func(t *testing.T) {
+			context := tosca.NewInMemoryTransactionContext(tosca.R13_Cancun, nil)
+			receipt, err := tosca.Simulate(processor, block, transaction, context, stateOverrides, blockOverrides)
+			if err != nil || !receipt.Success {
+				t.Fatalf("failed to simulate transaction, receipt %v, error %v", receipt, err)
+			}
+			if want, got := tosca.Word(tosca.NewValue(42)), tosca.Word(receipt.Output); want != got {
+				t.Errorf("unexpected output, wanted %v, got %v", want, got)
+			}
+			if context.AccountExists(sender) || context.AccountExists(contract) {
+				t.Errorf("simulation modified the world state")
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2024 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package processor
+
+import (
+	"testing"
+
+	"github.com/Fantom-foundation/Tosca/go/processor/floria"
+	opera "github.com/Fantom-foundation/Tosca/go/processor/opera"
+	"github.com/Fantom-foundation/Tosca/go/processor/system_contract"
+	"github.com/Fantom-foundation/Tosca/go/tosca"
+)
+
+func TestProcessor_SystemContractsCanBeConfigured(t *testing.T) {
+	driver := system_contract.DriverAddress()
+	evmWriter := system_contract.EvmWriterAddress()
+	accountToChange := tosca.Address{0x55}
+
+	// setBalance(accountToChange, 5)
+	input := []byte{0xe3, 0x4, 0x43, 0xbc}
+	input = append(input, make([]byte, 12)...)
+	input = append(input, accountToChange[:]...)
+	input = append(input, make([]byte, 31)...)
+	input = append(input, 5)
+
+	tests := map[string]struct {
+		floria  floria.Config
+		opera   opera.Config
+		enabled bool
+	}{
+		"default": {
+			enabled: true,
+		},
+		"explicitly listed": {
+			floria:  floria.Config{SystemContracts: []*system_contract.Contract{system_contract.EvmWriter()}},
+			opera:   opera.Config{SystemContracts: []*system_contract.Contract{system_contract.EvmWriter()}},
+			enabled: true,
+		},
+		"empty list": {
+			floria: floria.Config{SystemContracts: []*system_contract.Contract{}},
+			opera:  opera.Config{SystemContracts: []*system_contract.Contract{}},
+		},
+		"disabled": {
+			floria: floria.Config{WithoutStateContracts: true},
+			opera:  opera.Config{WithoutStateContracts: true},
+		},
+	}
+
+	for name, test := range tests {
+		for _, processorName := range []string{"floria", "opera"} {
+			t.Run(processorName+"/"+name, func(t *testing.T) {
+				var config any = test.floria
+				if processorName == "opera" {
+					config = test.opera
+				}
+				processor, err := tosca.NewProcessor(processorName, tosca.GetInterpreter("lfvm"), config)
+				if err != nil {
+					t.Fatalf("failed to create processor: %v", err)
+				}
+
+				state := WorldState{accountToChange: Account{Balance: tosca.NewValue(42)}}
+				transaction := tosca.Transaction{
+					Sender:    driver,
+					Recipient: &evmWriter,
+					GasLimit:  100_000,
+					Input:     input,
+				}
+				context := newScenarioContext(state)
+				block := tosca.BlockParameters{Revision: tosca.R13_Cancun}
+				receipt, err := processor.Run(block, transaction, context)
+				if err != nil || !receipt.Success {
+					t.Fatalf("failed to run transaction, receipt %v, error %v", receipt, err)
+				}
+
+				want := tosca.NewValue(42)
+				if test.enabled {
+					want = tosca.NewValue(5)
+				}
+				if got := context.GetBalance(accountToChange); want != got {
+					t.Errorf("unexpected balance, wanted %v, got %v", want, got)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright (c) 2024 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package processor
+
+import (
+	"testing"
+
+	"github.com/Fantom-foundation/Tosca/go/processor/floria"
+	"github.com/Fantom-foundation/Tosca/go/tosca"
+	"github.com/Fantom-foundation/Tosca/go/tosca/vm"
+)
+
+func TestProcessor_SystemTransactionsAreNotSubjectToPreChecks(t *testing.T) {
+	sender := tosca.Address{1}
+	recipient := tosca.Address{2}
+
+	// The sender has code, no balance, and a nonce not matching the
+	// transaction, and the fee cap is below the base fee. Each of those
+	// would cause the rejection of a regular transaction.
+	code := tosca.Code{byte(vm.PUSH1), 1, byte(vm.PUSH1), 0, byte(vm.MSTORE), byte(vm.STOP)}
+	state := WorldState{
+		sender:    Account{Nonce: 7, Code: tosca.Code{byte(vm.STOP)}},
+		recipient: Account{Code: code},
+	}
+	transaction := tosca.Transaction{
+		Sender:    sender,
+		Recipient: &recipient,
+		Nonce:     3,
+		GasLimit:  100_000,
+		GasFeeCap: tosca.NewValue(1),
+		Kind:      tosca.SystemTransaction,
+	}
+	block := tosca.BlockParameters{BaseFee: tosca.NewValue(10), Revision: tosca.R13_Cancun}
+
+	for processorName, processor := range getProcessors() {
+		t.Run(processorName, func(t *testing.T) {
+			regular := transaction
+			regular.Kind = tosca.RegularTransaction
+			if _, err := processor.Run(block, regular, newScenarioContext(state)); err == nil {
+				t.Fatalf("regular transaction was not rejected")
+			}
+
+			context := newScenarioContext(state)
+			receipt, err := processor.Run(block, transaction, context)
+			if err != nil || !receipt.Success {
+				t.Fatalf("failed to run system transaction, receipt %v, error %v", receipt, err)
+			}
+			// The contract consumes 12 gas. No share of the unused gas is charged.
+			if want, got := tosca.Gas(floria.TxGas+12), receipt.GasUsed; want != got {
+				t.Errorf("unexpected gas used, wanted %d, got %d", want, got)
+			}
+			if want, got := (tosca.Value{}), context.GetBalance(sender); want != got {
+				t.Errorf("sender was charged, wanted balance %v, got %v", want, got)
+			}
+			if want, got := uint64(8), context.GetNonce(sender); want != got {
+				t.Errorf("unexpected sender nonce, wanted %d, got %d", want, got)
+			}
+		})
+	}
+}
+
+func TestBlockExecutor_IncludesSystemTransactionsWithoutFees(t *testing.T) {
+	coinbase := tosca.Address{0xc}
+	recipient := tosca.Address{2}
+	block := tosca.BlockParameters{
+		GasLimit: 1_000_000,
+		Coinbase: coinbase,
+		BaseFee:  tosca.NewValue(10),
+		Revision: tosca.R13_Cancun,
+	}
+	transaction := tosca.Transaction{
+		Recipient: &recipient,
+		GasLimit:  floria.TxGas,
+		Kind:      tosca.SystemTransaction,
+	}
+
+	for processorName, processor := range getProcessors() {
+		t.Run(processorName, func(t *testing.T) {
+			context := tosca.NewInMemoryTransactionContext(tosca.R13_Cancun, nil)
+			result := tosca.NewBlockExecutor(processor).Run(block, []tosca.Transaction{transaction}, context)
+			if len(result.Skipped) != 0 {
+				t.Fatalf("system transaction was skipped: %v", result.Skipped[0].Err)
+			}
+			if want, got := 1, len(result.Receipts); want != got {
+				t.Fatalf("unexpected number of receipts, wanted %d, got %d", want, got)
+			}
+			if want, got := (tosca.Value{}), context.GetBalance(coinbase); want != got {
+				t.Errorf("unexpected coinbase balance, wanted %v, got %v", want, got)
+			}
+		})
+	}
+}
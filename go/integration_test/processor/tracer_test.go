@@ -0,0 +1,134 @@
+// Copyright (c) 2024 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package processor
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Fantom-foundation/Tosca/go/tosca"
+	"github.com/Fantom-foundation/Tosca/go/tosca/vm"
+)
+
+func TestProcessor_TracerIsInformedAboutExecution(t *testing.T) {
+	code := []byte{
+		byte(vm.PUSH1), byte(1),
+		byte(vm.PUSH1), byte(0),
+		byte(vm.SSTORE),
+		byte(vm.PUSH1), byte(0),
+		byte(vm.PUSH1), byte(0),
+		byte(vm.LOG0),
+		byte(vm.STOP),
+	}
+	sender := tosca.Address{1}
+	receiver := tosca.Address{2}
+
+	for processorName, processor := range getProcessors() {
+		t.Run(processorName, func(t *testing.T) {
+			state := WorldState{
+				sender:   Account{Balance: tosca.NewValue(100)},
+				receiver: Account{Code: code},
+			}
+			transaction := tosca.Transaction{
+				Sender:    sender,
+				Recipient: &receiver,
+				GasLimit:  sufficientGas,
+				Value:     tosca.NewValue(10),
+			}
+
+			tracer := &recordingTracer{}
+			context := &tracingScenarioContext{newScenarioContext(state), tracer}
+
+			result, err := processor.Run(tosca.BlockParameters{}, transaction, context)
+			if err != nil || !result.Success {
+				t.Fatalf("execution was not successful or failed with error %v", err)
+			}
+
+			// Instructions are only reported by interpreters supporting tracing.
+			interpreterName := processorName[strings.Index(processorName, "/")+1:]
+			if info, _ := tosca.GetInterpreterInfo(interpreterName); info.Tracing {
+				wantOps := []vm.OpCode{vm.PUSH1, vm.PUSH1, vm.SSTORE, vm.PUSH1, vm.PUSH1, vm.LOG0, vm.STOP}
+				wantPcs := []int{0, 2, 4, 5, 7, 9, 10}
+				if !slices.Equal(wantOps, tracer.ops) {
+					t.Errorf("unexpected traced operations, wanted %v, got %v", wantOps, tracer.ops)
+				}
+				if !slices.Equal(wantPcs, tracer.pcs) {
+					t.Errorf("unexpected traced program counters, wanted %v, got %v", wantPcs, tracer.pcs)
+				}
+			}
+
+			if want, got := []int{0}, tracer.enterDepths; !slices.Equal(want, got) {
+				t.Errorf("unexpected call frames entered, wanted %v, got %v", want, got)
+			}
+			if want, got := []int{0}, tracer.exitDepths; !slices.Equal(want, got) {
+				t.Errorf("unexpected call frames left, wanted %v, got %v", want, got)
+			}
+
+			if want, got := []tosca.Address{receiver}, tracer.storageChanges; !slices.Equal(want, got) {
+				t.Errorf("unexpected storage changes, wanted %v, got %v", want, got)
+			}
+			if want, got := 1, tracer.logs; want != got {
+				t.Errorf("unexpected number of logs, wanted %d, got %d", want, got)
+			}
+			if !slices.Contains(tracer.balanceChanges, receiver) {
+				t.Errorf("value transfer to %v was not traced, got %v", receiver, tracer.balanceChanges)
+			}
+		})
+	}
+}
+
+// tracingScenarioContext is a scenario context requesting the tracing of
+// transactions using a given tracer.
+type tracingScenarioContext struct {
+	*scenarioContext
+	tracer tosca.Tracer
+}
+
+func (c *tracingScenarioContext) GetTracer() tosca.Tracer {
+	return c.tracer
+}
+
+// recordingTracer is a tracer recording a summary of the observed events.
+type recordingTracer struct {
+	ops            []vm.OpCode
+	pcs            []int
+	enterDepths    []int
+	exitDepths     []int
+	storageChanges []tosca.Address
+	balanceChanges []tosca.Address
+	logs           int
+}
+
+func (r *recordingTracer) OnStep(info tosca.StepInfo) {
+	r.ops = append(r.ops, info.Op)
+	r.pcs = append(r.pcs, info.Pc)
+}
+
+func (r *recordingTracer) OnEnter(depth int, _ tosca.CallKind, _ tosca.CallParameters) {
+	r.enterDepths = append(r.enterDepths, depth)
+}
+
+func (r *recordingTracer) OnExit(depth int, _ tosca.CallResult, _ error) {
+	r.exitDepths = append(r.exitDepths, depth)
+}
+
+func (r *recordingTracer) OnStorageChange(address tosca.Address, _ tosca.Key, _, _ tosca.Word) {
+	r.storageChanges = append(r.storageChanges, address)
+}
+
+func (r *recordingTracer) OnBalanceChange(address tosca.Address, _, _ tosca.Value) {
+	r.balanceChanges = append(r.balanceChanges, address)
+}
+
+func (r *recordingTracer) OnLog(tosca.Log) {
+	r.logs++
+}
@@ -12,6 +12,7 @@ package processor
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"slices"
 	"testing"
@@ -173,14 +174,17 @@ func TestProcessor_TransferToSelf(t *testing.T) {
 	tests := map[string]struct {
 		value   tosca.Value
 		success bool
+		err     error
 	}{
 		"sufficient balance": {
 			tosca.NewValue(100),
 			true,
+			nil,
 		},
 		"insufficient balance": {
 			tosca.NewValue(10000),
 			false,
+			tosca.ErrInsufficientFunds,
 		},
 	}
 	for processorName, processor := range getProcessors() {
@@ -203,8 +207,8 @@ func TestProcessor_TransferToSelf(t *testing.T) {
 
 				// Run the processor
 				result, err := processor.Run(tosca.BlockParameters{}, transaction, transactionContext)
-				if err != nil {
-					t.Fatalf("execution failed with error %v", err)
+				if !errors.Is(err, test.err) {
+					t.Fatalf("unexpected error, wanted %v, got %v", test.err, err)
 				}
 				if result.Success != test.success {
 					t.Errorf("expected success flag to be %v, got %v", test.success, result.Success)
@@ -105,6 +105,7 @@ func (e *EvmcInterpreter) Run(params tosca.Parameters) (tosca.Result, error) {
 		// This is not really an error, but actually a revert.
 		// This is to be processed as a successful execution.
 		res.Success = false // < signal that execution reverted
+		res.FailureCause = tosca.FailureRevert
 		return res, nil
 	case evmc.Error(C.EVMC_OUT_OF_GAS),
 		evmc.Error(C.EVMC_INVALID_INSTRUCTION),
@@ -117,12 +118,32 @@ func (e *EvmcInterpreter) Run(params tosca.Parameters) (tosca.Result, error) {
 		// These are errors in the executed contract, but not VM errors.
 		// The result is thus marked as not successful, and all gas is
 		// removed. Also, all refunds are removed and no data is returned.
-		return tosca.Result{Success: false}, nil
+		return tosca.Result{Success: false, FailureCause: getFailureCause(err)}, nil
 	default:
 		return tosca.Result{}, fmt.Errorf("unexpected EVMC execution error: %w", err)
 	}
 }
 
+// getFailureCause classifies the given EVMC error of a failed execution.
+func getFailureCause(err error) tosca.FailureCause {
+	switch err {
+	case evmc.Error(C.EVMC_OUT_OF_GAS):
+		return tosca.FailureOutOfGas
+	case evmc.Error(C.EVMC_INVALID_INSTRUCTION),
+		evmc.Error(C.EVMC_UNDEFINED_INSTRUCTION):
+		return tosca.FailureInvalidOpCode
+	case evmc.Error(C.EVMC_BAD_JUMP_DESTINATION):
+		return tosca.FailureInvalidJump
+	case evmc.Error(C.EVMC_STATIC_MODE_VIOLATION):
+		return tosca.FailureStaticViolation
+	case evmc.Error(C.EVMC_STACK_OVERFLOW),
+		evmc.Error(C.EVMC_STACK_UNDERFLOW):
+		return tosca.FailureStackViolation
+	default:
+		return tosca.FailureOther
+	}
+}
+
 // GetEvmcVM provides direct access to the Evmc VM connected through the EVMC library.
 func (e *EvmcInterpreter) GetEvmcVM() *evmc.VM {
 	return e.vm
@@ -35,10 +35,10 @@ func init() {
 	// as the default "evmone" VM and as the "evmone-basic" tosca.
 	tosca.MustRegisterInterpreterFactory("evmone", func(any) (tosca.Interpreter, error) {
 		return &evmoneInstance{evmone}, nil
-	})
+	}, info)
 	tosca.MustRegisterInterpreterFactory("evmone-basic", func(any) (tosca.Interpreter, error) {
 		return &evmoneInstance{evmone}, nil
-	})
+	}, info)
 
 	// A second instance is configured to use the advanced execution mode.
 	evmone, err = evmc.LoadEvmcInterpreter("libevmone.so")
@@ -50,7 +50,7 @@ func init() {
 	}
 	tosca.MustRegisterInterpreterFactory("evmone-advanced", func(any) (tosca.Interpreter, error) {
 		return &evmoneInstance{evmone}, nil
-	})
+	}, info)
 }
 
 type evmoneInstance struct {
@@ -59,6 +59,13 @@ type evmoneInstance struct {
 
 const newestSupportedRevision = tosca.R13_Cancun
 
+// info describes the capabilities of the interpreter configurations
+// registered by this package.
+var info = tosca.InterpreterInfo{
+	NewestSupportedRevision: newestSupportedRevision,
+	NativeDependency:        "libevmone.so",
+}
+
 func (e *evmoneInstance) Run(params tosca.Parameters) (tosca.Result, error) {
 	if params.Revision > newestSupportedRevision {
 		return tosca.Result{}, &tosca.ErrUnsupportedRevision{Revision: params.Revision}
@@ -35,7 +35,7 @@ func init() {
 		// This instance remains in its basic configuration.
 		tosca.MustRegisterInterpreterFactory("evmrs", func(any) (tosca.Interpreter, error) {
 			return &evmrsInstance{evm}, nil
-		})
+		}, info)
 	}
 
 	{
@@ -48,7 +48,7 @@ func init() {
 		}
 		tosca.MustRegisterInterpreterFactory("evmrs-logging", func(any) (tosca.Interpreter, error) {
 			return &evmrsInstance{evm}, nil
-		})
+		}, info)
 	}
 }
 
@@ -58,6 +58,14 @@ type evmrsInstance struct {
 
 const newestSupportedRevision = tosca.R13_Cancun
 
+// info describes the capabilities of the interpreter configurations
+// registered by this package.
+var info = tosca.InterpreterInfo{
+	NewestSupportedRevision: newestSupportedRevision,
+	Steppable:               true,
+	NativeDependency:        "libevmrs.so",
+}
+
 func (e *evmrsInstance) Run(params tosca.Parameters) (tosca.Result, error) {
 	if params.Revision > newestSupportedRevision {
 		return tosca.Result{}, &tosca.ErrUnsupportedRevision{Revision: params.Revision}
@@ -12,17 +12,26 @@ package evmzero
 
 /*
 #cgo LDFLAGS: -L${SRCDIR}/../../../cpp/build/vm/evmzero -levmzero -Wl,-rpath,${SRCDIR}/../../../cpp/build/vm/evmzero
+#include <stdint.h>
 // Declarations for evmzero API exceeding EVMC requirements.
-void evmzero_dump_profile(void* vm);
+struct evmzero_profile {
+  uint64_t interpreter_calls;
+  uint64_t interpreter_time_ns;
+  uint64_t instruction_calls[256];
+  uint64_t instruction_time_ns[256];
+};
+void evmzero_get_profile(void* vm, struct evmzero_profile* out);
 void evmzero_reset_profiler(void* vm);
 */
 import "C"
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/Fantom-foundation/Tosca/go/interpreter/evmc"
 	"github.com/Fantom-foundation/Tosca/go/tosca"
+	"github.com/Fantom-foundation/Tosca/go/tosca/vm"
 )
 
 func init() {
@@ -38,7 +47,7 @@ func init() {
 		// This instance remains in its basic configuration.
 		tosca.MustRegisterInterpreterFactory("evmzero", func(any) (tosca.Interpreter, error) {
 			return &evmzeroInstance{evm}, nil
-		})
+		}, info)
 	}
 
 	// We create a second instance in which we enable logging.
@@ -52,7 +61,7 @@ func init() {
 		}
 		tosca.MustRegisterInterpreterFactory("evmzero-logging", func(any) (tosca.Interpreter, error) {
 			return &evmzeroInstance{evm}, nil
-		})
+		}, info)
 	}
 
 	// A third instance without analysis cache.
@@ -66,7 +75,7 @@ func init() {
 		}
 		tosca.MustRegisterInterpreterFactory("evmzero-no-analysis-cache", func(any) (tosca.Interpreter, error) {
 			return &evmzeroInstance{evm}, nil
-		})
+		}, info)
 	}
 
 	// Another instance without SHA3 cache.
@@ -80,7 +89,7 @@ func init() {
 		}
 		tosca.MustRegisterInterpreterFactory("evmzero-no-sha3-cache", func(any) (tosca.Interpreter, error) {
 			return &evmzeroInstance{evm}, nil
-		})
+		}, info)
 	}
 
 	// Another instance in which we enable profiling.
@@ -94,7 +103,7 @@ func init() {
 		}
 		tosca.MustRegisterInterpreterFactory("evmzero-profiling", func(any) (tosca.Interpreter, error) {
 			return &evmzeroInstanceWithProfiler{&evmzeroInstance{evm}}, nil
-		})
+		}, profilingInfo)
 	}
 
 	// Another instance in which we enable profiling external.
@@ -108,7 +117,7 @@ func init() {
 		}
 		tosca.MustRegisterInterpreterFactory("evmzero-profiling-external", func(any) (tosca.Interpreter, error) {
 			return &evmzeroInstanceWithProfiler{&evmzeroInstance{evm}}, nil
-		})
+		}, profilingInfo)
 	}
 }
 
@@ -118,6 +127,23 @@ type evmzeroInstance struct {
 
 const newestSupportedRevision = tosca.R13_Cancun
 
+// info describes the capabilities of the interpreter configurations
+// registered by this package.
+var info = tosca.InterpreterInfo{
+	NewestSupportedRevision: newestSupportedRevision,
+	Steppable:               true,
+	NativeDependency:        "libevmzero.so",
+}
+
+// profilingInfo describes the capabilities of configurations collecting
+// profiling data.
+var profilingInfo = tosca.InterpreterInfo{
+	NewestSupportedRevision: newestSupportedRevision,
+	Steppable:               true,
+	Profiling:               true,
+	NativeDependency:        "libevmzero.so",
+}
+
 func (e *evmzeroInstance) Run(params tosca.Parameters) (tosca.Result, error) {
 	if params.Revision > newestSupportedRevision {
 		return tosca.Result{}, &tosca.ErrUnsupportedRevision{Revision: params.Revision}
@@ -131,8 +157,29 @@ type evmzeroInstanceWithProfiler struct {
 	*evmzeroInstance
 }
 
-func (e *evmzeroInstanceWithProfiler) DumpProfile() {
-	C.evmzero_dump_profile(e.e.GetEvmcVM().GetHandle())
+func (e *evmzeroInstanceWithProfiler) GetProfile() tosca.Profile {
+	var profile C.struct_evmzero_profile
+	C.evmzero_get_profile(e.e.GetEvmcVM().GetHandle(), &profile)
+
+	res := tosca.Profile{
+		Interpreter: tosca.ExecutionStats{
+			Count:    uint64(profile.interpreter_calls),
+			Duration: time.Duration(profile.interpreter_time_ns),
+		},
+		Instructions: map[string]tosca.ExecutionStats{},
+	}
+	for i := range profile.instruction_calls {
+		count := uint64(profile.instruction_calls[i])
+		if count == 0 {
+			continue
+		}
+		res.Steps += count
+		res.Instructions[vm.OpCode(i).String()] = tosca.ExecutionStats{
+			Count:    count,
+			Duration: time.Duration(profile.instruction_time_ns[i]),
+		}
+	}
+	return res
 }
 
 func (e *evmzeroInstanceWithProfiler) ResetProfile() {
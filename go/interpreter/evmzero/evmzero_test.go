@@ -42,7 +42,7 @@ func TestFib10(t *testing.T) {
 	}
 }
 
-func TestEvmzero_DumpProfile(t *testing.T) {
+func TestEvmzero_GetProfile(t *testing.T) {
 	example := examples.GetFibExample()
 	instance, err := tosca.NewInterpreter("evmzero-profiling")
 	if err != nil {
@@ -57,9 +57,15 @@ func TestEvmzero_DumpProfile(t *testing.T) {
 		if err != nil {
 			t.Fatalf("running the fib example failed: %v", err)
 		}
-		interpreter.DumpProfile()
+		profile := interpreter.GetProfile()
+		if profile.Steps == 0 || profile.Interpreter.Count == 0 {
+			t.Errorf("profile does not contain executed steps: %v", profile)
+		}
 		if i == 5 {
 			interpreter.ResetProfile()
+			if got := interpreter.GetProfile(); got.Steps != 0 {
+				t.Errorf("profile was not reset, got %v", got)
+			}
 		}
 	}
 }
@@ -0,0 +1,51 @@
+// Copyright (c) 2024 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package geth
+
+import (
+	"errors"
+
+	"github.com/Fantom-foundation/Tosca/go/tosca"
+	geth "github.com/ethereum/go-ethereum/core/vm"
+)
+
+// ToFailureCause classifies an error reported by the geth EVM for an
+// unsuccessful execution. The result is FailureNone if the error is nil.
+func ToFailureCause(err error) tosca.FailureCause {
+	var (
+		stackOverflow  *geth.ErrStackOverflow
+		stackUnderflow *geth.ErrStackUnderflow
+		invalidOpCode  *geth.ErrInvalidOpCode
+	)
+	switch {
+	case err == nil:
+		return tosca.FailureNone
+	case errors.Is(err, geth.ErrExecutionReverted):
+		return tosca.FailureRevert
+	case errors.Is(err, geth.ErrOutOfGas),
+		errors.Is(err, geth.ErrCodeStoreOutOfGas),
+		errors.Is(err, geth.ErrGasUintOverflow):
+		return tosca.FailureOutOfGas
+	case errors.As(err, &invalidOpCode):
+		return tosca.FailureInvalidOpCode
+	case errors.Is(err, geth.ErrInvalidJump):
+		return tosca.FailureInvalidJump
+	case errors.As(err, &stackOverflow), errors.As(err, &stackUnderflow):
+		return tosca.FailureStackViolation
+	case errors.Is(err, geth.ErrWriteProtection):
+		return tosca.FailureStaticViolation
+	case errors.Is(err, geth.ErrMaxCodeSizeExceeded),
+		errors.Is(err, geth.ErrMaxInitCodeSizeExceeded):
+		return tosca.FailureCodeSizeLimit
+	default:
+		return tosca.FailureOther
+	}
+}
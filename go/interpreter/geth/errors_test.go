@@ -0,0 +1,51 @@
+// Copyright (c) 2024 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package geth
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Fantom-foundation/Tosca/go/tosca"
+	geth "github.com/ethereum/go-ethereum/core/vm"
+)
+
+func TestToFailureCause_ClassifiesGethErrors(t *testing.T) {
+	tests := map[string]struct {
+		err  error
+		want tosca.FailureCause
+	}{
+		"nil":                     {nil, tosca.FailureNone},
+		"revert":                  {geth.ErrExecutionReverted, tosca.FailureRevert},
+		"out of gas":              {geth.ErrOutOfGas, tosca.FailureOutOfGas},
+		"code store out of gas":   {geth.ErrCodeStoreOutOfGas, tosca.FailureOutOfGas},
+		"gas overflow":            {geth.ErrGasUintOverflow, tosca.FailureOutOfGas},
+		"invalid opcode":          {&geth.ErrInvalidOpCode{}, tosca.FailureInvalidOpCode},
+		"invalid jump":            {geth.ErrInvalidJump, tosca.FailureInvalidJump},
+		"stack overflow":          {&geth.ErrStackOverflow{}, tosca.FailureStackViolation},
+		"stack underflow":         {&geth.ErrStackUnderflow{}, tosca.FailureStackViolation},
+		"write protection":        {geth.ErrWriteProtection, tosca.FailureStaticViolation},
+		"max code size":           {geth.ErrMaxCodeSizeExceeded, tosca.FailureCodeSizeLimit},
+		"max init code size":      {geth.ErrMaxInitCodeSizeExceeded, tosca.FailureCodeSizeLimit},
+		"wrapped invalid jump":    {fmt.Errorf("wrapped: %w", geth.ErrInvalidJump), tosca.FailureInvalidJump},
+		"wrapped stack underflow": {fmt.Errorf("wrapped: %w", &geth.ErrStackUnderflow{}), tosca.FailureStackViolation},
+		"address collision":       {geth.ErrContractAddressCollision, tosca.FailureOther},
+		"other":                   {fmt.Errorf("other"), tosca.FailureOther},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if want, got := test.want, ToFailureCause(test.err); want != got {
+				t.Errorf("unexpected failure cause, wanted %v, got %v", want, got)
+			}
+		})
+	}
+}
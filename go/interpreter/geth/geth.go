@@ -13,10 +13,12 @@ package geth
 import (
 	"errors"
 	"fmt"
+	"io"
 	"math/big"
 
 	ct "github.com/Fantom-foundation/Tosca/go/ct/common"
 	"github.com/Fantom-foundation/Tosca/go/tosca"
+	"github.com/Fantom-foundation/Tosca/go/tosca/vm"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/stateless"
 	"github.com/ethereum/go-ethereum/core/tracing"
@@ -29,20 +31,66 @@ import (
 )
 
 func init() {
-	tosca.MustRegisterInterpreterFactory("geth", func(any) (tosca.Interpreter, error) {
-		return &gethVm{}, nil
+	tosca.MustRegisterInterpreterFactory("geth", func(config any) (tosca.Interpreter, error) {
+		switch config := config.(type) {
+		case nil:
+			return NewInterpreter(Config{}), nil
+		case Config:
+			return NewInterpreter(config), nil
+		case *Config:
+			if config == nil {
+				return NewInterpreter(Config{}), nil
+			}
+			return NewInterpreter(*config), nil
+		default:
+			return nil, fmt.Errorf("unsupported configuration type for geth: %T", config)
+		}
+	}, tosca.InterpreterInfo{
+		NewestSupportedRevision: newestSupportedRevision,
+		Steppable:               true,
+		Tracing:                 true,
 	})
 }
 
-type gethVm struct{}
+// Config provides a set of user-definable options for the geth interpreter.
+// The zero value is the default configuration.
+type Config struct {
+	// Log, if not nil, is the writer receiving a trace of all executed
+	// instructions in the EIP-3155 JSON format. Executions with an explicitly
+	// requested tracer are not logged. Logging is not thread-safe.
+	Log io.Writer
+}
+
+// NewInterpreter creates a new geth interpreter instance with the given
+// configuration.
+func NewInterpreter(config Config) tosca.Interpreter {
+	res := &gethVm{}
+	if config.Log != nil {
+		res.logger = tosca.NewJsonTracer(config.Log)
+	}
+	return res
+}
+
+type gethVm struct {
+	logger *tosca.JsonTracer // < nil if logging is disabled
+}
 
-// Defines the newest supported revision for this interpreter implementation
+// Defines the newest supported revision for this interpreter implementation.
+// Prague is not supported since the underlying geth version does not charge
+// for accessing the targets of code delegations (EIP-7702).
 const newestSupportedRevision = tosca.R13_Cancun
 
 func (m *gethVm) Run(parameters tosca.Parameters) (tosca.Result, error) {
 	if parameters.Revision > newestSupportedRevision {
 		return tosca.Result{}, &tosca.ErrUnsupportedRevision{Revision: parameters.Revision}
 	}
+	if parameters.Tracer == nil && m.logger != nil {
+		result, err := tosca.RunTraced(m, parameters, m.logger)
+		if err == nil {
+			err = m.logger.Err()
+		}
+		return result, err
+	}
 	evm, contract, stateDb := createGethInterpreterContext(parameters)
 
 	output, err := evm.Interpreter().Run(contract, parameters.Input, false)
@@ -62,6 +110,7 @@ func (m *gethVm) Run(parameters tosca.Parameters) (tosca.Result, error) {
 	// In case of a revert the result should indicate an unsuccessful execution.
 	if err == geth.ErrExecutionReverted {
 		result.Success = false
+		result.FailureCause = tosca.FailureRevert
 		return result, nil
 	}
 
@@ -81,17 +130,17 @@ func (m *gethVm) Run(parameters tosca.Parameters) (tosca.Result, error) {
 		errors.Is(err, geth.ErrReturnDataOutOfBounds),
 		errors.Is(err, geth.ErrGasUintOverflow),
 		errors.Is(err, geth.ErrInvalidCode):
-		return tosca.Result{Success: false}, nil
+		return tosca.Result{Success: false, FailureCause: ToFailureCause(err)}, nil
 	}
 
 	if _, ok := err.(*geth.ErrStackOverflow); ok {
-		return tosca.Result{Success: false}, nil
+		return tosca.Result{Success: false, FailureCause: tosca.FailureStackViolation}, nil
 	}
 	if _, ok := err.(*geth.ErrStackUnderflow); ok {
-		return tosca.Result{Success: false}, nil
+		return tosca.Result{Success: false, FailureCause: tosca.FailureStackViolation}, nil
 	}
 	if _, ok := err.(*geth.ErrInvalidOpCode); ok {
-		return tosca.Result{Success: false}, nil
+		return tosca.Result{Success: false, FailureCause: tosca.FailureInvalidOpCode}, nil
 	}
 
 	// In all other cases an EVM error should be reported.
@@ -109,6 +158,7 @@ func MakeChainConfig(baseline params.ChainConfig, chainId *big.Int, targetRevisi
 	parisBlock := ct.GetForkBlock(tosca.R11_Paris)
 	shanghaiTime := ct.GetForkTime(tosca.R12_Shanghai)
 	cancunTime := ct.GetForkTime(tosca.R13_Cancun)
+	pragueTime := ct.GetForkTime(tosca.R14_Prague)
 
 	chainConfig := baseline
 	chainConfig.ChainID = chainId
@@ -126,6 +176,12 @@ func MakeChainConfig(baseline params.ChainConfig, chainId *big.Int, targetRevisi
 	if targetRevision >= tosca.R13_Cancun {
 		chainConfig.CancunTime = &cancunTime
 	}
+	// While the geth interpreter does not run Prague code itself, the opera
+	// processor uses this config to run the geth EVM on Prague blocks with
+	// Tosca interpreters supporting this revision.
+	if targetRevision >= tosca.R14_Prague {
+		chainConfig.PragueTime = &pragueTime
+	}
 
 	return chainConfig
 }
@@ -180,6 +236,9 @@ func createGethInterpreterContext(parameters tosca.Parameters) (*geth.EVM, *geth
 	config := geth.Config{}
 
 	stateDb := &stateDbAdapter{context: parameters.Context}
+	if parameters.Tracer != nil {
+		config.Tracer = newTracingHooks(parameters.Tracer, parameters.Depth, parameters.Sender, stateDb)
+	}
 	evm := geth.NewEVM(blockCtx, txCtx, stateDb, &chainConfig, config)
 
 	evm.Origin = common.Address(parameters.Origin)
@@ -199,6 +258,34 @@ func createGethInterpreterContext(parameters tosca.Parameters) (*geth.EVM, *geth
 	return evm, contract, stateDb
 }
 
+// newTracingHooks creates geth tracing hooks forwarding the execution of
+// instructions and nested call frames to the given tracer. The depth of the
+// top-level call frame is used to convert geth's internal call depth to
+// Tosca's call depth.
+func newTracingHooks(tracer tosca.Tracer, depth int, sender tosca.Address, stateDb *stateDbAdapter) *tracing.Hooks {
+	calls := NewCallTracingHooks(tracer, depth, sender)
+	return &tracing.Hooks{
+		OnEnter: calls.OnEnter,
+		OnExit:  calls.OnExit,
+		OnOpcode: func(pc uint64, op byte, gas, _ uint64, scope tracing.OpContext, _ []byte, gethDepth int, _ error) {
+			stackData := scope.StackData()
+			stack := make([]tosca.Word, len(stackData))
+			for i := range stackData {
+				stack[i] = stackData[i].Bytes32()
+			}
+			tracer.OnStep(tosca.StepInfo{
+				Depth:  depth + gethDepth - 1,
+				Pc:     int(pc),
+				Op:     vm.OpCode(op),
+				Gas:    tosca.Gas(gas),
+				Refund: tosca.Gas(stateDb.refund),
+				Stack:  stack,
+				Memory: scope.MemoryData(),
+			})
+		},
+	}
+}
+
 // --- Adapter ---
 
 // transferFunc subtracts amount from sender and adds amount to recipient using the given Db
@@ -234,8 +321,8 @@ func (s *stateDbAdapter) CreateAccount(common.Address) {
 	// ignored: effect not needed in test environments
 }
 
-func (s *stateDbAdapter) CreateContract(common.Address) {
-	// ignored: effect not needed in test environments
+func (s *stateDbAdapter) CreateContract(address common.Address) {
+	s.context.CreateContract(tosca.Address(address))
 }
 
 func (s *stateDbAdapter) SubBalance(addr common.Address, diff *uint256.Int, _ tracing.BalanceChangeReason) {
@@ -428,10 +515,10 @@ func (s *stateDbAdapter) ForEachStorage(common.Address, func(common.Hash, common
 
 func (s *stateDbAdapter) PointCache() *utils.PointCache {
 	// see https://eips.ethereum.org/EIPS/eip-4762
-	panic("should not be needed by revisions up to Cancun")
+	panic("should not be needed by revisions up to Prague")
 }
 
 func (s *stateDbAdapter) Witness() *stateless.Witness {
-	// this should not be relevant for revisions up to Cancun
+	// this should not be relevant for revisions up to Prague
 	return nil
 }
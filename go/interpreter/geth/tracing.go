@@ -0,0 +1,115 @@
+// Copyright (c) 2024 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package geth
+
+import (
+	"math/big"
+
+	"github.com/Fantom-foundation/Tosca/go/tosca"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	geth "github.com/ethereum/go-ethereum/core/vm"
+	"github.com/holiman/uint256"
+)
+
+// NewCallTracingHooks creates geth tracing hooks forwarding the begin and end
+// of call frames processed by a geth EVM to the given tracer. The given depth
+// is added to geth's call depth to obtain the depth reported to the tracer.
+// The given sender is the sender of the call frame enclosing all frames
+// processed by the EVM, which is inherited by delegate calls.
+func NewCallTracingHooks(tracer tosca.Tracer, depth int, sender tosca.Address) *tracing.Hooks {
+	type frame struct {
+		gas     uint64
+		kind    tosca.CallKind
+		address tosca.Address
+		sender  tosca.Address
+	}
+	frames := []frame{}
+	return &tracing.Hooks{
+		OnEnter: func(gethDepth int, typ byte, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+			kind := toCallKind(geth.OpCode(typ))
+			var callValue tosca.Value
+			if value != nil {
+				callValue = tosca.ValueFromUint256(uint256.MustFromBig(value))
+			}
+			// Geth reports the caller and the target of calls, while Tosca
+			// reports the accounts whose context the target code is run in.
+			parameters := tosca.CallParameters{
+				Sender:      tosca.Address(from),
+				Recipient:   tosca.Address(to),
+				Value:       callValue,
+				Input:       input,
+				Gas:         tosca.Gas(gas &^ readOnlyGasFlag),
+				CodeAddress: tosca.Address(to),
+			}
+			switch kind {
+			case tosca.DelegateCall:
+				parameters.Sender = sender
+				if len(frames) > 0 {
+					parameters.Sender = frames[len(frames)-1].sender
+				}
+				parameters.Recipient = tosca.Address(from)
+			case tosca.CallCode:
+				parameters.Recipient = tosca.Address(from)
+			}
+			frames = append(frames, frame{
+				gas:     gas,
+				kind:    kind,
+				address: tosca.Address(to),
+				sender:  parameters.Sender,
+			})
+			tracer.OnEnter(depth+gethDepth, kind, parameters)
+		},
+		OnExit: func(gethDepth int, output []byte, gasUsed uint64, err error, _ bool) {
+			// Exits without a matching enter can not be reported consistently.
+			if len(frames) == 0 {
+				return
+			}
+			current := frames[len(frames)-1]
+			frames = frames[:len(frames)-1]
+			result := tosca.CallResult{
+				Output:       output,
+				GasLeft:      tosca.Gas((current.gas - gasUsed) &^ readOnlyGasFlag),
+				Success:      err == nil,
+				FailureCause: ToFailureCause(err),
+			}
+			if result.FailureCause == tosca.FailureRevert {
+				result.RevertReason, _ = tosca.DecodeRevertReason(output)
+			}
+			if current.kind == tosca.Create || current.kind == tosca.Create2 {
+				result.CreatedAddress = current.address
+			}
+			tracer.OnExit(depth+gethDepth, result, nil)
+		},
+	}
+}
+
+// readOnlyGasFlag is the bit used by the geth adapter to encode the read-only
+// mode of nested calls into the gas value passed to the geth EVM. It is
+// ignored when reporting gas values to tracers.
+const readOnlyGasFlag = uint64(1) << 63
+
+func toCallKind(op geth.OpCode) tosca.CallKind {
+	switch op {
+	case geth.DELEGATECALL:
+		return tosca.DelegateCall
+	case geth.STATICCALL:
+		return tosca.StaticCall
+	case geth.CALLCODE:
+		return tosca.CallCode
+	case geth.CREATE:
+		return tosca.Create
+	case geth.CREATE2:
+		return tosca.Create2
+	default:
+		return tosca.Call
+	}
+}
@@ -0,0 +1,161 @@
+// Copyright (c) 2024 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package geth
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/Fantom-foundation/Tosca/go/tosca"
+	"github.com/ethereum/go-ethereum/common"
+	geth "github.com/ethereum/go-ethereum/core/vm"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCallTracingHooks_ReportsCallsOfAllKinds(t *testing.T) {
+	sender := tosca.Address{1}
+	caller := tosca.Address{2}
+	target := tosca.Address{3}
+
+	tests := map[string]struct {
+		op                 geth.OpCode
+		kind               tosca.CallKind
+		wantSender         tosca.Address
+		wantRecipient      tosca.Address
+		wantCreatedAddress tosca.Address
+	}{
+		"call": {
+			op:            geth.CALL,
+			kind:          tosca.Call,
+			wantSender:    caller,
+			wantRecipient: target,
+		},
+		"static call": {
+			op:            geth.STATICCALL,
+			kind:          tosca.StaticCall,
+			wantSender:    caller,
+			wantRecipient: target,
+		},
+		"delegate call": {
+			op:            geth.DELEGATECALL,
+			kind:          tosca.DelegateCall,
+			wantSender:    sender,
+			wantRecipient: caller,
+		},
+		"call code": {
+			op:            geth.CALLCODE,
+			kind:          tosca.CallCode,
+			wantSender:    caller,
+			wantRecipient: caller,
+		},
+		"create": {
+			op:                 geth.CREATE,
+			kind:               tosca.Create,
+			wantSender:         caller,
+			wantRecipient:      target,
+			wantCreatedAddress: target,
+		},
+		"create2": {
+			op:                 geth.CREATE2,
+			kind:               tosca.Create2,
+			wantSender:         caller,
+			wantRecipient:      target,
+			wantCreatedAddress: target,
+		},
+	}
+
+	for name, test := range tests {
+		for _, readOnly := range []bool{false, true} {
+			t.Run(fmt.Sprintf("%s/readOnly=%t", name, readOnly), func(t *testing.T) {
+				ctrl := gomock.NewController(t)
+				tracer := tosca.NewMockTracer(ctrl)
+
+				gas := uint64(1000)
+				if readOnly {
+					gas |= readOnlyGasFlag
+				}
+
+				gomock.InOrder(
+					tracer.EXPECT().OnEnter(3, test.kind, tosca.CallParameters{
+						Sender:      test.wantSender,
+						Recipient:   test.wantRecipient,
+						Value:       tosca.NewValue(5),
+						Input:       []byte{1, 2},
+						Gas:         1000,
+						CodeAddress: target,
+					}),
+					tracer.EXPECT().OnExit(3, tosca.CallResult{
+						Output:         []byte{3},
+						GasLeft:        600,
+						Success:        true,
+						CreatedAddress: test.wantCreatedAddress,
+					}, nil),
+				)
+
+				hooks := NewCallTracingHooks(tracer, 2, sender)
+				hooks.OnEnter(1, byte(test.op), common.Address(caller), common.Address(target), []byte{1, 2}, gas, big.NewInt(5))
+				hooks.OnExit(1, []byte{3}, 400, nil, false)
+			})
+		}
+	}
+}
+
+func TestCallTracingHooks_DelegateCallInheritsSenderOfEnclosingFrame(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	tracer := tosca.NewMockTracer(ctrl)
+
+	outer := tosca.Address{1}
+	caller := tosca.Address{2}
+	target := tosca.Address{3}
+
+	gomock.InOrder(
+		tracer.EXPECT().OnEnter(1, tosca.Call, gomock.Any()),
+		tracer.EXPECT().OnEnter(2, tosca.DelegateCall, tosca.CallParameters{
+			Sender:      outer,
+			Recipient:   caller,
+			Gas:         10,
+			CodeAddress: target,
+		}),
+		tracer.EXPECT().OnExit(2, gomock.Any(), nil),
+		tracer.EXPECT().OnExit(1, gomock.Any(), nil),
+	)
+
+	hooks := NewCallTracingHooks(tracer, 0, tosca.Address{})
+	hooks.OnEnter(1, byte(geth.CALL), common.Address(outer), common.Address(caller), nil, 100, nil)
+	hooks.OnEnter(2, byte(geth.DELEGATECALL), common.Address(caller), common.Address(target), nil, 10, nil)
+	hooks.OnExit(2, nil, 0, nil, false)
+	hooks.OnExit(1, nil, 0, nil, false)
+}
+
+func TestCallTracingHooks_ExitWithoutEnterIsIgnored(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	tracer := tosca.NewMockTracer(ctrl)
+
+	hooks := NewCallTracingHooks(tracer, 0, tosca.Address{})
+	hooks.OnExit(0, nil, 0, nil, false)
+}
+
+func TestCallTracingHooks_FailedCallsReportFailureCause(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	tracer := tosca.NewMockTracer(ctrl)
+
+	gomock.InOrder(
+		tracer.EXPECT().OnEnter(0, tosca.Call, gomock.Any()),
+		tracer.EXPECT().OnExit(0, tosca.CallResult{
+			FailureCause: tosca.FailureOutOfGas,
+		}, nil),
+	)
+
+	hooks := NewCallTracingHooks(tracer, 0, tosca.Address{})
+	hooks.OnEnter(0, byte(geth.CALL), common.Address{}, common.Address{}, nil, 100, nil)
+	hooks.OnExit(0, nil, 100, geth.ErrOutOfGas, false)
+}
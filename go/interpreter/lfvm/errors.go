@@ -25,3 +25,24 @@ const (
 	errStackUnderflow         = tosca.ConstError("stack underflow")
 	errStackOverflow          = tosca.ConstError("stack overflow")
 )
+
+// getFailureCause classifies the given violation encountered during the
+// execution of a contract.
+func getFailureCause(err error) tosca.FailureCause {
+	switch err {
+	case errOutOfGas, errMaxMemoryExpansionSize:
+		return tosca.FailureOutOfGas
+	case errInvalidOpCode, errInvalidRevision:
+		return tosca.FailureInvalidOpCode
+	case errInvalidJump:
+		return tosca.FailureInvalidJump
+	case errStackLimitsViolation, errStackUnderflow, errStackOverflow:
+		return tosca.FailureStackViolation
+	case errStaticContextViolation:
+		return tosca.FailureStaticViolation
+	case errInitCodeTooLarge:
+		return tosca.FailureCodeSizeLimit
+	default:
+		return tosca.FailureOther
+	}
+}
@@ -59,6 +59,7 @@ func TestGas_getDynamicCostsForSstore_exhaustive(t *testing.T) {
 	specs[tosca.R11_Paris] = specs[tosca.R10_London]
 	specs[tosca.R12_Shanghai] = specs[tosca.R11_Paris]
 	specs[tosca.R13_Cancun] = specs[tosca.R12_Shanghai]
+	specs[tosca.R14_Prague] = specs[tosca.R13_Cancun]
 
 	// Check that gas prices are computed correctly.
 	for _, revision := range tosca.GetAllKnownRevisions() {
@@ -135,6 +136,7 @@ func TestGas_getRefundForSstore_exhaustive(t *testing.T) {
 	specs[tosca.R11_Paris] = specs[tosca.R10_London]
 	specs[tosca.R12_Shanghai] = specs[tosca.R11_Paris]
 	specs[tosca.R13_Cancun] = specs[tosca.R12_Shanghai]
+	specs[tosca.R14_Prague] = specs[tosca.R13_Cancun]
 
 	// Check that gas prices are computed correctly.
 	for _, revision := range tosca.GetAllKnownRevisions() {
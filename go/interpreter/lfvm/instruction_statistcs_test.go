@@ -12,7 +12,7 @@ package lfvm
 
 import (
 	"fmt"
-	"strings"
+	"reflect"
 	"testing"
 
 	"github.com/Fantom-foundation/Tosca/go/tosca"
@@ -44,37 +44,42 @@ func TestStatisticsRunner_RunWithStatistics(t *testing.T) {
 	}
 }
 
-func TestStatisticsRunner_DumpProfilePrintsExpectedOutput(t *testing.T) {
-
+func TestStatisticsRunner_GetProfileReturnsExpectedStatistics(t *testing.T) {
 	tests := map[string]struct {
-		code         tosca.Code
-		findInOutput []string
+		code tosca.Code
+		want tosca.Profile
 	}{
 		"singles": {tosca.Code{byte(vm.STOP)},
-			[]string{
-				"Steps: 1",
-				"STOP                          : 1 (100.00%)",
+			tosca.Profile{
+				Steps:        1,
+				Instructions: map[string]tosca.ExecutionStats{"STOP": {Count: 1}},
+				Pairs:        map[string]uint64{},
+				Triples:      map[string]uint64{},
+				Quads:        map[string]uint64{},
 			}},
 		"pairs": {tosca.Code{byte(vm.PUSH1), 0x01, byte(vm.STOP)},
-			[]string{
-				"Steps: 2",
-				"PUSH1                         : 1 (50.00%)",
-				"STOP                          : 1 (50.00%)",
-				"PUSH1                         STOP                          : 1"}},
+			tosca.Profile{
+				Steps:        2,
+				Instructions: map[string]tosca.ExecutionStats{"PUSH1": {Count: 1}, "STOP": {Count: 1}},
+				Pairs:        map[string]uint64{"PUSH1 STOP": 1},
+				Triples:      map[string]uint64{},
+				Quads:        map[string]uint64{},
+			}},
 		"triples": {tosca.Code{byte(vm.PUSH1), 0x01, byte(vm.PUSH1), 0x01, byte(vm.STOP)},
-			[]string{
-				"Steps: 3",
-				"PUSH1                         : 2 (66.67%)",
-				"STOP                          : 1 (33.33%)",
-				"PUSH1                         PUSH1                         STOP                          : 1"}},
+			tosca.Profile{
+				Steps:        3,
+				Instructions: map[string]tosca.ExecutionStats{"PUSH1": {Count: 2}, "STOP": {Count: 1}},
+				Pairs:        map[string]uint64{"PUSH1 PUSH1": 1, "PUSH1 STOP": 1},
+				Triples:      map[string]uint64{"PUSH1 PUSH1 STOP": 1},
+				Quads:        map[string]uint64{},
+			}},
 		"quads": {tosca.Code{byte(vm.PUSH1), 0x01, byte(vm.PUSH1), 0x01, byte(vm.PUSH1), 0x01, byte(vm.STOP)},
-			[]string{
-				"Steps: 4",
-				"PUSH1                         : 3 (75.00%)",
-				"STOP                          : 1 (25.00%)",
-				"PUSH1                         PUSH1                         PUSH1                         : 1 (25.00%)",
-				"PUSH1                         PUSH1                         STOP                          : 1 (25.00%)",
-				"PUSH1                         PUSH1                         PUSH1                         STOP                          : 1 (25.00%)",
+			tosca.Profile{
+				Steps:        4,
+				Instructions: map[string]tosca.ExecutionStats{"PUSH1": {Count: 3}, "STOP": {Count: 1}},
+				Pairs:        map[string]uint64{"PUSH1 PUSH1": 2, "PUSH1 STOP": 1},
+				Triples:      map[string]uint64{"PUSH1 PUSH1 PUSH1": 1, "PUSH1 PUSH1 STOP": 1},
+				Quads:        map[string]uint64{"PUSH1 PUSH1 PUSH1 STOP": 1},
 			}},
 	}
 
@@ -98,24 +103,55 @@ func TestStatisticsRunner_DumpProfilePrintsExpectedOutput(t *testing.T) {
 				t.Fatalf("Failed to run code: %v", err)
 			}
 
-			// Run testing code
-			instance.DumpProfile()
-
-			out := statsRunner.stats.print()
-			for _, s := range test.findInOutput {
-				if !strings.Contains(string(out), s) {
-					t.Errorf("did not find occurrences of %v in %v", s, string(out))
-				}
+			if got := instance.GetProfile(); !reflect.DeepEqual(test.want, got) {
+				t.Errorf("unexpected profile, wanted %v, got %v", test.want, got)
 			}
 		})
 	}
 }
 
-func TestStatisticsRunner_getSummaryInitializesNewStatsWhenUninitialized(t *testing.T) {
+func TestStatisticsRunner_GetProfileReturnsSnapshot(t *testing.T) {
+	instance, err := newVm(config{
+		runner: &statisticRunner{},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create VM: %v", err)
+	}
+	params := tosca.Parameters{Gas: 10, Code: tosca.Code{byte(vm.STOP)}}
+	if _, err := instance.Run(params); err != nil {
+		t.Fatalf("Failed to run code: %v", err)
+	}
+	profile := instance.GetProfile()
+	if _, err := instance.Run(params); err != nil {
+		t.Fatalf("Failed to run code: %v", err)
+	}
+	if want, got := uint64(1), profile.Instructions["STOP"].Count; want != got {
+		t.Errorf("snapshot was modified, wanted %d, got %d", want, got)
+	}
+	if want, got := uint64(2), instance.GetProfile().Steps; want != got {
+		t.Errorf("unexpected number of steps, wanted %d, got %d", want, got)
+	}
+	instance.ResetProfile()
+	if want, got := uint64(0), instance.GetProfile().Steps; want != got {
+		t.Errorf("unexpected number of steps after reset, wanted %d, got %d", want, got)
+	}
+}
+
+func TestLfvm_GetProfileIsEmptyWithoutStatisticsRunner(t *testing.T) {
+	instance, err := newVm(config{})
+	if err != nil {
+		t.Fatalf("Failed to create VM: %v", err)
+	}
+	if got := instance.GetProfile(); !reflect.DeepEqual(tosca.Profile{}, got) {
+		t.Errorf("unexpected profile: %v", got)
+	}
+}
+
+func TestStatisticsRunner_getProfileInitializesNewStatsWhenUninitialized(t *testing.T) {
 	statsRunner := &statisticRunner{
 		stats: nil,
 	}
-	_ = statsRunner.getSummary()
+	_ = statsRunner.getProfile()
 	if statsRunner.stats == nil {
 		t.Errorf("summary should have been initialized")
 	}
@@ -149,29 +185,22 @@ func TestStatisticsRunner_statisticsStopsWhenExecutionEncountersAnError(t *testi
 	}
 }
 
-func TestStatisticsRunner_print_getTopN_returnFirstNElementsOfManyMore(t *testing.T) {
-	want := "\n----- Statistics ------\n"
-	want += "\nSteps: 0\n"
-	want += "\nSingles:\n"
-	want += "\tPUSH5                         : 5 (+Inf%)\n"
-	want += "\tPUSH4                         : 4 (+Inf%)\n"
-	want += "\tPUSH3                         : 3 (+Inf%)\n"
-	want += "\tPUSH2                         : 2 (+Inf%)\n"
-	want += "\tSTOP                          : 1 (+Inf%)\n"
-	want += "\nPairs:\n\nTriples:\n\nQuads:\n\n"
-
+func TestStatistics_toProfile_ConvertsSequencesToInstructionNames(t *testing.T) {
 	stats := statistics{
-		singleCount: map[uint64]uint64{
-			uint64(STOP):  1,
-			uint64(PUSH1): 1,
-			uint64(PUSH2): 2,
-			uint64(PUSH3): 3,
-			uint64(PUSH4): 4,
-			uint64(PUSH5): 5,
-		},
-	}
-	got := stats.print()
-	if got != want {
-		t.Errorf("unexpected output: want %v, got %v", want, got)
+		count:       4,
+		singleCount: map[uint64]uint64{uint64(PUSH1): 3, uint64(JUMP_TO): 1},
+		pairCount:   map[uint64]uint64{uint64(PUSH1)<<16 | uint64(JUMP_TO): 1},
+		tripleCount: map[uint64]uint64{uint64(PUSH1)<<32 | uint64(PUSH1)<<16 | uint64(JUMP_TO): 1},
+		quadCount:   map[uint64]uint64{uint64(STOP)<<48 | uint64(PUSH1)<<32 | uint64(PUSH1)<<16 | uint64(JUMP_TO): 1},
+	}
+	want := tosca.Profile{
+		Steps:        4,
+		Instructions: map[string]tosca.ExecutionStats{"PUSH1": {Count: 3}, "JUMP_TO": {Count: 1}},
+		Pairs:        map[string]uint64{"PUSH1 JUMP_TO": 1},
+		Triples:      map[string]uint64{"PUSH1 PUSH1 JUMP_TO": 1},
+		Quads:        map[string]uint64{"STOP PUSH1 PUSH1 JUMP_TO": 1},
+	}
+	if got := stats.toProfile(); !reflect.DeepEqual(want, got) {
+		t.Errorf("unexpected profile, wanted %v, got %v", want, got)
 	}
 }
@@ -11,10 +11,10 @@
 package lfvm
 
 import (
-	"fmt"
-	"sort"
 	"strings"
 	"sync"
+
+	"github.com/Fantom-foundation/Tosca/go/tosca"
 )
 
 // statisticRunner is a runner that collects statistics about the instruction
@@ -42,15 +42,14 @@ func (s *statisticRunner) run(c *context) (status, error) {
 	return status, nil
 }
 
-// getSummary returns a summary of the collected statistics in a human-readable
-// format.
-func (s *statisticRunner) getSummary() string {
+// getProfile returns a snapshot of the collected statistics.
+func (s *statisticRunner) getProfile() tosca.Profile {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 	if s.stats == nil {
 		s.stats = newStatistics()
 	}
-	return s.stats.print()
+	return s.stats.toProfile()
 }
 
 // reset clears the collected statistics.
@@ -97,58 +96,34 @@ func (s *statistics) insert(src *statistics) {
 	}
 }
 
-// print returns a human-readable summary of the collected statistics.
-func (s *statistics) print() string {
-
-	type entry struct {
-		value uint64
-		count uint64
-	}
-
-	getTopN := func(data map[uint64]uint64, n int) []entry {
-		list := make([]entry, 0, len(data))
-		for k, c := range data {
-			list = append(list, entry{k, c})
-		}
-		sort.Slice(list, func(i, j int) bool {
-			if list[i].count != list[j].count {
-				return list[i].count > list[j].count
-			}
-			return list[i].value < list[j].value
-		})
-		if len(list) < n {
-			return list
-		}
-		return list[0:n]
-	}
-
-	builder := strings.Builder{}
-	write := func(format string, args ...interface{}) {
-		builder.WriteString(fmt.Sprintf(format, args...))
-	}
-
-	write("\n----- Statistics ------\n")
-	write("\nSteps: %d\n", s.count)
-	write("\nSingles:\n")
-	for _, e := range getTopN(s.singleCount, 5) {
-		write("\t%-30v: %d (%.2f%%)\n", OpCode(e.value), e.count, float32(e.count*100)/float32(s.count))
-	}
-	write("\nPairs:\n")
-	for _, e := range getTopN(s.pairCount, 5) {
-		write("\t%-30v%-30v: %d (%.2f%%)\n", OpCode(e.value>>16), OpCode(e.value), e.count, float32(e.count*100)/float32(s.count))
+// toProfile converts the collected statistics into a profile.
+func (s *statistics) toProfile() tosca.Profile {
+	res := tosca.Profile{
+		Steps:        s.count,
+		Instructions: make(map[string]tosca.ExecutionStats, len(s.singleCount)),
+		Pairs:        toSequenceCounts(s.pairCount, 2),
+		Triples:      toSequenceCounts(s.tripleCount, 3),
+		Quads:        toSequenceCounts(s.quadCount, 4),
 	}
-	write("\nTriples:\n")
-	for _, e := range getTopN(s.tripleCount, 5) {
-		write("\t%-30v%-30v%-30v: %d (%.2f%%)\n", OpCode(e.value>>32), OpCode(e.value>>16), OpCode(e.value), e.count, float32(e.count*100)/float32(s.count))
+	for key, count := range s.singleCount {
+		res.Instructions[OpCode(key).String()] = tosca.ExecutionStats{Count: count}
 	}
+	return res
+}
 
-	write("\nQuads:\n")
-	for _, e := range getTopN(s.quadCount, 5) {
-		write("\t%-30v%-30v%-30v%-30v: %d (%.2f%%)\n", OpCode(e.value>>48), OpCode(e.value>>32), OpCode(e.value>>16), OpCode(e.value), e.count, float32(e.count*100)/float32(s.count))
+// toSequenceCounts converts the given counters of instruction sequences of
+// the given length into counters indexed by the space-separated instruction
+// names, as required by tosca.Profile.
+func toSequenceCounts(counts map[uint64]uint64, length int) map[string]uint64 {
+	res := make(map[string]uint64, len(counts))
+	names := make([]string, length)
+	for key, count := range counts {
+		for i := range names {
+			names[length-1-i] = OpCode(key >> (16 * i)).String()
+		}
+		res[strings.Join(names, " ")] += count
 	}
-	write("\n")
-
-	return builder.String()
+	return res
 }
 
 // statsCollector is a helper struct that keeps track of the resent history of
@@ -0,0 +1,55 @@
+// Copyright (c) 2024 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package lfvm
+
+import (
+	"github.com/Fantom-foundation/Tosca/go/tosca"
+	"github.com/Fantom-foundation/Tosca/go/tosca/vm"
+)
+
+// tracingRunner is a runner that reports the execution of each instruction
+// to the tracer provided through the run parameters. Instructions are
+// reported in terms of the original EVM code. Thus, the code executed by this
+// runner must have been converted without super instructions.
+type tracingRunner struct{}
+
+func (tracingRunner) run(c *context) (status, error) {
+	tracer := c.params.Tracer
+	pcMap := genPcMap(c.params.Code)
+	status := statusRunning
+	for status == statusRunning {
+		// Instructions introduced by the code conversion (e.g. JUMP_TO) are
+		// not part of the original code and are thus not reported.
+		if int(c.pc) < len(c.code) && c.code[c.pc].opcode.isBaseInstruction() {
+			tracer.OnStep(getStepInfo(c, int(pcMap.lfvmToEvm[c.pc])))
+		}
+		status = execute(c, true)
+	}
+	return status, nil
+}
+
+// getStepInfo summarizes the current state of the given context for the
+// instruction located at the given position of the original EVM code.
+func getStepInfo(c *context, pc int) tosca.StepInfo {
+	stack := make([]tosca.Word, c.stack.len())
+	for i := range stack {
+		stack[i] = c.stack.get(i).Bytes32()
+	}
+	return tosca.StepInfo{
+		Depth:  c.params.Depth,
+		Pc:     pc,
+		Op:     vm.OpCode(c.code[c.pc].opcode),
+		Gas:    c.gas,
+		Refund: c.refund,
+		Stack:  stack,
+		Memory: c.memory.store,
+	}
+}
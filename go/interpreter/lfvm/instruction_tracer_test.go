@@ -0,0 +1,117 @@
+// Copyright (c) 2024 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package lfvm
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/Fantom-foundation/Tosca/go/tosca"
+	"github.com/Fantom-foundation/Tosca/go/tosca/vm"
+	"go.uber.org/mock/gomock"
+)
+
+func TestTracingRunner_ReportsStepsInTermsOfEvmCode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	tracer := tosca.NewMockTracer(ctrl)
+
+	code := []byte{
+		byte(vm.PUSH2), 0, 7,
+		byte(vm.JUMP),
+		byte(vm.PUSH2), 0, 0,
+		byte(vm.JUMPDEST),
+		byte(vm.PUSH1), 1,
+		byte(vm.STOP),
+	}
+
+	gomock.InOrder(
+		tracer.EXPECT().OnStep(tosca.StepInfo{Depth: 2, Pc: 0, Op: vm.PUSH2, Gas: 100, Stack: []tosca.Word{}}),
+		tracer.EXPECT().OnStep(tosca.StepInfo{Depth: 2, Pc: 3, Op: vm.JUMP, Gas: 97, Stack: []tosca.Word{{31: 7}}}),
+		tracer.EXPECT().OnStep(tosca.StepInfo{Depth: 2, Pc: 7, Op: vm.JUMPDEST, Gas: 89, Stack: []tosca.Word{}}),
+		tracer.EXPECT().OnStep(tosca.StepInfo{Depth: 2, Pc: 8, Op: vm.PUSH1, Gas: 88, Stack: []tosca.Word{}}),
+		tracer.EXPECT().OnStep(tosca.StepInfo{Depth: 2, Pc: 10, Op: vm.STOP, Gas: 85, Stack: []tosca.Word{{31: 1}}}),
+	)
+
+	for _, withSuperInstructions := range []bool{true, false} {
+		instance, err := newVm(config{
+			ConversionConfig: ConversionConfig{WithSuperInstructions: withSuperInstructions},
+		})
+		if err != nil {
+			t.Fatalf("failed to create vm: %v", err)
+		}
+
+		result, err := instance.Run(tosca.Parameters{
+			Code:   code,
+			Gas:    100,
+			Depth:  2,
+			Tracer: tracer,
+		})
+		if err != nil || !result.Success {
+			t.Fatalf("unexpected result: %v, %v", result, err)
+		}
+		if want, got := tosca.Gas(85), result.GasLeft; want != got {
+			t.Errorf("unexpected gas left, wanted %d, got %d", want, got)
+		}
+		// The mocked steps are only expected once, all following runs are
+		// checked for their result only.
+		tracer.EXPECT().OnStep(gomock.Any()).AnyTimes()
+	}
+}
+
+func TestTracingRunner_ConvertedCodeIsCachedWithoutSuperInstructions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	tracer := tosca.NewMockTracer(ctrl)
+	tracer.EXPECT().OnStep(gomock.Any()).AnyTimes()
+
+	code := []byte{byte(vm.PUSH1), 1, byte(vm.PUSH1), 1, byte(vm.STOP)}
+	hash := tosca.Hash{1}
+
+	for _, withSuperInstructions := range []bool{true, false} {
+		instance, err := newVm(config{
+			ConversionConfig: ConversionConfig{WithSuperInstructions: withSuperInstructions},
+		})
+		if err != nil {
+			t.Fatalf("failed to create vm: %v", err)
+		}
+
+		result, err := instance.Run(tosca.Parameters{
+			Code:     code,
+			CodeHash: &hash,
+			Gas:      100,
+			Tracer:   tracer,
+		})
+		if err != nil || !result.Success {
+			t.Fatalf("unexpected result: %v, %v", result, err)
+		}
+
+		cached, found := instance.tracingConverter.cache.Get(hash)
+		if !found {
+			t.Fatalf("converted code was not cached")
+		}
+		if want, got := convert(code, ConversionConfig{}), cached; !slices.Equal(want, got) {
+			t.Errorf("unexpected cached code, wanted %v, got %v", want, got)
+		}
+	}
+}
+
+func TestTracingRunner_IsNotUsedWithoutTracer(t *testing.T) {
+	instance, err := newVm(config{})
+	if err != nil {
+		t.Fatalf("failed to create vm: %v", err)
+	}
+	result, err := instance.Run(tosca.Parameters{
+		Code: []byte{byte(vm.PUSH1), 1, byte(vm.STOP)},
+		Gas:  100,
+	})
+	if err != nil || !result.Success {
+		t.Fatalf("unexpected result: %v, %v", result, err)
+	}
+}
@@ -952,6 +952,16 @@ func genericCall(c *context, kind tosca.CallKind) error {
 		}
 	}
 
+	// from prague onwards calls of accounts delegating their code execution
+	// also pay for accessing the delegation target (EIP-7702).
+	if c.isAtLeast(tosca.R14_Prague) {
+		if target, delegated := tosca.GetDelegationTarget(c.context.GetCode(toAddr)); delegated {
+			if err := c.useGas(getAccessCost(c.context.AccessAccount(target))); err != nil {
+				return err
+			}
+		}
+	}
+
 	// for static and delegate calls, the following value checks will always be zero.
 	// Charge for transferring value to a new address
 	if !value.IsZero() {
@@ -739,6 +739,51 @@ func TestCall_ChargesForAccessAfterBerlin(t *testing.T) {
 	}
 }
 
+func TestCall_ChargesForAccessOfDelegationTargetSincePrague(t *testing.T) {
+	one := *uint256.NewInt(1)
+	zero := *uint256.NewInt(0)
+	target := tosca.Address{0x42}
+	for _, revision := range []tosca.Revision{tosca.R13_Cancun, tosca.R14_Prague} {
+		for _, accessStatus := range []tosca.AccessStatus{tosca.WarmAccess, tosca.ColdAccess} {
+			t.Run(fmt.Sprintf("%v/%v", revision, accessStatus), func(t *testing.T) {
+				ctrl := gomock.NewController(t)
+				runContext := tosca.NewMockRunContext(ctrl)
+				runContext.EXPECT().AccessAccount(tosca.Address(one.Bytes20())).Return(tosca.WarmAccess)
+				if revision >= tosca.R14_Prague {
+					runContext.EXPECT().GetCode(tosca.Address(one.Bytes20())).Return(tosca.NewDelegationDesignator(target))
+					runContext.EXPECT().AccessAccount(target).Return(accessStatus)
+				}
+				runContext.EXPECT().Call(tosca.Call, gomock.Any()).Return(tosca.CallResult{}, nil)
+
+				ctxt := context{
+					params: tosca.Parameters{
+						BlockParameters: tosca.BlockParameters{
+							Revision: revision,
+						},
+					},
+					stack:   NewStack(),
+					memory:  NewMemory(),
+					context: runContext,
+					gas:     10_000,
+				}
+				ctxt.stack = fillStack(zero, one, zero, zero, zero, zero, zero, zero)
+
+				if err := genericCall(&ctxt, tosca.Call); err != nil {
+					t.Fatalf("genericCall failed: %v", err)
+				}
+
+				want := tosca.Gas(10_000 - 100)
+				if revision >= tosca.R14_Prague {
+					want -= getAccessCost(accessStatus)
+				}
+				if want != ctxt.gas {
+					t.Errorf("unexpected gas left, wanted %v, got %v", want, ctxt.gas)
+				}
+			})
+		}
+	}
+}
+
 func TestSelfDestruct_Refund(t *testing.T) {
 	tests := map[string]struct {
 		destructed bool
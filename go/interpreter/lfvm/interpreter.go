@@ -49,6 +49,7 @@ type context struct {
 
 	// Intermediate data
 	returnData []byte // < the result of the last nested contract call
+	failure    error  // < the violation causing a statusFailed, if any
 
 	// Configuration flags
 	withShaCache bool
@@ -138,13 +139,15 @@ func generateResult(status status, ctxt *context) (tosca.Result, error) {
 		}, nil
 	case statusReverted:
 		return tosca.Result{
-			Success: false,
-			Output:  ctxt.returnData,
-			GasLeft: ctxt.gas,
+			Success:      false,
+			Output:       ctxt.returnData,
+			GasLeft:      ctxt.gas,
+			FailureCause: tosca.FailureRevert,
 		}, nil
 	case statusFailed:
 		return tosca.Result{
-			Success: false,
+			Success:      false,
+			FailureCause: getFailureCause(ctxt.failure),
 		}, nil
 	default:
 		return tosca.Result{}, fmt.Errorf("unexpected error in interpreter, unknown status: %v", status)
@@ -170,6 +173,7 @@ func (r vanillaRunner) run(c *context) (status, error) {
 func execute(c *context, oneStepOnly bool) status {
 	status, error := steps(c, oneStepOnly)
 	if error != nil {
+		c.failure = error
 		return statusFailed
 	}
 	return status
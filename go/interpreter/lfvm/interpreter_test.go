@@ -357,10 +357,11 @@ func TestRun_GenerateResult(t *testing.T) {
 		"reverted": {
 			status: statusReverted,
 			expectedResult: tosca.Result{
-				Success:   false,
-				Output:    baseOutput,
-				GasLeft:   baseGas,
-				GasRefund: 0,
+				Success:      false,
+				Output:       baseOutput,
+				GasLeft:      baseGas,
+				GasRefund:    0,
+				FailureCause: tosca.FailureRevert,
 			},
 		},
 		"stopped": {
@@ -384,7 +385,8 @@ func TestRun_GenerateResult(t *testing.T) {
 		"failure": {
 			status: statusFailed,
 			expectedResult: tosca.Result{
-				Success: false,
+				Success:      false,
+				FailureCause: tosca.FailureOther,
 			},
 		},
 		"unknown status": {
@@ -582,6 +584,47 @@ func TestInterpreter_ExecuteReturnsFailureOnExecutionError(t *testing.T) {
 	}
 }
 
+func TestInterpreter_ExecuteRecordsViolationOfFailedExecution(t *testing.T) {
+	ctxt := context{
+		code:  generateCodeFor(INVALID),
+		stack: NewStack(),
+	}
+
+	execute(&ctxt, false)
+	if want, got := errInvalidOpCode, ctxt.failure; want != got {
+		t.Errorf("unexpected failure: want %v, got %v", want, got)
+	}
+	result, err := generateResult(statusFailed, &ctxt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want, got := tosca.FailureInvalidOpCode, result.FailureCause; want != got {
+		t.Errorf("unexpected failure cause: want %v, got %v", want, got)
+	}
+}
+
+func TestGetFailureCause_ClassifiesViolations(t *testing.T) {
+	tests := map[error]tosca.FailureCause{
+		errOutOfGas:               tosca.FailureOutOfGas,
+		errMaxMemoryExpansionSize: tosca.FailureOutOfGas,
+		errInvalidOpCode:          tosca.FailureInvalidOpCode,
+		errInvalidRevision:        tosca.FailureInvalidOpCode,
+		errInvalidJump:            tosca.FailureInvalidJump,
+		errStackUnderflow:         tosca.FailureStackViolation,
+		errStackOverflow:          tosca.FailureStackViolation,
+		errStackLimitsViolation:   tosca.FailureStackViolation,
+		errStaticContextViolation: tosca.FailureStaticViolation,
+		errInitCodeTooLarge:       tosca.FailureCodeSizeLimit,
+		errOverflow:               tosca.FailureOther,
+		nil:                       tosca.FailureOther,
+	}
+	for err, want := range tests {
+		if got := getFailureCause(err); want != got {
+			t.Errorf("unexpected cause for %v: want %v, got %v", err, want, got)
+		}
+	}
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // Benchmarks
 
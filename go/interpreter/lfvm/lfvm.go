@@ -12,31 +12,93 @@ package lfvm
 
 import (
 	"fmt"
+	"io"
 	"os"
 
 	"github.com/Fantom-foundation/Tosca/go/tosca"
 )
 
 // Config provides a set of user-definable options for the LFVM interpreter.
+// The zero value is the official configuration for production purposes.
 type Config struct {
+	// WithSuperInstructions enables the use of super instructions.
+	WithSuperInstructions bool
+	// WithoutShaCache disables the caching of SHA3 hashes.
+	WithoutShaCache bool
+	// CodeCacheSize is the maximum size of the cache of converted code in
+	// bytes. See ConversionConfig.CacheSize for details.
+	CodeCacheSize int
+	// WithStatistics enables the collection of instruction statistics which
+	// can be obtained through the interpreter's GetProfile method.
+	WithStatistics bool
+	// Log, if not nil, is the writer receiving a trace of all executed
+	// instructions in the EIP-3155 JSON format. Executions with an explicitly
+	// requested tracer are not logged. Logging can not be combined with
+	// statistics and is not thread-safe.
+	Log io.Writer
 }
 
-// NewInterpreter creates a new LFVM interpreter instance with the official
-// configuration for production purposes.
-func NewInterpreter(Config) (*lfvm, error) {
-	return newVm(config{
+// toConfig validates the user-defined options and converts them into the
+// internal configuration of the interpreter.
+func (c Config) toConfig() (config, error) {
+	res := config{
 		ConversionConfig: ConversionConfig{
-			WithSuperInstructions: false,
+			CacheSize:             c.CodeCacheSize,
+			WithSuperInstructions: c.WithSuperInstructions,
 		},
-		WithShaCache: true,
-	})
+		WithShaCache: !c.WithoutShaCache,
+	}
+	if c.WithStatistics && c.Log != nil {
+		return config{}, fmt.Errorf("statistics and logging can not be enabled at the same time")
+	}
+	if c.WithStatistics {
+		res.runner = &statisticRunner{
+			stats: newStatistics(),
+		}
+	}
+	if c.Log != nil {
+		res.logger = tosca.NewJsonTracer(c.Log)
+	}
+	return res, nil
+}
+
+// info describes the capabilities of interpreters using this configuration.
+func (c Config) info() tosca.InterpreterInfo {
+	return tosca.InterpreterInfo{
+		NewestSupportedRevision: newestSupportedRevision,
+		Steppable:               true,
+		Profiling:               c.WithStatistics,
+		Tracing:                 true,
+	}
+}
+
+// NewInterpreter creates a new LFVM interpreter instance with the given
+// configuration. An error is returned if the configuration is invalid.
+func NewInterpreter(config Config) (*lfvm, error) {
+	internal, err := config.toConfig()
+	if err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+	return newVm(internal)
 }
 
 // Registers the long-form EVM as a possible interpreter implementation.
 func init() {
-	tosca.MustRegisterInterpreterFactory("lfvm", func(any) (tosca.Interpreter, error) {
-		return NewInterpreter(Config{})
-	})
+	tosca.MustRegisterInterpreterFactory("lfvm", func(config any) (tosca.Interpreter, error) {
+		switch config := config.(type) {
+		case nil:
+			return NewInterpreter(Config{})
+		case Config:
+			return NewInterpreter(config)
+		case *Config:
+			if config == nil {
+				return NewInterpreter(Config{})
+			}
+			return NewInterpreter(*config)
+		default:
+			return nil, fmt.Errorf("unsupported configuration type for lfvm: %T", config)
+		}
+	}, Config{}.info())
 }
 
 // RegisterExperimentalInterpreterConfigurations registers all experimental
@@ -45,27 +107,19 @@ func init() {
 // not officially supported.
 func RegisterExperimentalInterpreterConfigurations() error {
 
-	configs := map[string]config{}
+	configs := map[string]Config{}
 
 	for _, si := range []string{"", "-si"} {
 		for _, shaCache := range []string{"", "-no-sha-cache"} {
 			for _, mode := range []string{"", "-stats", "-logging"} {
 
-				config := config{
-					ConversionConfig: ConversionConfig{
-						WithSuperInstructions: si == "-si",
-					},
-					WithShaCache: shaCache != "-no-sha-cache",
+				config := Config{
+					WithSuperInstructions: si == "-si",
+					WithoutShaCache:       shaCache == "-no-sha-cache",
+					WithStatistics:        mode == "-stats",
 				}
-
-				if mode == "-stats" {
-					config.runner = &statisticRunner{
-						stats: newStatistics(),
-					}
-				} else if mode == "-logging" {
-					config.runner = loggingRunner{
-						log: os.Stdout,
-					}
+				if mode == "-logging" {
+					config.Log = os.Stdout
 				}
 
 				name := "lfvm" + si + shaCache + mode
@@ -78,16 +132,18 @@ func RegisterExperimentalInterpreterConfigurations() error {
 		}
 	}
 
-	configs["lfvm-no-code-cache"] = config{
-		ConversionConfig: ConversionConfig{CacheSize: -1},
+	configs["lfvm-no-code-cache"] = Config{
+		WithoutShaCache: true,
+		CodeCacheSize:   -1,
 	}
 
 	for name, config := range configs {
 		err := tosca.RegisterInterpreterFactory(
 			name,
 			func(any) (tosca.Interpreter, error) {
-				return newVm(config)
+				return NewInterpreter(config)
 			},
+			config.info(),
 		)
 		if err != nil {
 			return fmt.Errorf("failed to register interpreter %q: %v", name, err)
@@ -101,11 +157,16 @@ type config struct {
 	ConversionConfig
 	WithShaCache bool
 	runner       runner
+	logger       *tosca.JsonTracer // < nil if logging is disabled
 }
 
 type lfvm struct {
 	config    config
 	converter *Converter
+	// tracingConverter converts code for traced runs, which are reported in
+	// terms of the original EVM code and thus require a conversion without
+	// super instructions.
+	tracingConverter *Converter
 }
 
 func newVm(config config) (*lfvm, error) {
@@ -113,17 +174,40 @@ func newVm(config config) (*lfvm, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to create converter: %v", err)
 	}
-	return &lfvm{config: config, converter: converter}, nil
+	tracingConverter := converter
+	if config.WithSuperInstructions {
+		tracingConfig := config.ConversionConfig
+		tracingConfig.WithSuperInstructions = false
+		tracingConverter, err = NewConverter(tracingConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create converter: %v", err)
+		}
+	}
+	return &lfvm{config: config, converter: converter, tracingConverter: tracingConverter}, nil
 }
 
 // Defines the newest supported revision for this interpreter implementation
-const newestSupportedRevision = tosca.R13_Cancun
+const newestSupportedRevision = tosca.R14_Prague
 
 func (v *lfvm) Run(params tosca.Parameters) (tosca.Result, error) {
 	if params.Revision > newestSupportedRevision {
 		return tosca.Result{}, &tosca.ErrUnsupportedRevision{Revision: params.Revision}
 	}
 
+	if params.Tracer == nil && v.config.logger != nil {
+		result, err := tosca.RunTraced(v, params, v.config.logger)
+		if err == nil {
+			err = v.config.logger.Err()
+		}
+		return result, err
+	}
+
+	if params.Tracer != nil {
+		config := v.config
+		config.runner = tracingRunner{}
+		return run(config, params, v.tracingConverter.Convert(params.Code, params.CodeHash))
+	}
+
 	converted := v.converter.Convert(
 		params.Code,
 		params.CodeHash,
@@ -132,10 +216,11 @@ func (v *lfvm) Run(params tosca.Parameters) (tosca.Result, error) {
 	return run(v.config, params, converted)
 }
 
-func (e *lfvm) DumpProfile() {
+func (e *lfvm) GetProfile() tosca.Profile {
 	if statsRunner, ok := e.config.runner.(*statisticRunner); ok {
-		fmt.Print(statsRunner.getSummary())
+		return statsRunner.getProfile()
 	}
+	return tosca.Profile{}
 }
 
 func (e *lfvm) ResetProfile() {
@@ -11,10 +11,12 @@
 package lfvm
 
 import (
+	"bytes"
 	"fmt"
 	"testing"
 
 	"github.com/Fantom-foundation/Tosca/go/tosca"
+	"github.com/Fantom-foundation/Tosca/go/tosca/vm"
 )
 
 func TestNewInterpreter_ProducesInstanceWithSanctionedProperties(t *testing.T) {
@@ -71,3 +73,142 @@ func TestLfvm_newVm_returnsErrorWithWrongConfiguration(t *testing.T) {
 		t.Fatalf("expected error, got nil")
 	}
 }
+
+func TestNewInterpreter_AppliesUserDefinedConfiguration(t *testing.T) {
+	tests := map[string]struct {
+		config Config
+		check  func(*testing.T, config)
+	}{
+		"super instructions": {
+			config: Config{WithSuperInstructions: true},
+			check: func(t *testing.T, c config) {
+				if !c.ConversionConfig.WithSuperInstructions {
+					t.Errorf("super instructions are not enabled")
+				}
+			},
+		},
+		"no sha cache": {
+			config: Config{WithoutShaCache: true},
+			check: func(t *testing.T, c config) {
+				if c.WithShaCache {
+					t.Errorf("sha cache is not disabled")
+				}
+			},
+		},
+		"code cache size": {
+			config: Config{CodeCacheSize: -1},
+			check: func(t *testing.T, c config) {
+				if want, got := -1, c.ConversionConfig.CacheSize; want != got {
+					t.Errorf("unexpected code cache size, wanted %d, got %d", want, got)
+				}
+			},
+		},
+		"statistics": {
+			config: Config{WithStatistics: true},
+			check: func(t *testing.T, c config) {
+				if _, ok := c.runner.(*statisticRunner); !ok {
+					t.Errorf("unexpected runner %T", c.runner)
+				}
+			},
+		},
+		"logging": {
+			config: Config{Log: &bytes.Buffer{}},
+			check: func(t *testing.T, c config) {
+				if c.logger == nil {
+					t.Errorf("logging is not enabled")
+				}
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			lfvm, err := NewInterpreter(test.config)
+			if err != nil {
+				t.Fatalf("failed to create LFVM instance: %v", err)
+			}
+			test.check(t, lfvm.config)
+		})
+	}
+}
+
+func TestNewInterpreter_RejectsInvalidConfigurations(t *testing.T) {
+	tests := map[string]Config{
+		"statistics and logging": {WithStatistics: true, Log: &bytes.Buffer{}},
+		"too small code cache":   {CodeCacheSize: maxCachedCodeLength / 2},
+	}
+	for name, config := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewInterpreter(config); err == nil {
+				t.Errorf("expected configuration to be rejected")
+			}
+		})
+	}
+}
+
+func TestLfvm_FactoryAcceptsUserDefinedConfiguration(t *testing.T) {
+	for _, config := range []any{Config{WithStatistics: true}, &Config{WithStatistics: true}} {
+		vm, err := tosca.NewInterpreter("lfvm", config)
+		if err != nil {
+			t.Fatalf("failed to create lfvm instance: %v", err)
+		}
+		lfvm, ok := vm.(*lfvm)
+		if !ok {
+			t.Fatalf("unexpected interpreter implementation, got %T", vm)
+		}
+		if _, ok := lfvm.config.runner.(*statisticRunner); !ok {
+			t.Errorf("configuration was not applied, got runner %T", lfvm.config.runner)
+		}
+	}
+}
+
+func TestLfvm_FactoryRejectsInvalidConfigurations(t *testing.T) {
+	for _, config := range []any{"lfvm", 12, Config{WithStatistics: true, Log: &bytes.Buffer{}}} {
+		if _, err := tosca.NewInterpreter("lfvm", config); err == nil {
+			t.Errorf("expected configuration %v to be rejected", config)
+		}
+	}
+}
+
+func TestLfvm_LogsExecutionInEip3155Format(t *testing.T) {
+	log := &bytes.Buffer{}
+	lfvm, err := NewInterpreter(Config{Log: log})
+	if err != nil {
+		t.Fatalf("failed to create lfvm instance: %v", err)
+	}
+
+	result, err := lfvm.Run(tosca.Parameters{
+		Code: []byte{byte(vm.PUSH1), 1, byte(vm.STOP)},
+		Gas:  100,
+	})
+	if err != nil || !result.Success {
+		t.Fatalf("unexpected result: %v, %v", result, err)
+	}
+
+	want := `{"pc":0,"op":96,"gas":"0x64","gasCost":"0x3","memSize":0,"stack":[],"depth":1,"refund":0,"opName":"PUSH1"}` + "\n" +
+		`{"pc":2,"op":0,"gas":"0x61","gasCost":"0x0","memSize":0,"stack":["0x1"],"depth":1,"refund":0,"opName":"STOP"}` + "\n" +
+		`{"output":"","gasUsed":"0x3","pass":true}` + "\n"
+	if got := log.String(); want != got {
+		t.Errorf("unexpected log\nwanted %s\n   got %s", want, got)
+	}
+}
+
+func TestLfvm_ReportsErrorsOfLogWriter(t *testing.T) {
+	lfvm, err := NewInterpreter(Config{Log: failingWriter{}})
+	if err != nil {
+		t.Fatalf("failed to create lfvm instance: %v", err)
+	}
+	_, err = lfvm.Run(tosca.Parameters{
+		Code: []byte{byte(vm.PUSH1), 1, byte(vm.STOP)},
+		Gas:  100,
+	})
+	if err == nil {
+		t.Errorf("expected error of log writer to be reported")
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, fmt.Errorf("injected error")
+}
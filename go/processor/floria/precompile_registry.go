@@ -0,0 +1,189 @@
+// Copyright (c) 2024 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package floria
+
+import (
+	"bytes"
+	"math"
+	"sort"
+
+	"github.com/Fantom-foundation/Tosca/go/tosca"
+	"github.com/ethereum/go-ethereum/common"
+	geth "github.com/ethereum/go-ethereum/core/vm"
+)
+
+const (
+	// ErrExecutionReverted may be returned by precompiled contracts to fail
+	// a call without consuming the remaining gas.
+	ErrExecutionReverted = tosca.ConstError("execution reverted")
+
+	// ErrOutOfGas is returned by precompiled contracts running out of gas.
+	ErrOutOfGas = tosca.ConstError("out of gas")
+)
+
+// PrecompiledContract is a contract implemented natively in Go instead of
+// EVM byte code. Implementations must be stateless and safe for concurrent
+// use, since a single instance is shared by all calls of all transactions.
+type PrecompiledContract interface {
+	// RequiredGas returns the gas charged before the contract is run on the
+	// given input. If the call does not provide enough gas, the contract is
+	// not run and all gas is consumed.
+	RequiredGas(input tosca.Data) tosca.Gas
+
+	// Run executes the contract on the given input. The context provides
+	// access to the parameters of the call and the state of the ongoing
+	// transaction. Contracts not depending on the state may ignore it.
+	// Any returned error marks the call as failed. Unless the error is
+	// ErrExecutionReverted, all remaining gas of the call is consumed.
+	Run(context *PrecompileContext, input tosca.Data) (tosca.Data, error)
+}
+
+// PrecompileContext is the environment of a single call of a precompiled
+// contract.
+type PrecompileContext struct {
+	tosca.TransactionContext                // < the state of the ongoing transaction
+	Revision                 tosca.Revision // < the revision of the current block
+	Sender                   tosca.Address  // < the caller of the contract
+	Recipient                tosca.Address  // < the address of the contract
+	Value                    tosca.Value    // < the value transferred by the call
+	gasLeft                  tosca.Gas      // < the gas left after the RequiredGas was charged
+}
+
+// GasLeft returns the gas still available to the running contract.
+func (c *PrecompileContext) GasLeft() tosca.Gas {
+	return c.gasLeft
+}
+
+// UseGas charges the given amount of gas for costs depending on the state
+// or the progress of the execution. If not enough gas is left, ErrOutOfGas
+// is returned and the contract should abort its execution with this error.
+func (c *PrecompileContext) UseGas(amount tosca.Gas) error {
+	if amount < 0 || c.gasLeft < amount {
+		c.gasLeft = 0
+		return ErrOutOfGas
+	}
+	c.gasLeft -= amount
+	return nil
+}
+
+// PrecompileRegistry is a set of precompiled contracts keyed by revision. A
+// contract registered at an address becomes available with the revision it
+// was registered for and stays available in all later revisions, until a
+// different contract is registered at the same address for a later one.
+//
+// A registry is not thread-safe for modifications. Once all contracts are
+// registered, it may be shared by any number of processors.
+type PrecompileRegistry struct {
+	contracts map[tosca.Address][]precompileRegistration
+}
+
+// precompileRegistration is a contract registered at an address, sorted in
+// the registry by the revision it is active from.
+type precompileRegistration struct {
+	since    tosca.Revision
+	contract PrecompiledContract // < nil if the address got deactivated
+}
+
+// NewPrecompileRegistry creates an empty registry.
+func NewPrecompileRegistry() *PrecompileRegistry {
+	return &PrecompileRegistry{contracts: map[tosca.Address][]precompileRegistration{}}
+}
+
+// NewEthereumPrecompileRegistry creates a registry containing the precompiled
+// contracts of Ethereum, as implemented by geth. Chains may register
+// additional contracts or replace existing ones in the resulting registry.
+func NewEthereumPrecompileRegistry() *PrecompileRegistry {
+	registry := NewPrecompileRegistry()
+	// Istanbul is the oldest revision supported by Sonic. Berlin updated the
+	// pricing of existing contracts and Cancun added the point evaluation.
+	for _, set := range []struct {
+		revision  tosca.Revision
+		contracts map[common.Address]geth.PrecompiledContract
+	}{
+		{tosca.R07_Istanbul, geth.PrecompiledContractsIstanbul},
+		{tosca.R09_Berlin, geth.PrecompiledContractsBerlin},
+		{tosca.R13_Cancun, geth.PrecompiledContractsCancun},
+	} {
+		for address, contract := range set.contracts {
+			registry.Register(set.revision, tosca.Address(address), NewGethPrecompiledContract(contract))
+		}
+	}
+	return registry
+}
+
+// Register makes the given contract available at the given address from the
+// given revision on. Registering a nil contract deactivates the address from
+// the given revision on. A previous registration for the same address and
+// revision is replaced.
+func (r *PrecompileRegistry) Register(since tosca.Revision, address tosca.Address, contract PrecompiledContract) {
+	registrations := r.contracts[address]
+	for i := range registrations {
+		if registrations[i].since == since {
+			registrations[i].contract = contract
+			return
+		}
+	}
+	registrations = append(registrations, precompileRegistration{since: since, contract: contract})
+	sort.Slice(registrations, func(i, j int) bool {
+		return registrations[i].since < registrations[j].since
+	})
+	r.contracts[address] = registrations
+}
+
+// Get returns the contract available at the given address in the given
+// revision, if there is any.
+func (r *PrecompileRegistry) Get(revision tosca.Revision, address tosca.Address) (PrecompiledContract, bool) {
+	var res PrecompiledContract
+	for _, registration := range r.contracts[address] {
+		if registration.since > revision {
+			break
+		}
+		res = registration.contract
+	}
+	return res, res != nil
+}
+
+// Addresses returns the addresses of all contracts available in the given
+// revision, in ascending order.
+func (r *PrecompileRegistry) Addresses(revision tosca.Revision) []tosca.Address {
+	res := make([]tosca.Address, 0, len(r.contracts))
+	for address := range r.contracts {
+		if _, found := r.Get(revision, address); found {
+			res = append(res, address)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return bytes.Compare(res[i][:], res[j][:]) < 0
+	})
+	return res
+}
+
+// NewGethPrecompiledContract adapts a precompiled contract implemented for
+// geth to the PrecompiledContract interface of this package.
+func NewGethPrecompiledContract(contract geth.PrecompiledContract) PrecompiledContract {
+	return gethPrecompiledContract{contract}
+}
+
+type gethPrecompiledContract struct {
+	contract geth.PrecompiledContract
+}
+
+func (c gethPrecompiledContract) RequiredGas(input tosca.Data) tosca.Gas {
+	gas := c.contract.RequiredGas(input)
+	if gas > math.MaxInt64 {
+		return math.MaxInt64
+	}
+	return tosca.Gas(gas)
+}
+
+func (c gethPrecompiledContract) Run(_ *PrecompileContext, input tosca.Data) (tosca.Data, error) {
+	return c.contract.Run(input)
+}
@@ -11,54 +11,71 @@
 package floria
 
 import (
+	"errors"
+
 	"github.com/Fantom-foundation/Tosca/go/tosca"
-	"github.com/ethereum/go-ethereum/common"
-	geth "github.com/ethereum/go-ethereum/core/vm"
 )
 
-func handlePrecompiledContract(revision tosca.Revision, input tosca.Data, address tosca.Address, gas tosca.Gas) (tosca.CallResult, bool) {
-	contract, ok := getPrecompiledContract(address, revision)
+// ethereumPrecompiles is the registry of precompiled contracts used if no
+// registry is configured.
+var ethereumPrecompiles = NewEthereumPrecompileRegistry()
+
+// handlePrecompiledContract runs the contract registered at the given address
+// in the given registry on the input of the given call. The second result is
+// false if no contract is registered at the address.
+func handlePrecompiledContract(
+	context tosca.TransactionContext,
+	precompiles *PrecompileRegistry,
+	revision tosca.Revision,
+	address tosca.Address,
+	parameters tosca.CallParameters,
+) (tosca.CallResult, bool) {
+	contract, ok := precompiles.Get(revision, address)
 	if !ok {
 		return tosca.CallResult{}, false
 	}
-	gasCost := contract.RequiredGas(input)
-	if gas < tosca.Gas(gasCost) {
-		return tosca.CallResult{}, true
+	gasCost := contract.RequiredGas(parameters.Input)
+	if parameters.Gas < gasCost {
+		return tosca.CallResult{FailureCause: tosca.FailureOutOfGas}, true
 	}
-	gas -= tosca.Gas(gasCost)
-	output, err := contract.Run(input)
-
-	return tosca.CallResult{
-		Success: err == nil, // precompiled contracts only return errors on invalid input
-		Output:  output,
-		GasLeft: gas,
-	}, true
-}
 
-func getPrecompiledContract(address tosca.Address, revision tosca.Revision) (geth.PrecompiledContract, bool) {
-	precompiles := getPrecompiledContracts(revision)
-	contract, ok := precompiles[common.Address(address)]
-	return contract, ok
-}
-
-func getPrecompiledContracts(revision tosca.Revision) map[common.Address]geth.PrecompiledContract {
-	var precompiles map[common.Address]geth.PrecompiledContract
-	switch revision {
-	case tosca.R13_Cancun:
-		precompiles = geth.PrecompiledContractsCancun
-	case tosca.R12_Shanghai, tosca.R11_Paris, tosca.R10_London, tosca.R09_Berlin:
-		precompiles = geth.PrecompiledContractsBerlin
-	default: // Istanbul is the oldest supported revision supported by Sonic
-		precompiles = geth.PrecompiledContractsIstanbul
+	precompileContext := &PrecompileContext{
+		TransactionContext: context,
+		Revision:           revision,
+		Sender:             parameters.Sender,
+		Recipient:          address,
+		Value:              parameters.Value,
+		gasLeft:            parameters.Gas - gasCost,
+	}
+	output, err := contract.Run(precompileContext, parameters.Input)
+	switch {
+	case err == nil:
+		return tosca.CallResult{
+			Success: true,
+			Output:  output,
+			GasLeft: precompileContext.gasLeft,
+		}, true
+	case errors.Is(err, ErrExecutionReverted):
+		reason, _ := tosca.DecodeRevertReason(output)
+		return tosca.CallResult{
+			Output:       output,
+			GasLeft:      precompileContext.gasLeft,
+			FailureCause: tosca.FailureRevert,
+			RevertReason: reason,
+		}, true
+	case errors.Is(err, ErrOutOfGas):
+		return tosca.CallResult{FailureCause: tosca.FailureOutOfGas}, true
+	default:
+		// precompiled contracts only return errors on invalid input
+		return tosca.CallResult{FailureCause: tosca.FailurePrecompile}, true
 	}
-	return precompiles
 }
 
-func getPrecompiledAddresses(revision tosca.Revision) []tosca.Address {
-	precompiles := getPrecompiledContracts(revision)
-	addresses := make([]tosca.Address, 0, len(precompiles))
-	for addr := range precompiles {
-		addresses = append(addresses, tosca.Address(addr))
+// getPrecompiles returns the registry of precompiled contracts enabled by
+// the configuration of this run context.
+func (r runContext) getPrecompiles() *PrecompileRegistry {
+	if r.config.Precompiles != nil {
+		return r.config.Precompiles
 	}
-	return addresses
+	return ethereumPrecompiles
 }
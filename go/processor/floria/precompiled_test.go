@@ -11,11 +11,14 @@
 package floria
 
 import (
+	"errors"
+	"reflect"
 	"strings"
 	"testing"
 
 	test_utils "github.com/Fantom-foundation/Tosca/go/processor"
 	"github.com/Fantom-foundation/Tosca/go/tosca"
+	"go.uber.org/mock/gomock"
 )
 
 func TestPrecompiled_RightNumberOfContractsDependingOnRevision(t *testing.T) {
@@ -35,7 +38,7 @@ func TestPrecompiled_RightNumberOfContractsDependingOnRevision(t *testing.T) {
 		count := 0
 		for i := byte(0x01); i < byte(0x42); i++ {
 			address := test_utils.NewAddress(i)
-			_, isPrecompiled := getPrecompiledContract(address, test.revision)
+			_, isPrecompiled := ethereumPrecompiles.Get(test.revision, address)
 			if isPrecompiled {
 				count++
 			}
@@ -43,7 +46,7 @@ func TestPrecompiled_RightNumberOfContractsDependingOnRevision(t *testing.T) {
 		if count != test.numberOfContracts {
 			t.Errorf("unexpected number of precompiled contracts for revision %v, want %v, got %v", test.revision, test.numberOfContracts, count)
 		}
-		if len(getPrecompiledAddresses(test.revision)) != test.numberOfContracts {
+		if len(ethereumPrecompiles.Addresses(test.revision)) != test.numberOfContracts {
 			t.Errorf("unexpected number of precompiled contracts for revision %v, want %v, got %v", test.revision, test.numberOfContracts, count)
 		}
 	}
@@ -73,7 +76,8 @@ func TestPrecompiled_AddressesAreHandledCorrectly(t *testing.T) {
 				input = test_utils.ValidPointEvaluationInput
 			}
 
-			result, isPrecompiled := handlePrecompiledContract(test.revision, input, test.address, test.gas)
+			params := tosca.CallParameters{Input: input, Gas: test.gas}
+			result, isPrecompiled := handlePrecompiledContract(nil, ethereumPrecompiles, test.revision, test.address, params)
 			if isPrecompiled != test.isPrecompiled {
 				t.Errorf("unexpected precompiled, want %v, got %v", test.isPrecompiled, isPrecompiled)
 			}
@@ -83,3 +87,175 @@ func TestPrecompiled_AddressesAreHandledCorrectly(t *testing.T) {
 		})
 	}
 }
+
+func TestPrecompiled_FailuresAreClassified(t *testing.T) {
+	tests := map[string]struct {
+		input tosca.Data
+		gas   tosca.Gas
+		want  tosca.FailureCause
+	}{
+		"success":       {test_utils.ValidPointEvaluationInput, 55000, tosca.FailureNone},
+		"out of gas":    {test_utils.ValidPointEvaluationInput, 1, tosca.FailureOutOfGas},
+		"invalid input": {tosca.Data{1, 2, 3}, 55000, tosca.FailurePrecompile},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			params := tosca.CallParameters{Input: test.input, Gas: test.gas}
+			result, _ := handlePrecompiledContract(nil, ethereumPrecompiles, tosca.R13_Cancun, test_utils.NewAddress(0x0a), params)
+			if want, got := test.want, result.FailureCause; want != got {
+				t.Errorf("unexpected failure cause, want %v, got %v", want, got)
+			}
+		})
+	}
+}
+
+func TestPrecompiled_FailuresConsumeAllGasUnlessReverted(t *testing.T) {
+	tests := map[string]struct {
+		err     error
+		gasLeft tosca.Gas
+		cause   tosca.FailureCause
+	}{
+		"success":  {nil, 900, tosca.FailureNone},
+		"reverted": {ErrExecutionReverted, 900, tosca.FailureRevert},
+		"outOfGas": {ErrOutOfGas, 0, tosca.FailureOutOfGas},
+		"failed":   {errors.New("injected error"), 0, tosca.FailurePrecompile},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			registry := NewPrecompileRegistry()
+			registry.Register(tosca.R07_Istanbul, tosca.Address{1}, testPrecompiledContract{
+				gas: 100,
+				run: func(*PrecompileContext, tosca.Data) (tosca.Data, error) {
+					return tosca.Data{1}, test.err
+				},
+			})
+			params := tosca.CallParameters{Gas: 1000}
+			result, _ := handlePrecompiledContract(nil, registry, tosca.R13_Cancun, tosca.Address{1}, params)
+			if want, got := test.err == nil, result.Success; want != got {
+				t.Errorf("unexpected success, want %t, got %t", want, got)
+			}
+			if want, got := test.gasLeft, result.GasLeft; want != got {
+				t.Errorf("unexpected gas left, want %d, got %d", want, got)
+			}
+			if want, got := test.cause, result.FailureCause; want != got {
+				t.Errorf("unexpected failure cause, want %v, got %v", want, got)
+			}
+		})
+	}
+}
+
+func TestPrecompiled_ContractsCanAccessContextAndChargeGas(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	context := tosca.NewMockTransactionContext(ctrl)
+	context.EXPECT().GetBalance(tosca.Address{2}).Return(tosca.NewValue(42))
+
+	registry := NewPrecompileRegistry()
+	registry.Register(tosca.R07_Istanbul, tosca.Address{1}, testPrecompiledContract{
+		gas: 100,
+		run: func(context *PrecompileContext, input tosca.Data) (tosca.Data, error) {
+			if want, got := (tosca.Address{2}), context.Sender; want != got {
+				t.Errorf("unexpected sender, want %v, got %v", want, got)
+			}
+			if want, got := tosca.Gas(900), context.GasLeft(); want != got {
+				t.Errorf("unexpected gas left, want %d, got %d", want, got)
+			}
+			if err := context.UseGas(400); err != nil {
+				t.Errorf("failed to charge gas: %v", err)
+			}
+			balance := context.GetBalance(context.Sender)
+			return balance[:], nil
+		},
+	})
+
+	params := tosca.CallParameters{Sender: tosca.Address{2}, Gas: 1000}
+	result, _ := handlePrecompiledContract(context, registry, tosca.R13_Cancun, tosca.Address{1}, params)
+	if !result.Success {
+		t.Fatalf("call was not successful")
+	}
+	if want, got := tosca.Gas(500), result.GasLeft; want != got {
+		t.Errorf("unexpected gas left, want %d, got %d", want, got)
+	}
+	if want, got := tosca.NewValue(42), tosca.Value(result.Output); want != got {
+		t.Errorf("unexpected output, want %v, got %v", want, got)
+	}
+}
+
+func TestPrecompileContext_UseGasFailsIfNotEnoughGasIsLeft(t *testing.T) {
+	context := &PrecompileContext{gasLeft: 10}
+	if err := context.UseGas(11); !errors.Is(err, ErrOutOfGas) {
+		t.Errorf("unexpected error, want %v, got %v", ErrOutOfGas, err)
+	}
+	if want, got := tosca.Gas(0), context.GasLeft(); want != got {
+		t.Errorf("unexpected gas left, want %d, got %d", want, got)
+	}
+}
+
+func TestPrecompileRegistry_ContractsAreActiveFromTheirRevision(t *testing.T) {
+	first := testPrecompiledContract{gas: 1}
+	second := testPrecompiledContract{gas: 2}
+	registry := NewPrecompileRegistry()
+	registry.Register(tosca.R13_Cancun, tosca.Address{1}, second)
+	registry.Register(tosca.R09_Berlin, tosca.Address{1}, first)
+	registry.Register(tosca.R10_London, tosca.Address{2}, first)
+	registry.Register(tosca.R12_Shanghai, tosca.Address{2}, nil)
+
+	tests := map[tosca.Revision][]tosca.Gas{ // < required gas of contract 1 and 2, 0 if missing
+		tosca.R07_Istanbul: {0, 0},
+		tosca.R09_Berlin:   {1, 0},
+		tosca.R10_London:   {1, 1},
+		tosca.R12_Shanghai: {1, 0},
+		tosca.R13_Cancun:   {2, 0},
+	}
+	for revision, want := range tests {
+		addresses := []tosca.Address{}
+		for i, address := range []tosca.Address{{1}, {2}} {
+			got := tosca.Gas(0)
+			if contract, found := registry.Get(revision, address); found {
+				got = contract.RequiredGas(nil)
+				addresses = append(addresses, address)
+			}
+			if want[i] != got {
+				t.Errorf("unexpected contract at %v in %v, want gas %d, got %d", address, revision, want[i], got)
+			}
+		}
+		if want, got := addresses, registry.Addresses(revision); !reflect.DeepEqual(want, got) {
+			t.Errorf("unexpected addresses in %v, want %v, got %v", revision, want, got)
+		}
+	}
+}
+
+func TestPrecompileRegistry_RegisteringTwiceReplacesContract(t *testing.T) {
+	registry := NewPrecompileRegistry()
+	registry.Register(tosca.R09_Berlin, tosca.Address{1}, testPrecompiledContract{gas: 1})
+	registry.Register(tosca.R09_Berlin, tosca.Address{1}, testPrecompiledContract{gas: 2})
+	contract, found := registry.Get(tosca.R13_Cancun, tosca.Address{1})
+	if !found || contract.RequiredGas(nil) != 2 {
+		t.Errorf("contract was not replaced")
+	}
+}
+
+func TestPrecompiled_ConfiguredPrecompilesOverrideDefaults(t *testing.T) {
+	defaults := runContext{blockParameters: tosca.BlockParameters{Revision: tosca.R13_Cancun}}
+	if defaults.getPrecompiles() != ethereumPrecompiles {
+		t.Errorf("default precompiled contracts are not used")
+	}
+
+	configured := defaults
+	configured.config.Precompiles = NewPrecompileRegistry()
+	if got := configured.getPrecompiles(); got != configured.config.Precompiles {
+		t.Errorf("configured precompiled contracts are not used")
+	}
+}
+
+type testPrecompiledContract struct {
+	gas tosca.Gas
+	run func(*PrecompileContext, tosca.Data) (tosca.Data, error)
+}
+
+func (c testPrecompiledContract) RequiredGas(tosca.Data) tosca.Gas {
+	return c.gas
+}
+
+func (c testPrecompiledContract) Run(context *PrecompileContext, input tosca.Data) (tosca.Data, error) {
+	return c.run(context, input)
+}
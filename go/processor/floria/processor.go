@@ -13,6 +13,7 @@ package floria
 import (
 	"fmt"
 
+	"github.com/Fantom-foundation/Tosca/go/processor/system_contract"
 	"github.com/Fantom-foundation/Tosca/go/tosca"
 )
 
@@ -23,25 +24,102 @@ const (
 	TxDataZeroGasEIP2028      = 4
 	TxAccessListAddressGas    = 2400
 	TxAccessListStorageKeyGas = 1900
+	TxCostFloorPerToken       = 10 // Gas charged per token of data by the floor cost (EIP-7623).
+	TxTokenPerNonZeroByte     = 4  // Tokens counted for a non-zero byte of data (EIP-7623).
 
 	createGasCostPerByte = 200
 	maxCodeSize          = 24576
+	maxInitCodeSize      = 2 * maxCodeSize // Maximum size of init code since Shanghai (EIP-3860).
+	initCodeWordGas      = 2               // Gas charged per word of init code since Shanghai (EIP-3860).
+
+	BlobTxBlobGasPerBlob = 1 << 17 // Gas consumption of a single data blob (EIP-4844).
+	MaxBlobGasPerBlock   = 6 * BlobTxBlobGasPerBlob
+	BlobTxHashVersion    = 0x01 // Version byte of the commitment hashes of blobs (EIP-4844).
 
 	MaxRecursiveDepth = 1024 // Maximum depth of call/create stack.
 )
 
 func init() {
-	tosca.RegisterProcessorFactory("floria", newProcessor)
+	tosca.MustRegisterProcessorFactory("floria", newProcessorFromConfig, tosca.ProcessorInfo{
+		NewestSupportedRevision: tosca.R14_Prague,
+		Tracing:                 true,
+	})
+}
+
+// Config provides a set of user-definable options for the Floria processor.
+// The zero value is the configuration used by the Sonic network.
+type Config struct {
+	// ChainId, if not zero, is the ID of the only chain the processor
+	// accepts blocks of. Blocks of other chains are rejected with an error.
+	ChainId tosca.Word
+	// Precompiles, if not nil, defines the set of precompiled contracts
+	// available in each revision. By default, the precompiled contracts
+	// of the corresponding Ethereum revision are used.
+	Precompiles *PrecompileRegistry
+	// FeePolicy defines how gas not used by a transaction is billed.
+	FeePolicy FeePolicy
+	// SystemContracts, if not nil, lists the system contracts available to
+	// transactions. By default, Sonic's EvmWriter contract is available.
+	SystemContracts []*system_contract.Contract
+	// WithoutStateContracts disables all system contracts, including the
+	// Sonic specific EvmWriter contract enabled by default.
+	WithoutStateContracts bool
+	// SystemTransactionGasAllowance, if not zero, replaces the gas limit of
+	// system transactions. By default, their gas limit is used.
+	SystemTransactionGasAllowance tosca.Gas
 }
 
-func newProcessor(interpreter tosca.Interpreter) tosca.Processor {
+// FeePolicy defines the billing of gas for transactions.
+type FeePolicy int
+
+const (
+	// SonicFeePolicy charges 10% of the gas not used by a transaction to
+	// discourage the over-allocation of gas. System transactions are exempt
+	// from this charge.
+	SonicFeePolicy FeePolicy = iota
+	// EthereumFeePolicy charges only for the gas used by a transaction.
+	EthereumFeePolicy
+)
+
+// NewProcessor creates a new Floria processor instance running contract code
+// on the given interpreter using the given configuration. An error is
+// returned if the configuration is invalid.
+func NewProcessor(interpreter tosca.Interpreter, config Config) (tosca.Processor, error) {
+	if interpreter == nil {
+		return nil, fmt.Errorf("invalid configuration: no interpreter provided")
+	}
+	if config.FeePolicy != SonicFeePolicy && config.FeePolicy != EthereumFeePolicy {
+		return nil, fmt.Errorf("invalid configuration: unknown fee policy %d", config.FeePolicy)
+	}
+	if config.SystemTransactionGasAllowance < 0 {
+		return nil, fmt.Errorf("invalid configuration: negative system transaction gas allowance %d", config.SystemTransactionGasAllowance)
+	}
 	return &processor{
 		interpreter: interpreter,
+		config:      config,
+	}, nil
+}
+
+// newProcessorFromConfig is the factory registered in the processor registry.
+func newProcessorFromConfig(interpreter tosca.Interpreter, config any) (tosca.Processor, error) {
+	switch config := config.(type) {
+	case nil:
+		return NewProcessor(interpreter, Config{})
+	case Config:
+		return NewProcessor(interpreter, config)
+	case *Config:
+		if config == nil {
+			return NewProcessor(interpreter, Config{})
+		}
+		return NewProcessor(interpreter, *config)
+	default:
+		return nil, fmt.Errorf("unsupported configuration type for floria: %T", config)
 	}
 }
 
 type processor struct {
 	interpreter tosca.Interpreter
+	config      Config
 }
 
 func (p *processor) Run(
@@ -49,43 +127,131 @@ func (p *processor) Run(
 	transaction tosca.Transaction,
 	context tosca.TransactionContext,
 ) (tosca.Receipt, error) {
+	return p.run(blockParameters, transaction, context, false)
+}
+
+// Simulate runs the given transaction without checking the nonce, the sender,
+// and the gas fee caps of the transaction, and without buying gas.
+func (p *processor) Simulate(
+	blockParameters tosca.BlockParameters,
+	transaction tosca.Transaction,
+	context tosca.TransactionContext,
+) (tosca.Receipt, error) {
+	return p.run(blockParameters, transaction, context, true)
+}
+
+func (p *processor) run(
+	blockParameters tosca.BlockParameters,
+	transaction tosca.Transaction,
+	context tosca.TransactionContext,
+	simulate bool,
+) (tosca.Receipt, error) {
+	if p.config.ChainId != (tosca.Word{}) && p.config.ChainId != blockParameters.ChainID {
+		return tosca.Receipt{}, fmt.Errorf("invalid chain ID: expected %x, got %x", p.config.ChainId, blockParameters.ChainID)
+	}
+
+	tracer := tosca.GetTracer(context)
+	context = tosca.NewTracedTransactionContext(context, tracer)
+
+	// System transactions are issued by the chain and not paid for by the
+	// sender. Like simulated transactions, they are exempt from fee, nonce,
+	// and sender checks.
+	system := transaction.Kind == tosca.SystemTransaction
+	if system && p.config.SystemTransactionGasAllowance != 0 {
+		transaction.GasLimit = p.config.SystemTransactionGasAllowance
+	}
+	paid := !simulate && !system
+
+	gasPrice := transaction.GetEffectiveGasPrice(blockParameters.BaseFee)
+	if paid {
+		var err error
+		gasPrice, err = checkFees(blockParameters, transaction)
+		if err != nil {
+			return tosca.Receipt{}, err
+		}
+	}
+
+	blobGas, err := checkBlobs(blockParameters, transaction)
+	if err != nil {
+		return tosca.Receipt{}, err
+	}
+
+	if err := checkAuthorizations(blockParameters, transaction); err != nil {
+		return tosca.Receipt{}, err
+	}
+
 	errorReceipt := tosca.Receipt{
-		Success: false,
-		GasUsed: transaction.GasLimit,
+		Success:           false,
+		GasUsed:           transaction.GasLimit,
+		BlobGasUsed:       blobGas,
+		EffectiveGasPrice: gasPrice,
 	}
 	gas := transaction.GasLimit
 
-	if err := buyGas(transaction, context); err != nil {
-		return tosca.Receipt{}, nil
+	if paid {
+		if err := checkNonce(transaction, context); err != nil {
+			return tosca.Receipt{}, err
+		}
+		if err := checkSenderIsEOA(transaction, context); err != nil {
+			return tosca.Receipt{}, err
+		}
 	}
 
-	intrinsicGas := setupGasBilling(transaction)
+	intrinsicGas := setupGasBilling(transaction, blockParameters.Revision)
 	if gas < intrinsicGas {
-		return errorReceipt, nil
+		return tosca.Receipt{}, fmt.Errorf("%w: have %d, want %d", tosca.ErrIntrinsicGas, gas, intrinsicGas)
+	}
+	// Since Shanghai (EIP-3860) the size of init code is limited.
+	if transaction.Recipient == nil && blockParameters.Revision >= tosca.R12_Shanghai && len(transaction.Input) > maxInitCodeSize {
+		return tosca.Receipt{}, fmt.Errorf("%w: code size %v limit %v", tosca.ErrMaxInitCodeSizeExceeded, len(transaction.Input), maxInitCodeSize)
+	}
+	// Since Prague (EIP-7623), transactions must pay at least a floor cost
+	// depending on the size of their data.
+	floorGas := tosca.Gas(0)
+	if blockParameters.Revision >= tosca.R14_Prague {
+		floorGas = floorDataGas(transaction)
+		if transaction.GasLimit < floorGas {
+			return tosca.Receipt{}, fmt.Errorf("%w: have %d, want %d", tosca.ErrFloorDataGas, transaction.GasLimit, floorGas)
+		}
 	}
 	gas -= intrinsicGas
 
-	if err := handleNonce(transaction, context); err != nil {
-		return errorReceipt, nil
+	// All checks before this point must not modify the world state.
+	if paid {
+		if err := buyGas(transaction, context, gasPrice, blobGas, blockParameters.BlobBaseFee); err != nil {
+			return tosca.Receipt{}, err
+		}
+	}
+	if transaction.Recipient != nil {
+		context.SetNonce(transaction.Sender, context.GetNonce(transaction.Sender)+1)
 	}
 
 	transactionParameters := tosca.TransactionParameters{
 		Origin:     transaction.Sender,
-		GasPrice:   transaction.GasPrice,
-		BlobHashes: []tosca.Hash{}, // ?
+		GasPrice:   gasPrice,
+		BlobHashes: transaction.BlobHashes,
 	}
 
 	runContext := runContext{
-		context,
-		p.interpreter,
-		blockParameters,
-		transactionParameters,
-		0,
-		false,
+		TransactionContext:    context,
+		interpreter:           p.interpreter,
+		blockParameters:       blockParameters,
+		transactionParameters: transactionParameters,
+		tracer:                tracer,
+		config:                p.config,
 	}
 
 	if blockParameters.Revision >= tosca.R09_Berlin {
-		setUpAccessList(transaction, &runContext, blockParameters.Revision)
+		setUpAccessList(transaction, &runContext, runContext.getPrecompiles().Addresses(blockParameters.Revision))
+	}
+
+	// Delegations are installed after the nonce of the sender got
+	// incremented, such that senders may authorize their own delegation.
+	authorizationRefund := applyAuthorizations(context, blockParameters.ChainID, transaction.AuthorizationList)
+	if blockParameters.Revision >= tosca.R14_Prague && transaction.Recipient != nil {
+		if target, delegated := tosca.GetDelegationTarget(context.GetCode(*transaction.Recipient)); delegated {
+			context.AccessAccount(target)
+		}
 	}
 
 	callParameters := callParameters(transaction, gas)
@@ -99,7 +265,9 @@ func (p *processor) Run(
 	// left or due to other failures, the transaction needs to handle it differently.
 	// TODO: add extensive testing for output handling in reverted/failed cases
 	// Work in progress, still prone to changes
-	if !result.Success && result.GasLeft == 0 {
+	if !result.Success && result.GasLeft == 0 && authorizationRefund == 0 {
+		errorReceipt.FailureCause = result.FailureCause
+		errorReceipt.RevertReason = result.RevertReason
 		return errorReceipt, nil
 	}
 	// End of work in progress
@@ -109,21 +277,76 @@ func (p *processor) Run(
 		createdAddress = &result.CreatedAddress
 	}
 
-	gasLeft := calculateGasLeft(transaction, result, blockParameters.Revision)
-	refundGas(transaction, context, gasLeft)
+	gasLeft := calculateGasLeft(transaction, result, authorizationRefund, blockParameters.Revision, p.config.FeePolicy)
+	if gasUsed := transaction.GasLimit - gasLeft; gasUsed < floorGas {
+		gasLeft = transaction.GasLimit - floorGas
+	}
+	if paid {
+		refundGas(transaction, context, gasPrice, gasLeft)
+	}
 
 	logs := context.GetLogs()
 
 	return tosca.Receipt{
-		Success:         result.Success,
-		GasUsed:         transaction.GasLimit - gasLeft,
-		ContractAddress: createdAddress,
-		Output:          result.Output,
-		Logs:            logs,
+		Success:           result.Success,
+		GasUsed:           transaction.GasLimit - gasLeft,
+		BlobGasUsed:       blobGas,
+		EffectiveGasPrice: gasPrice,
+		ContractAddress:   createdAddress,
+		Output:            result.Output,
+		Logs:              logs,
+		FailureCause:      result.FailureCause,
+		RevertReason:      result.RevertReason,
 	}, nil
 }
 
-func setUpAccessList(transaction tosca.Transaction, context tosca.TransactionContext, revision tosca.Revision) {
+// checkFees validates the fees offered by the given transaction against the
+// base fee of the block and returns the effective price of a unit of gas.
+func checkFees(blockParameters tosca.BlockParameters, transaction tosca.Transaction) (tosca.Value, error) {
+	if blockParameters.Revision < tosca.R10_London {
+		if transaction.IsDynamicFee() {
+			return tosca.Value{}, fmt.Errorf("%w: dynamic fee transaction before London", tosca.ErrTxTypeNotSupported)
+		}
+		return transaction.GasPrice, nil
+	}
+	feeCap := transaction.GetGasFeeCap()
+	if tipCap := transaction.GetGasTipCap(); tipCap.Cmp(feeCap) > 0 {
+		return tosca.Value{}, fmt.Errorf("%w: %v > %v", tosca.ErrTipAboveFeeCap, tipCap, feeCap)
+	}
+	if feeCap.Cmp(blockParameters.BaseFee) < 0 {
+		return tosca.Value{}, fmt.Errorf("%w: %v < %v", tosca.ErrFeeCapTooLow, feeCap, blockParameters.BaseFee)
+	}
+	return transaction.GetEffectiveGasPrice(blockParameters.BaseFee), nil
+}
+
+// checkBlobs validates the blobs of the given transaction according to the
+// rules introduced by EIP-4844 and returns the blob gas to be paid.
+func checkBlobs(blockParameters tosca.BlockParameters, transaction tosca.Transaction) (tosca.Gas, error) {
+	if len(transaction.BlobHashes) == 0 {
+		return 0, nil
+	}
+	if blockParameters.Revision < tosca.R13_Cancun {
+		return 0, fmt.Errorf("%w: blob transaction before Cancun", tosca.ErrTxTypeNotSupported)
+	}
+	if transaction.Recipient == nil {
+		return 0, tosca.ErrBlobTxCreate
+	}
+	blobGas := tosca.Gas(len(transaction.BlobHashes)) * BlobTxBlobGasPerBlob
+	if blobGas > MaxBlobGasPerBlock {
+		return 0, fmt.Errorf("%w: %d > %d", tosca.ErrTooManyBlobs, len(transaction.BlobHashes), MaxBlobGasPerBlock/BlobTxBlobGasPerBlob)
+	}
+	for i, hash := range transaction.BlobHashes {
+		if hash[0] != BlobTxHashVersion {
+			return 0, fmt.Errorf("%w: hash %d has version %d", tosca.ErrInvalidBlobHashVersion, i, hash[0])
+		}
+	}
+	if transaction.BlobGasFeeCap.Cmp(blockParameters.BlobBaseFee) < 0 {
+		return 0, fmt.Errorf("%w: %v < %v", tosca.ErrBlobFeeCapTooLow, transaction.BlobGasFeeCap, blockParameters.BlobBaseFee)
+	}
+	return blobGas, nil
+}
+
+func setUpAccessList(transaction tosca.Transaction, context tosca.TransactionContext, precompiles []tosca.Address) {
 	if transaction.AccessList == nil {
 		return
 	}
@@ -132,7 +355,6 @@ func setUpAccessList(transaction tosca.Transaction, context tosca.TransactionCon
 		context.AccessAccount(*transaction.Recipient)
 	}
 
-	precompiles := getPrecompiledAddresses(revision)
 	for _, address := range precompiles {
 		context.AccessAccount(address)
 	}
@@ -164,16 +386,28 @@ func callParameters(transaction tosca.Transaction, gas tosca.Gas) tosca.CallPara
 	return callParameters
 }
 
-func calculateGasLeft(transaction tosca.Transaction, result tosca.CallResult, revision tosca.Revision) tosca.Gas {
+// calculateGasLeft computes the gas to be returned to the sender of the given
+// transaction. Refunds of the execution are only granted to successful
+// transactions, while the refund of authorizations is granted in any case.
+func calculateGasLeft(
+	transaction tosca.Transaction,
+	result tosca.CallResult,
+	authorizationRefund tosca.Gas,
+	revision tosca.Revision,
+	policy FeePolicy,
+) tosca.Gas {
 	gasLeft := result.GasLeft
-	// 10% of remaining gas is charged for non-internal transactions
-	if transaction.Sender != (tosca.Address{}) {
+	// 10% of remaining gas is charged for non-system transactions
+	if policy == SonicFeePolicy && transaction.Kind != tosca.SystemTransaction {
 		gasLeft -= gasLeft / 10
 	}
 
+	refund := authorizationRefund
 	if result.Success {
+		refund += result.GasRefund
+	}
+	if refund != 0 {
 		gasUsed := transaction.GasLimit - gasLeft
-		refund := result.GasRefund
 
 		maxRefund := tosca.Gas(0)
 		if revision < tosca.R10_London {
@@ -193,14 +427,14 @@ func calculateGasLeft(transaction tosca.Transaction, result tosca.CallResult, re
 	return gasLeft
 }
 
-func refundGas(transaction tosca.Transaction, context tosca.TransactionContext, gasLeft tosca.Gas) {
-	refundValue := transaction.GasPrice.Scale(uint64(gasLeft))
+func refundGas(transaction tosca.Transaction, context tosca.TransactionContext, gasPrice tosca.Value, gasLeft tosca.Gas) {
+	refundValue := gasPrice.Scale(uint64(gasLeft))
 	senderBalance := context.GetBalance(transaction.Sender)
 	senderBalance = tosca.Add(senderBalance, refundValue)
 	context.SetBalance(transaction.Sender, senderBalance)
 }
 
-func setupGasBilling(transaction tosca.Transaction) tosca.Gas {
+func setupGasBilling(transaction tosca.Transaction, revision tosca.Revision) tosca.Gas {
 	var gas tosca.Gas
 	if transaction.Recipient == nil {
 		gas = TxGasContractCreation
@@ -218,6 +452,11 @@ func setupGasBilling(transaction tosca.Transaction) tosca.Gas {
 		zeroBytes := tosca.Gas(len(transaction.Input)) - nonZeroBytes
 		gas += zeroBytes * TxDataZeroGasEIP2028
 		gas += nonZeroBytes * TxDataNonZeroGasEIP2028
+
+		// Since Shanghai (EIP-3860) init code is charged per word.
+		if transaction.Recipient == nil && revision >= tosca.R12_Shanghai {
+			gas += tosca.Gas(tosca.SizeInWords(uint64(len(transaction.Input)))) * initCodeWordGas
+		}
 	}
 
 	// No overflow check for the gas computation is required although it is performed in the
@@ -231,29 +470,61 @@ func setupGasBilling(transaction tosca.Transaction) tosca.Gas {
 			gas += tosca.Gas(len(accessTuple.Keys)) * TxAccessListStorageKeyGas
 		}
 	}
+	gas += tosca.Gas(len(transaction.AuthorizationList)) * PerEmptyAccountCost
 
 	return tosca.Gas(gas)
 }
 
-func handleNonce(transaction tosca.Transaction, context tosca.TransactionContext) error {
+// floorDataGas computes the minimum amount of gas to be paid by the given
+// transaction for its data, as introduced by EIP-7623.
+func floorDataGas(transaction tosca.Transaction) tosca.Gas {
+	tokens := tosca.Gas(len(transaction.Input))
+	for _, inputByte := range transaction.Input {
+		if inputByte != 0 {
+			tokens += TxTokenPerNonZeroByte - 1
+		}
+	}
+	return TxGas + tokens*TxCostFloorPerToken
+}
+
+func checkNonce(transaction tosca.Transaction, context tosca.TransactionContext) error {
 	stateNonce := context.GetNonce(transaction.Sender)
 	messageNonce := transaction.Nonce
-	if messageNonce != stateNonce {
-		return fmt.Errorf("nonce mismatch: %v != %v", messageNonce, stateNonce)
+	if messageNonce < stateNonce {
+		return fmt.Errorf("%w: address %v, tx: %d state: %d", tosca.ErrNonceTooLow, transaction.Sender, messageNonce, stateNonce)
 	}
-	if transaction.Recipient != nil {
-		context.SetNonce(transaction.Sender, stateNonce+1)
+	if messageNonce > stateNonce {
+		return fmt.Errorf("%w: address %v, tx: %d state: %d", tosca.ErrNonceTooHigh, transaction.Sender, messageNonce, stateNonce)
+	}
+	return nil
+}
+
+// checkSenderIsEOA verifies that the sender of the given transaction has no
+// code. Since EIP-7702, senders may have delegated the execution of code.
+func checkSenderIsEOA(transaction tosca.Transaction, context tosca.TransactionContext) error {
+	if codeHash := context.GetCodeHash(transaction.Sender); codeHash != emptyCodeHash && codeHash != (tosca.Hash{}) {
+		if _, delegated := tosca.GetDelegationTarget(context.GetCode(transaction.Sender)); delegated {
+			return nil
+		}
+		return fmt.Errorf("%w: address %v, codehash: %v", tosca.ErrSenderNoEOA, transaction.Sender, codeHash)
 	}
 	return nil
 }
 
-func buyGas(transaction tosca.Transaction, context tosca.TransactionContext) error {
-	gas := transaction.GasPrice.Scale(uint64(transaction.GasLimit))
+// buyGas charges the sender of the given transaction for its gas limit at the
+// given effective gas price and for the given blob gas at the given blob base
+// fee. Following EIP-1559 and EIP-4844, the sender must be able to pay for the
+// gas limit and the blob gas at the fee caps of the transaction and for the
+// transferred value, even though only the effective prices are charged.
+func buyGas(transaction tosca.Transaction, context tosca.TransactionContext, gasPrice tosca.Value, blobGas tosca.Gas, blobBaseFee tosca.Value) error {
+	gas := tosca.Add(gasPrice.Scale(uint64(transaction.GasLimit)), blobBaseFee.Scale(uint64(blobGas)))
+	required := tosca.Add(transaction.GetGasFeeCap().Scale(uint64(transaction.GasLimit)), transaction.Value)
+	required = tosca.Add(required, transaction.BlobGasFeeCap.Scale(uint64(blobGas)))
 
 	// Buy gas
 	senderBalance := context.GetBalance(transaction.Sender)
-	if senderBalance.Cmp(gas) < 0 {
-		return fmt.Errorf("insufficient balance: %v < %v", senderBalance, gas)
+	if senderBalance.Cmp(required) < 0 {
+		return fmt.Errorf("%w: address %v have %v want %v", tosca.ErrInsufficientFunds, transaction.Sender, senderBalance, required)
 	}
 
 	senderBalance = tosca.Sub(senderBalance, gas)
@@ -11,6 +11,8 @@
 package floria
 
 import (
+	"bytes"
+	"errors"
 	"reflect"
 	"testing"
 
@@ -30,43 +32,599 @@ func TestProcessorRegistry_InitProcessor(t *testing.T) {
 	}
 }
 
-func TestProcessor_HandleNonce(t *testing.T) {
+func TestProcessorRegistry_NewProcessorAcceptsConfiguration(t *testing.T) {
 	ctrl := gomock.NewController(t)
+	interpreter := tosca.NewMockInterpreter(ctrl)
+
+	config := Config{FeePolicy: EthereumFeePolicy, WithoutStateContracts: true}
+	for _, c := range []any{nil, config, &config} {
+		instance, err := tosca.NewProcessor("floria", interpreter, c)
+		if err != nil {
+			t.Fatalf("failed to create processor with config %v: %v", c, err)
+		}
+		got := instance.(*processor).config
+		if c != nil && (got.FeePolicy != config.FeePolicy || got.WithoutStateContracts != config.WithoutStateContracts) {
+			t.Errorf("configuration was not applied, wanted %v, got %v", config, got)
+		}
+	}
+}
+
+func TestProcessorRegistry_NewProcessorRejectsInvalidConfiguration(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	interpreter := tosca.NewMockInterpreter(ctrl)
+
+	tests := map[string]struct {
+		interpreter tosca.Interpreter
+		config      any
+	}{
+		"unsupported type":    {interpreter, "floria"},
+		"unknown fee policy":  {interpreter, Config{FeePolicy: FeePolicy(12)}},
+		"negative allowance":  {interpreter, Config{SystemTransactionGasAllowance: -1}},
+		"missing interpreter": {nil, Config{}},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := tosca.NewProcessor("floria", test.interpreter, test.config); err == nil {
+				t.Errorf("expected configuration to be rejected")
+			}
+		})
+	}
+}
+
+func TestProcessor_RejectsBlocksOfOtherChains(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	interpreter := tosca.NewMockInterpreter(ctrl)
 	context := tosca.NewMockTransactionContext(ctrl)
 
-	context.EXPECT().GetNonce(tosca.Address{1}).Return(uint64(9))
-	context.EXPECT().SetNonce(tosca.Address{1}, uint64(10))
-	context.EXPECT().GetNonce(tosca.Address{1}).Return(uint64(10))
+	processor, err := NewProcessor(interpreter, Config{ChainId: tosca.Word{31: 1}})
+	if err != nil {
+		t.Fatalf("failed to create processor: %v", err)
+	}
+	blockParameters := tosca.BlockParameters{ChainID: tosca.Word{31: 2}}
+	if _, err := processor.Run(blockParameters, tosca.Transaction{}, context); err == nil {
+		t.Errorf("expected block of other chain to be rejected")
+	}
+}
+
+func TestProcessor_RunsTransactionsOnInMemoryTransactionContext(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	interpreter := tosca.NewMockInterpreter(ctrl)
+
+	sender := tosca.Address{1}
+	recipient := tosca.Address{2}
+	context := tosca.NewInMemoryTransactionContext(tosca.R13_Cancun, map[tosca.Address]tosca.Account{
+		sender:    {Balance: tosca.NewValue(1_000_000)},
+		recipient: {Code: tosca.Code{byte(0)}},
+	})
+
+	interpreter.EXPECT().Run(gomock.Any()).DoAndReturn(func(params tosca.Parameters) (tosca.Result, error) {
+		params.Context.SetStorage(params.Recipient, tosca.Key{1}, tosca.Word{2})
+		params.Context.EmitLog(tosca.Log{Address: params.Recipient})
+		return tosca.Result{Success: true, GasLeft: params.Gas}, nil
+	})
 
+	processor, err := NewProcessor(interpreter, Config{WithoutStateContracts: true})
+	if err != nil {
+		t.Fatalf("failed to create processor: %v", err)
+	}
 	transaction := tosca.Transaction{
-		Sender:    tosca.Address{1},
-		Recipient: &tosca.Address{2},
-		Nonce:     9,
+		Sender:    sender,
+		Recipient: &recipient,
+		GasLimit:  TxGas,
+		GasPrice:  tosca.NewValue(1),
+		Value:     tosca.NewValue(10),
+	}
+	receipt, err := processor.Run(tosca.BlockParameters{Revision: tosca.R13_Cancun}, transaction, context)
+	if err != nil || !receipt.Success {
+		t.Fatalf("failed to run transaction, receipt %v, error %v", receipt, err)
+	}
+	context.EndTransaction()
+
+	accounts := context.GetAccounts()
+	if want, got := tosca.NewValue(10), accounts[recipient].Balance; want != got {
+		t.Errorf("unexpected recipient balance, wanted %v, got %v", want, got)
+	}
+	if want, got := tosca.NewValue(1_000_000-10-uint64(TxGas)), accounts[sender].Balance; want != got {
+		t.Errorf("unexpected sender balance, wanted %v, got %v", want, got)
+	}
+	if want, got := uint64(1), accounts[sender].Nonce; want != got {
+		t.Errorf("unexpected sender nonce, wanted %d, got %d", want, got)
+	}
+	if want, got := (tosca.Word{2}), accounts[recipient].Storage[tosca.Key{1}]; want != got {
+		t.Errorf("unexpected storage value, wanted %v, got %v", want, got)
 	}
+	if want, got := 1, len(receipt.Logs); want != got {
+		t.Errorf("unexpected number of logs, wanted %d, got %d", want, got)
+	}
+}
 
-	err := handleNonce(transaction, context)
+func TestProcessor_SimulateSkipsPreChecksAndGasPurchase(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	interpreter := tosca.NewMockInterpreter(ctrl)
+
+	// The sender has code, no balance, and a nonce not matching the
+	// transaction, each of which would cause a rejection by Run.
+	sender := tosca.Address{1}
+	recipient := tosca.Address{2}
+	context := tosca.NewInMemoryTransactionContext(tosca.R13_Cancun, map[tosca.Address]tosca.Account{
+		sender:    {Nonce: 7, Code: tosca.Code{byte(0)}},
+		recipient: {Code: tosca.Code{byte(0)}},
+	})
+
+	interpreter.EXPECT().Run(gomock.Any()).DoAndReturn(func(params tosca.Parameters) (tosca.Result, error) {
+		if want, got := tosca.NewValue(2), params.GasPrice; want != got {
+			t.Errorf("unexpected gas price, wanted %v, got %v", want, got)
+		}
+		return tosca.Result{Success: true, Output: []byte{1}, GasLeft: params.Gas}, nil
+	})
+
+	processor, err := NewProcessor(interpreter, Config{WithoutStateContracts: true})
 	if err != nil {
-		t.Errorf("handleNonce returned an error: %v", err)
+		t.Fatalf("failed to create processor: %v", err)
+	}
+	transaction := tosca.Transaction{
+		Sender:    sender,
+		Recipient: &recipient,
+		Nonce:     3,
+		GasLimit:  TxGas,
+		GasFeeCap: tosca.NewValue(2),
 	}
-	if context.GetNonce(transaction.Sender) != 10 {
-		t.Errorf("Nonce was not incremented")
+	block := tosca.BlockParameters{BaseFee: tosca.NewValue(5), Revision: tosca.R13_Cancun}
+	receipt, err := processor.(tosca.Simulator).Simulate(block, transaction, context)
+	if err != nil || !receipt.Success {
+		t.Fatalf("failed to simulate transaction, receipt %v, error %v", receipt, err)
+	}
+	if want, got := []byte{1}, receipt.Output; !bytes.Equal(want, got) {
+		t.Errorf("unexpected output, wanted %v, got %v", want, got)
+	}
+	if want, got := (tosca.Value{}), context.GetBalance(sender); want != got {
+		t.Errorf("sender was charged, wanted balance %v, got %v", want, got)
+	}
+	if want, got := uint64(8), context.GetNonce(sender); want != got {
+		t.Errorf("unexpected sender nonce, wanted %d, got %d", want, got)
 	}
 }
 
-func TestProcessor_NonceMissmatch(t *testing.T) {
+func TestProcessor_SystemTransactionsSkipPreChecksAndGasPurchase(t *testing.T) {
 	ctrl := gomock.NewController(t)
-	context := tosca.NewMockTransactionContext(ctrl)
+	interpreter := tosca.NewMockInterpreter(ctrl)
 
-	context.EXPECT().GetNonce(tosca.Address{1}).Return(uint64(5))
+	// The sender has code, no balance, and a nonce not matching the
+	// transaction, each of which would cause a rejection of regular
+	// transactions.
+	sender := tosca.Address{1}
+	recipient := tosca.Address{2}
+	context := tosca.NewInMemoryTransactionContext(tosca.R13_Cancun, map[tosca.Address]tosca.Account{
+		sender:    {Nonce: 7, Code: tosca.Code{byte(0)}},
+		recipient: {Code: tosca.Code{byte(0)}},
+	})
+
+	interpreter.EXPECT().Run(gomock.Any()).DoAndReturn(func(params tosca.Parameters) (tosca.Result, error) {
+		return tosca.Result{Success: true, GasLeft: params.Gas}, nil
+	})
 
+	processor, err := NewProcessor(interpreter, Config{WithoutStateContracts: true})
+	if err != nil {
+		t.Fatalf("failed to create processor: %v", err)
+	}
 	transaction := tosca.Transaction{
-		Sender:    tosca.Address{1},
-		Recipient: &tosca.Address{2},
-		Nonce:     10,
+		Sender:    sender,
+		Recipient: &recipient,
+		Nonce:     3,
+		GasLimit:  TxGas + 1000,
+		GasFeeCap: tosca.NewValue(2),
+		Kind:      tosca.SystemTransaction,
 	}
-	err := handleNonce(transaction, context)
-	if err == nil {
-		t.Errorf("handleNonce did not spot nonce miss match")
+	block := tosca.BlockParameters{BaseFee: tosca.NewValue(5), Revision: tosca.R13_Cancun}
+	receipt, err := processor.Run(block, transaction, context)
+	if err != nil || !receipt.Success {
+		t.Fatalf("failed to run system transaction, receipt %v, error %v", receipt, err)
+	}
+	// No share of the unused gas is charged for system transactions.
+	if want, got := tosca.Gas(TxGas), receipt.GasUsed; want != got {
+		t.Errorf("unexpected gas used, wanted %d, got %d", want, got)
+	}
+	if want, got := (tosca.Value{}), context.GetBalance(sender); want != got {
+		t.Errorf("sender was charged, wanted balance %v, got %v", want, got)
+	}
+	if want, got := uint64(8), context.GetNonce(sender); want != got {
+		t.Errorf("unexpected sender nonce, wanted %d, got %d", want, got)
+	}
+}
+
+func TestProcessor_SystemTransactionsUseConfiguredGasAllowance(t *testing.T) {
+	const allowance = 50_000
+	tests := map[string]struct {
+		kind    tosca.TransactionKind
+		wantGas tosca.Gas
+	}{
+		"regular": {tosca.RegularTransaction, 100},
+		"system":  {tosca.SystemTransaction, allowance - TxGas},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			interpreter := tosca.NewMockInterpreter(ctrl)
+
+			sender := tosca.Address{1}
+			recipient := tosca.Address{2}
+			context := tosca.NewInMemoryTransactionContext(tosca.R13_Cancun, map[tosca.Address]tosca.Account{
+				sender:    {Balance: tosca.NewValue(1_000_000)},
+				recipient: {Code: tosca.Code{byte(0)}},
+			})
+
+			interpreter.EXPECT().Run(gomock.Any()).DoAndReturn(func(params tosca.Parameters) (tosca.Result, error) {
+				if want, got := test.wantGas, params.Gas; want != got {
+					t.Errorf("unexpected gas, wanted %d, got %d", want, got)
+				}
+				return tosca.Result{Success: true}, nil
+			})
+
+			config := Config{WithoutStateContracts: true, SystemTransactionGasAllowance: allowance}
+			processor, err := NewProcessor(interpreter, config)
+			if err != nil {
+				t.Fatalf("failed to create processor: %v", err)
+			}
+			transaction := tosca.Transaction{
+				Sender:    sender,
+				Recipient: &recipient,
+				GasLimit:  TxGas + 100,
+				Kind:      test.kind,
+			}
+			receipt, err := processor.Run(tosca.BlockParameters{Revision: tosca.R13_Cancun}, transaction, context)
+			if err != nil || !receipt.Success {
+				t.Fatalf("failed to run transaction, receipt %v, error %v", receipt, err)
+			}
+			if want, got := TxGas+test.wantGas, receipt.GasUsed; want != got {
+				t.Errorf("unexpected gas used, wanted %d, got %d", want, got)
+			}
+		})
+	}
+}
+
+func TestProcessor_CalculateGasLeftWithEthereumFeePolicyDoesNotChargeUnusedGas(t *testing.T) {
+	transaction := tosca.Transaction{
+		Sender:   tosca.Address{1},
+		GasLimit: 1000,
+	}
+	result := tosca.CallResult{
+		GasLeft: 500,
+		Success: true,
+	}
+	if want, got := tosca.Gas(450), calculateGasLeft(transaction, result, 0, tosca.R10_London, SonicFeePolicy); want != got {
+		t.Errorf("unexpected gas left for Sonic fee policy, wanted %d, got %d", want, got)
+	}
+	if want, got := tosca.Gas(500), calculateGasLeft(transaction, result, 0, tosca.R10_London, EthereumFeePolicy); want != got {
+		t.Errorf("unexpected gas left for Ethereum fee policy, wanted %d, got %d", want, got)
+	}
+}
+
+func TestProcessor_CheckNonce(t *testing.T) {
+	tests := map[string]struct {
+		nonce uint64
+		want  error
+	}{
+		"matching": {nonce: 9, want: nil},
+		"too low":  {nonce: 8, want: tosca.ErrNonceTooLow},
+		"too high": {nonce: 10, want: tosca.ErrNonceTooHigh},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			context := tosca.NewMockTransactionContext(ctrl)
+			context.EXPECT().GetNonce(tosca.Address{1}).Return(uint64(9))
+
+			transaction := tosca.Transaction{
+				Sender:    tosca.Address{1},
+				Recipient: &tosca.Address{2},
+				Nonce:     test.nonce,
+			}
+			if err := checkNonce(transaction, context); !errors.Is(err, test.want) {
+				t.Errorf("unexpected error, wanted %v, got %v", test.want, err)
+			}
+		})
+	}
+}
+
+func TestProcessor_CheckSenderIsEOA(t *testing.T) {
+	tests := map[string]struct {
+		codeHash tosca.Hash
+		code     tosca.Code
+		want     error
+	}{
+		"non-existing account":  {codeHash: tosca.Hash{}, want: nil},
+		"account without code":  {codeHash: emptyCodeHash, want: nil},
+		"account with code":     {codeHash: tosca.Hash{1}, code: tosca.Code{1}, want: tosca.ErrSenderNoEOA},
+		"account with delegate": {codeHash: tosca.Hash{1}, code: tosca.NewDelegationDesignator(tosca.Address{2}), want: nil},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			context := tosca.NewMockTransactionContext(ctrl)
+			context.EXPECT().GetCodeHash(tosca.Address{1}).Return(test.codeHash)
+			context.EXPECT().GetCode(tosca.Address{1}).Return(test.code).AnyTimes()
+
+			transaction := tosca.Transaction{Sender: tosca.Address{1}}
+			if err := checkSenderIsEOA(transaction, context); !errors.Is(err, test.want) {
+				t.Errorf("unexpected error, wanted %v, got %v", test.want, err)
+			}
+		})
+	}
+}
+
+func TestProcessor_InvalidTransactionsAreRejectedWithoutModifyingTheState(t *testing.T) {
+	sender := tosca.Address{1}
+	recipient := tosca.Address{2}
+	valid := tosca.Transaction{
+		Sender:    sender,
+		Recipient: &recipient,
+		Nonce:     4,
+		GasLimit:  TxGas,
+		GasPrice:  tosca.NewValue(1),
+	}
+
+	tests := map[string]struct {
+		modify func(*tosca.Transaction, map[tosca.Address]tosca.Account)
+		want   error
+	}{
+		"nonce too low": {
+			modify: func(tx *tosca.Transaction, _ map[tosca.Address]tosca.Account) { tx.Nonce = 3 },
+			want:   tosca.ErrNonceTooLow,
+		},
+		"nonce too high": {
+			modify: func(tx *tosca.Transaction, _ map[tosca.Address]tosca.Account) { tx.Nonce = 5 },
+			want:   tosca.ErrNonceTooHigh,
+		},
+		"insufficient funds": {
+			modify: func(tx *tosca.Transaction, _ map[tosca.Address]tosca.Account) { tx.GasPrice = tosca.NewValue(1_000) },
+			want:   tosca.ErrInsufficientFunds,
+		},
+		"intrinsic gas too low": {
+			modify: func(tx *tosca.Transaction, _ map[tosca.Address]tosca.Account) { tx.GasLimit = TxGas - 1 },
+			want:   tosca.ErrIntrinsicGas,
+		},
+		"init code too large": {
+			modify: func(tx *tosca.Transaction, _ map[tosca.Address]tosca.Account) {
+				tx.Recipient = nil
+				tx.Input = make([]byte, maxInitCodeSize+1)
+				tx.GasLimit = 1_000_000
+			},
+			want: tosca.ErrMaxInitCodeSizeExceeded,
+		},
+		"sender not an eoa": {
+			modify: func(_ *tosca.Transaction, accounts map[tosca.Address]tosca.Account) {
+				account := accounts[sender]
+				account.Code = tosca.Code{byte(0)}
+				accounts[sender] = account
+			},
+			want: tosca.ErrSenderNoEOA,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			interpreter := tosca.NewMockInterpreter(ctrl)
+
+			transaction := valid
+			accounts := map[tosca.Address]tosca.Account{
+				sender: {Balance: tosca.NewValue(100_000), Nonce: 4},
+			}
+			test.modify(&transaction, accounts)
+			context := tosca.NewInMemoryTransactionContext(tosca.R13_Cancun, accounts)
+
+			processor, err := NewProcessor(interpreter, Config{})
+			if err != nil {
+				t.Fatalf("failed to create processor: %v", err)
+			}
+			blockParameters := tosca.BlockParameters{Revision: tosca.R13_Cancun}
+			if _, err := processor.Run(blockParameters, transaction, context); !errors.Is(err, test.want) {
+				t.Errorf("unexpected error, wanted %v, got %v", test.want, err)
+			}
+			if want, got := accounts, context.GetAccounts(); !reflect.DeepEqual(want, got) {
+				t.Errorf("rejected transaction modified the state, wanted %v, got %v", want, got)
+			}
+		})
+	}
+}
+
+func TestProcessor_CheckFeesComputesEffectiveGasPrice(t *testing.T) {
+	tests := map[string]struct {
+		revision    tosca.Revision
+		transaction tosca.Transaction
+		want        tosca.Value
+	}{
+		"legacy before london": {
+			revision:    tosca.R09_Berlin,
+			transaction: tosca.Transaction{GasPrice: tosca.NewValue(5)},
+			want:        tosca.NewValue(5),
+		},
+		"legacy after london": {
+			revision:    tosca.R10_London,
+			transaction: tosca.Transaction{GasPrice: tosca.NewValue(5)},
+			want:        tosca.NewValue(5),
+		},
+		"dynamic fee limited by tip cap": {
+			revision:    tosca.R13_Cancun,
+			transaction: tosca.Transaction{GasFeeCap: tosca.NewValue(10), GasTipCap: tosca.NewValue(2)},
+			want:        tosca.NewValue(5),
+		},
+		"dynamic fee limited by fee cap": {
+			revision:    tosca.R13_Cancun,
+			transaction: tosca.Transaction{GasFeeCap: tosca.NewValue(4), GasTipCap: tosca.NewValue(2)},
+			want:        tosca.NewValue(4),
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			blockParameters := tosca.BlockParameters{Revision: test.revision, BaseFee: tosca.NewValue(3)}
+			got, err := checkFees(blockParameters, test.transaction)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if test.want != got {
+				t.Errorf("unexpected effective gas price, wanted %v, got %v", test.want, got)
+			}
+		})
+	}
+}
+
+func TestProcessor_CheckFeesRejectsInvalidFees(t *testing.T) {
+	tests := map[string]struct {
+		revision    tosca.Revision
+		transaction tosca.Transaction
+		want        error
+	}{
+		"dynamic fee before london": {
+			revision:    tosca.R09_Berlin,
+			transaction: tosca.Transaction{GasFeeCap: tosca.NewValue(10), GasTipCap: tosca.NewValue(2)},
+			want:        tosca.ErrTxTypeNotSupported,
+		},
+		"gas price below base fee": {
+			revision:    tosca.R10_London,
+			transaction: tosca.Transaction{GasPrice: tosca.NewValue(2)},
+			want:        tosca.ErrFeeCapTooLow,
+		},
+		"fee cap below base fee": {
+			revision:    tosca.R13_Cancun,
+			transaction: tosca.Transaction{GasFeeCap: tosca.NewValue(2), GasTipCap: tosca.NewValue(1)},
+			want:        tosca.ErrFeeCapTooLow,
+		},
+		"tip cap above fee cap": {
+			revision:    tosca.R13_Cancun,
+			transaction: tosca.Transaction{GasFeeCap: tosca.NewValue(4), GasTipCap: tosca.NewValue(5)},
+			want:        tosca.ErrTipAboveFeeCap,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			blockParameters := tosca.BlockParameters{Revision: test.revision, BaseFee: tosca.NewValue(3)}
+			if _, err := checkFees(blockParameters, test.transaction); !errors.Is(err, test.want) {
+				t.Errorf("unexpected error, wanted %v, got %v", test.want, err)
+			}
+		})
+	}
+}
+
+func TestProcessor_CheckBlobsComputesBlobGas(t *testing.T) {
+	recipient := tosca.Address{1}
+	blockParameters := tosca.BlockParameters{Revision: tosca.R13_Cancun, BlobBaseFee: tosca.NewValue(2)}
+	for _, count := range []int{0, 1, 6} {
+		transaction := tosca.Transaction{
+			Recipient:     &recipient,
+			BlobHashes:    make([]tosca.Hash, count),
+			BlobGasFeeCap: tosca.NewValue(2),
+		}
+		for i := range transaction.BlobHashes {
+			transaction.BlobHashes[i][0] = BlobTxHashVersion
+		}
+		got, err := checkBlobs(blockParameters, transaction)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if want := tosca.Gas(count * BlobTxBlobGasPerBlob); want != got {
+			t.Errorf("unexpected blob gas, wanted %d, got %d", want, got)
+		}
+	}
+}
+
+func TestProcessor_CheckBlobsRejectsInvalidBlobTransactions(t *testing.T) {
+	recipient := tosca.Address{1}
+	validHash := tosca.Hash{BlobTxHashVersion}
+	valid := tosca.Transaction{
+		Recipient:     &recipient,
+		BlobHashes:    []tosca.Hash{validHash},
+		BlobGasFeeCap: tosca.NewValue(2),
+	}
+
+	tests := map[string]struct {
+		revision tosca.Revision
+		modify   func(*tosca.Transaction)
+		want     error
+	}{
+		"before cancun": {
+			revision: tosca.R12_Shanghai,
+			modify:   func(*tosca.Transaction) {},
+			want:     tosca.ErrTxTypeNotSupported,
+		},
+		"contract creation": {
+			revision: tosca.R13_Cancun,
+			modify:   func(tx *tosca.Transaction) { tx.Recipient = nil },
+			want:     tosca.ErrBlobTxCreate,
+		},
+		"too many blobs": {
+			revision: tosca.R13_Cancun,
+			modify: func(tx *tosca.Transaction) {
+				tx.BlobHashes = []tosca.Hash{validHash, validHash, validHash, validHash, validHash, validHash, validHash}
+			},
+			want: tosca.ErrTooManyBlobs,
+		},
+		"invalid hash version": {
+			revision: tosca.R13_Cancun,
+			modify:   func(tx *tosca.Transaction) { tx.BlobHashes = []tosca.Hash{{0x02}} },
+			want:     tosca.ErrInvalidBlobHashVersion,
+		},
+		"blob fee cap below blob base fee": {
+			revision: tosca.R13_Cancun,
+			modify:   func(tx *tosca.Transaction) { tx.BlobGasFeeCap = tosca.NewValue(1) },
+			want:     tosca.ErrBlobFeeCapTooLow,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			transaction := valid
+			test.modify(&transaction)
+			blockParameters := tosca.BlockParameters{Revision: test.revision, BlobBaseFee: tosca.NewValue(2)}
+			if _, err := checkBlobs(blockParameters, transaction); !errors.Is(err, test.want) {
+				t.Errorf("unexpected error, wanted %v, got %v", test.want, err)
+			}
+		})
+	}
+}
+
+func TestProcessor_BlobTransactionsPayBlobGas(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	interpreter := tosca.NewMockInterpreter(ctrl)
+
+	sender := tosca.Address{1}
+	recipient := tosca.Address{2}
+	blobHashes := []tosca.Hash{{BlobTxHashVersion, 1}, {BlobTxHashVersion, 2}}
+	context := tosca.NewInMemoryTransactionContext(tosca.R13_Cancun, map[tosca.Address]tosca.Account{
+		sender:    {Balance: tosca.NewValue(1_000_000)},
+		recipient: {Code: tosca.Code{byte(0)}},
+	})
+
+	interpreter.EXPECT().Run(gomock.Any()).DoAndReturn(func(params tosca.Parameters) (tosca.Result, error) {
+		if want, got := blobHashes, params.BlobHashes; !reflect.DeepEqual(want, got) {
+			t.Errorf("unexpected blob hashes, wanted %v, got %v", want, got)
+		}
+		return tosca.Result{Success: true, GasLeft: params.Gas}, nil
+	})
+
+	processor, err := NewProcessor(interpreter, Config{})
+	if err != nil {
+		t.Fatalf("failed to create processor: %v", err)
+	}
+	transaction := tosca.Transaction{
+		Sender:        sender,
+		Recipient:     &recipient,
+		GasLimit:      TxGas,
+		GasPrice:      tosca.NewValue(1),
+		BlobHashes:    blobHashes,
+		BlobGasFeeCap: tosca.NewValue(3),
+	}
+	blockParameters := tosca.BlockParameters{Revision: tosca.R13_Cancun, BlobBaseFee: tosca.NewValue(2)}
+	receipt, err := processor.Run(blockParameters, transaction, context)
+	if err != nil || !receipt.Success {
+		t.Fatalf("failed to run transaction, receipt %v, error %v", receipt, err)
+	}
+
+	if want, got := tosca.Gas(2*BlobTxBlobGasPerBlob), receipt.BlobGasUsed; want != got {
+		t.Errorf("unexpected blob gas used, wanted %d, got %d", want, got)
+	}
+	want := tosca.NewValue(1_000_000 - TxGas - 2*2*BlobTxBlobGasPerBlob)
+	if got := context.GetBalance(sender); want != got {
+		t.Errorf("unexpected sender balance, wanted %v, got %v", want, got)
 	}
 }
 
@@ -87,7 +645,7 @@ func TestProcessor_BuyGas(t *testing.T) {
 	context.EXPECT().SetBalance(transaction.Sender, tosca.NewValue(balance-gasLimit*gasPrice))
 	context.EXPECT().GetBalance(transaction.Sender).Return(tosca.NewValue(balance - gasLimit*gasPrice))
 
-	err := buyGas(transaction, context)
+	err := buyGas(transaction, context, transaction.GasPrice, 0, tosca.Value{})
 	if err != nil {
 		t.Errorf("buyGas returned an error: %v", err)
 	}
@@ -111,12 +669,93 @@ func TestProcessor_BuyGasInsufficientBalance(t *testing.T) {
 	context := tosca.NewMockTransactionContext(ctrl)
 	context.EXPECT().GetBalance(transaction.Sender).Return(tosca.NewValue(balance))
 
-	err := buyGas(transaction, context)
+	err := buyGas(transaction, context, transaction.GasPrice, 0, tosca.Value{})
 	if err == nil {
 		t.Errorf("buyGas did not fail with insufficient balance")
 	}
 }
 
+func TestProcessor_BuyGasRequiresBalanceForFeeCapAndValue(t *testing.T) {
+	sender := tosca.Address{1}
+	transaction := tosca.Transaction{
+		Sender:    sender,
+		GasLimit:  100,
+		GasFeeCap: tosca.NewValue(5),
+		GasTipCap: tosca.NewValue(1),
+		Value:     tosca.NewValue(10),
+	}
+	gasPrice := tosca.NewValue(2) // < the effective gas price is below the fee cap
+
+	tests := map[string]struct {
+		balance uint64
+		want    error
+	}{
+		"covers effective price only":    {balance: 100 * 2, want: tosca.ErrInsufficientFunds},
+		"covers fee cap but not value":   {balance: 100 * 5, want: tosca.ErrInsufficientFunds},
+		"covers fee cap and value":       {balance: 100*5 + 10},
+		"covers more than fee and value": {balance: 1000},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			context := tosca.NewInMemoryTransactionContext(tosca.R13_Cancun, map[tosca.Address]tosca.Account{
+				sender: {Balance: tosca.NewValue(test.balance)},
+			})
+			err := buyGas(transaction, context, gasPrice, 0, tosca.Value{})
+			if !errors.Is(err, test.want) {
+				t.Fatalf("unexpected error, wanted %v, got %v", test.want, err)
+			}
+			want := tosca.NewValue(test.balance)
+			if test.want == nil {
+				want = tosca.NewValue(test.balance - 100*2)
+			}
+			if got := context.GetBalance(sender); want != got {
+				t.Errorf("unexpected balance, wanted %v, got %v", want, got)
+			}
+		})
+	}
+}
+
+func TestProcessor_BuyGasRequiresBalanceForBlobFeeCap(t *testing.T) {
+	sender := tosca.Address{1}
+	transaction := tosca.Transaction{
+		Sender:        sender,
+		GasLimit:      100,
+		GasPrice:      tosca.NewValue(1),
+		BlobHashes:    []tosca.Hash{{BlobTxHashVersion}},
+		BlobGasFeeCap: tosca.NewValue(3),
+	}
+	blobGas := tosca.Gas(BlobTxBlobGasPerBlob)
+	blobBaseFee := tosca.NewValue(2) // < the blob base fee is below the fee cap
+
+	tests := map[string]struct {
+		balance uint64
+		want    error
+	}{
+		"covers blob base fee only": {balance: 100 + 2*uint64(blobGas), want: tosca.ErrInsufficientFunds},
+		"covers blob fee cap":       {balance: 100 + 3*uint64(blobGas)},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			context := tosca.NewInMemoryTransactionContext(tosca.R13_Cancun, map[tosca.Address]tosca.Account{
+				sender: {Balance: tosca.NewValue(test.balance)},
+			})
+			err := buyGas(transaction, context, transaction.GasPrice, blobGas, blobBaseFee)
+			if !errors.Is(err, test.want) {
+				t.Fatalf("unexpected error, wanted %v, got %v", test.want, err)
+			}
+			want := tosca.NewValue(test.balance)
+			if test.want == nil {
+				want = tosca.NewValue(test.balance - 100 - 2*uint64(blobGas))
+			}
+			if got := context.GetBalance(sender); want != got {
+				t.Errorf("unexpected balance, wanted %v, got %v", want, got)
+			}
+		})
+	}
+}
+
 func TestGasUsed(t *testing.T) {
 	tests := map[string]struct {
 		transaction     tosca.Transaction
@@ -124,9 +763,9 @@ func TestGasUsed(t *testing.T) {
 		revision        tosca.Revision
 		expectedGasLeft tosca.Gas
 	}{
-		"InternalTransaction": {
+		"SystemTransaction": {
 			transaction: tosca.Transaction{
-				Sender:   tosca.Address{},
+				Kind:     tosca.SystemTransaction,
 				GasLimit: 1000,
 			},
 			result: tosca.CallResult{
@@ -137,7 +776,7 @@ func TestGasUsed(t *testing.T) {
 			revision:        tosca.R10_London,
 			expectedGasLeft: 500,
 		},
-		"NonInternalTransaction": {
+		"RegularTransaction": {
 			transaction: tosca.Transaction{
 				Sender:   tosca.Address{1},
 				GasLimit: 1000,
@@ -150,11 +789,24 @@ func TestGasUsed(t *testing.T) {
 			revision:        tosca.R10_London,
 			expectedGasLeft: 450,
 		},
-		"RefundPreLondon": {
+		"RegularTransactionOfZeroAddress": {
 			transaction: tosca.Transaction{
 				Sender:   tosca.Address{},
 				GasLimit: 1000,
 			},
+			result: tosca.CallResult{
+				GasLeft:   500,
+				Success:   true,
+				GasRefund: 0,
+			},
+			revision:        tosca.R10_London,
+			expectedGasLeft: 450,
+		},
+		"RefundPreLondon": {
+			transaction: tosca.Transaction{
+				Kind:     tosca.SystemTransaction,
+				GasLimit: 1000,
+			},
 			result: tosca.CallResult{
 				GasLeft:   500,
 				Success:   true,
@@ -165,7 +817,7 @@ func TestGasUsed(t *testing.T) {
 		},
 		"RefundLondon": {
 			transaction: tosca.Transaction{
-				Sender:   tosca.Address{},
+				Kind:     tosca.SystemTransaction,
 				GasLimit: 1000,
 			},
 			result: tosca.CallResult{
@@ -178,7 +830,7 @@ func TestGasUsed(t *testing.T) {
 		},
 		"RefundPostLondon": {
 			transaction: tosca.Transaction{
-				Sender:   tosca.Address{},
+				Kind:     tosca.SystemTransaction,
 				GasLimit: 1000,
 			},
 			result: tosca.CallResult{
@@ -191,7 +843,7 @@ func TestGasUsed(t *testing.T) {
 		},
 		"smallRefund": {
 			transaction: tosca.Transaction{
-				Sender:   tosca.Address{},
+				Kind:     tosca.SystemTransaction,
 				GasLimit: 1000,
 			},
 			result: tosca.CallResult{
@@ -204,7 +856,7 @@ func TestGasUsed(t *testing.T) {
 		},
 		"UnsuccessfulResult": {
 			transaction: tosca.Transaction{
-				Sender:   tosca.Address{},
+				Kind:     tosca.SystemTransaction,
 				GasLimit: 1000,
 			},
 			result: tosca.CallResult{
@@ -219,7 +871,7 @@ func TestGasUsed(t *testing.T) {
 
 	for testName, test := range tests {
 		t.Run(testName, func(t *testing.T) {
-			actualGasLeft := calculateGasLeft(test.transaction, test.result, test.revision)
+			actualGasLeft := calculateGasLeft(test.transaction, test.result, 0, test.revision, SonicFeePolicy)
 
 			if actualGasLeft != test.expectedGasLeft {
 				t.Errorf("gasUsed returned incorrect result, got: %d, want: %d", actualGasLeft, test.expectedGasLeft)
@@ -246,7 +898,7 @@ func TestProcessor_RefundGas(t *testing.T) {
 		GasPrice: tosca.NewValue(uint64(gasPrice)),
 	}
 
-	refundGas(transaction, context, tosca.Gas(gasLeft))
+	refundGas(transaction, context, transaction.GasPrice, tosca.Gas(gasLeft))
 
 }
 
@@ -255,6 +907,7 @@ func TestProcessor_SetupGasBilling(t *testing.T) {
 		recipient       *tosca.Address
 		input           []byte
 		accessList      []tosca.AccessTuple
+		revision        tosca.Revision
 		expectedGasUsed tosca.Gas
 	}{
 		"creation": {
@@ -292,6 +945,24 @@ func TestProcessor_SetupGasBilling(t *testing.T) {
 			},
 			expectedGasUsed: TxGas + TxAccessListAddressGas + 3*TxAccessListStorageKeyGas,
 		},
+		"initCodeBeforeShanghai": {
+			recipient:       nil,
+			input:           make([]byte, 33),
+			revision:        tosca.R11_Paris,
+			expectedGasUsed: TxGasContractCreation + 33*TxDataZeroGasEIP2028,
+		},
+		"initCodeSinceShanghai": {
+			recipient:       nil,
+			input:           make([]byte, 33),
+			revision:        tosca.R12_Shanghai,
+			expectedGasUsed: TxGasContractCreation + 33*TxDataZeroGasEIP2028 + 2*initCodeWordGas,
+		},
+		"callInputSinceShanghai": {
+			recipient:       &tosca.Address{1},
+			input:           make([]byte, 33),
+			revision:        tosca.R12_Shanghai,
+			expectedGasUsed: TxGas + 33*TxDataZeroGasEIP2028,
+		},
 	}
 
 	for name, test := range tests {
@@ -302,7 +973,7 @@ func TestProcessor_SetupGasBilling(t *testing.T) {
 				AccessList: test.accessList,
 			}
 
-			actualGasUsed := setupGasBilling(transaction)
+			actualGasUsed := setupGasBilling(transaction, test.revision)
 			if actualGasUsed != test.expectedGasUsed {
 				t.Errorf("setupGasBilling returned incorrect gas used, got: %d, want: %d", actualGasUsed, test.expectedGasUsed)
 			}
@@ -310,6 +981,39 @@ func TestProcessor_SetupGasBilling(t *testing.T) {
 	}
 }
 
+func TestProcessor_FloorDataGas(t *testing.T) {
+	tests := map[string]struct {
+		input []byte
+		want  tosca.Gas
+	}{
+		"empty": {
+			input: []byte{},
+			want:  TxGas,
+		},
+		"zeros": {
+			input: []byte{0, 0, 0},
+			want:  TxGas + 3*TxCostFloorPerToken,
+		},
+		"nonZeros": {
+			input: []byte{1, 2, 3},
+			want:  TxGas + 3*TxTokenPerNonZeroByte*TxCostFloorPerToken,
+		},
+		"mixed": {
+			input: []byte{0, 1, 0, 2},
+			want:  TxGas + (2+2*TxTokenPerNonZeroByte)*TxCostFloorPerToken,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			transaction := tosca.Transaction{Input: test.input}
+			if got := floorDataGas(transaction); test.want != got {
+				t.Errorf("unexpected floor data gas, wanted %d, got %d", test.want, got)
+			}
+		})
+	}
+}
+
 func TestProcessor_CallKind(t *testing.T) {
 	tests := map[string]struct {
 		recipient *tosca.Address
@@ -383,11 +1087,11 @@ func TestProcessor_SetUpAccessList(t *testing.T) {
 	}
 
 	context.EXPECT().AccessAccount(*transaction.Recipient)
-	for _, contract := range getPrecompiledAddresses(tosca.R09_Berlin) {
+	for _, contract := range ethereumPrecompiles.Addresses(tosca.R09_Berlin) {
 		context.EXPECT().AccessAccount(contract)
 	}
 	context.EXPECT().AccessStorage(tosca.Address{2}, tosca.Key{1})
 	context.EXPECT().AccessStorage(tosca.Address{2}, tosca.Key{2})
 
-	setUpAccessList(transaction, context, tosca.R09_Berlin)
+	setUpAccessList(transaction, context, ethereumPrecompiles.Addresses(tosca.R09_Berlin))
 }
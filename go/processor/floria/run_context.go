@@ -29,11 +29,35 @@ type runContext struct {
 	transactionParameters tosca.TransactionParameters
 	depth                 int
 	static                bool
+	tracer                tosca.Tracer
+	config                Config
+}
+
+// GetBlockHash returns the hash of the block with the given number. Since
+// Prague (EIP-2935), hashes recorded by the history storage contract take
+// precedence over the hashes provided by the transaction context.
+func (r runContext) GetBlockHash(number int64) tosca.Hash {
+	if r.blockParameters.Revision >= tosca.R14_Prague {
+		if hash, found := tosca.GetHistoricalBlockHash(r.TransactionContext, r.blockParameters.BlockNumber, number); found {
+			return hash
+		}
+	}
+	return r.TransactionContext.GetBlockHash(number)
 }
 
 func (r runContext) Call(kind tosca.CallKind, parameters tosca.CallParameters) (tosca.CallResult, error) {
+	if r.tracer == nil {
+		return r.call(kind, parameters)
+	}
+	r.tracer.OnEnter(r.depth, kind, parameters)
+	result, err := r.call(kind, parameters)
+	r.tracer.OnExit(r.depth, result, err)
+	return result, err
+}
+
+func (r runContext) call(kind tosca.CallKind, parameters tosca.CallParameters) (tosca.CallResult, error) {
 	if r.depth > MaxRecursiveDepth {
-		return tosca.CallResult{}, nil
+		return tosca.CallResult{FailureCause: tosca.FailureOther}, nil
 	}
 	r.depth++
 	defer func() { r.depth-- }()
@@ -46,6 +70,15 @@ func (r runContext) Call(kind tosca.CallKind, parameters tosca.CallParameters) (
 		codeHash = r.GetCodeHash(parameters.CodeAddress)
 	}
 
+	// Since Prague (EIP-7702) accounts may delegate the execution of their
+	// code to another account. Delegations are not followed transitively.
+	if kind != tosca.Create && kind != tosca.Create2 && r.blockParameters.Revision >= tosca.R14_Prague {
+		if target, delegated := tosca.GetDelegationTarget(code); delegated {
+			code = r.GetCode(target)
+			codeHash = r.GetCodeHash(target)
+		}
+	}
+
 	recipient := parameters.Recipient
 	var createdAddress tosca.Address
 	if kind == tosca.Create || kind == tosca.Create2 {
@@ -63,10 +96,11 @@ func (r runContext) Call(kind tosca.CallKind, parameters tosca.CallParameters) (
 		if r.GetNonce(createdAddress) != 0 ||
 			(r.GetCodeHash(createdAddress) != (tosca.Hash{}) &&
 				r.GetCodeHash(createdAddress) != emptyCodeHash) {
-			return tosca.CallResult{}, nil
+			return tosca.CallResult{FailureCause: tosca.FailureOther}, nil
 		}
 
 		r.SetNonce(parameters.Sender, r.GetNonce(parameters.Sender)+1)
+		r.CreateContract(createdAddress)
 		r.SetNonce(createdAddress, 1)
 		recipient = createdAddress
 	}
@@ -81,18 +115,24 @@ func (r runContext) Call(kind tosca.CallKind, parameters tosca.CallParameters) (
 	if kind != tosca.StaticCall && kind != tosca.DelegateCall {
 		if err := transferValue(r, parameters.Value, parameters.Sender, recipient); err != nil {
 			r.RestoreSnapshot(snapshot)
-			return tosca.CallResult{}, nil
+			return tosca.CallResult{FailureCause: tosca.FailureOther}, nil
 		}
 	}
 
-	output, isStatePrecompiled := handleStateContract(
-		r, parameters.Sender, parameters.Recipient, parameters.Input, parameters.Gas)
-	if isStatePrecompiled {
+	// System contracts are only reachable through plain calls.
+	if contract, found := r.getSystemContract(recipient); found && kind == tosca.Call {
+		output := runSystemContract(r, contract, r.transactionParameters.Origin, parameters)
+		if !output.Success {
+			r.RestoreSnapshot(snapshot)
+		}
 		return output, nil
 	}
 	output, isPrecompiled := handlePrecompiledContract(
-		r.blockParameters.Revision, parameters.Input, recipient, parameters.Gas)
+		r, r.getPrecompiles(), r.blockParameters.Revision, recipient, parameters)
 	if isPrecompiled {
+		if !output.Success {
+			r.RestoreSnapshot(snapshot)
+		}
 		return output, nil
 	}
 
@@ -117,6 +157,7 @@ func (r runContext) Call(kind tosca.CallKind, parameters tosca.CallParameters) (
 		Value:                 parameters.Value,
 		CodeHash:              &codeHash,
 		Code:                  code,
+		Tracer:                r.tracer,
 	}
 
 	result, err := r.interpreter.Run(interpreterParameters)
@@ -125,26 +166,33 @@ func (r runContext) Call(kind tosca.CallKind, parameters tosca.CallParameters) (
 	} else if kind == tosca.Create || kind == tosca.Create2 {
 		code := result.Output
 		if len(code) > maxCodeSize {
-			return tosca.CallResult{}, nil
+			return tosca.CallResult{FailureCause: tosca.FailureCodeSizeLimit}, nil
 		}
 		if r.blockParameters.Revision >= tosca.R10_London && len(code) > 0 && code[0] == 0xEF {
-			return tosca.CallResult{}, nil
+			return tosca.CallResult{FailureCause: tosca.FailureOther}, nil
 		}
 		createGas := tosca.Gas(len(result.Output) * createGasCostPerByte)
 		if result.GasLeft < createGas {
-			return tosca.CallResult{}, nil
+			return tosca.CallResult{FailureCause: tosca.FailureOutOfGas}, nil
 		}
 		result.GasLeft -= createGas
 
 		r.SetCode(createdAddress, tosca.Code(result.Output))
 	}
 
+	revertReason := ""
+	if result.FailureCause == tosca.FailureRevert {
+		revertReason, _ = tosca.DecodeRevertReason(result.Output)
+	}
+
 	return tosca.CallResult{
 		Output:         result.Output,
 		GasLeft:        result.GasLeft,
 		GasRefund:      result.GasRefund,
 		Success:        result.Success,
 		CreatedAddress: createdAddress,
+		FailureCause:   result.FailureCause,
+		RevertReason:   revertReason,
 	}, err
 }
 
@@ -22,9 +22,11 @@ import (
 
 func TestCalls_InterpreterResultIsHandledCorrectly(t *testing.T) {
 	tests := map[string]struct {
-		setup   func(interpreter *tosca.MockInterpreter)
-		success bool
-		output  []byte
+		setup        func(interpreter *tosca.MockInterpreter)
+		success      bool
+		output       []byte
+		failureCause tosca.FailureCause
+		revertReason string
 	}{
 		"successful": {
 			setup: func(interpreter *tosca.MockInterpreter) {
@@ -34,9 +36,22 @@ func TestCalls_InterpreterResultIsHandledCorrectly(t *testing.T) {
 		},
 		"failed": {
 			setup: func(interpreter *tosca.MockInterpreter) {
-				interpreter.EXPECT().Run(gomock.Any()).Return(tosca.Result{Success: false}, nil)
+				interpreter.EXPECT().Run(gomock.Any()).Return(tosca.Result{Success: false, FailureCause: tosca.FailureInvalidOpCode}, nil)
 			},
-			success: false,
+			success:      false,
+			failureCause: tosca.FailureInvalidOpCode,
+		},
+		"reverted": {
+			setup: func(interpreter *tosca.MockInterpreter) {
+				interpreter.EXPECT().Run(gomock.Any()).Return(tosca.Result{
+					Output:       encodeRevertReason("some reason"),
+					FailureCause: tosca.FailureRevert,
+				}, nil)
+			},
+			success:      false,
+			output:       encodeRevertReason("some reason"),
+			failureCause: tosca.FailureRevert,
+			revertReason: "some reason",
 		},
 		"output": {
 			setup: func(interpreter *tosca.MockInterpreter) {
@@ -52,12 +67,8 @@ func TestCalls_InterpreterResultIsHandledCorrectly(t *testing.T) {
 	interpreter := tosca.NewMockInterpreter(ctrl)
 
 	runContext := runContext{
-		context,
-		interpreter,
-		tosca.BlockParameters{},
-		tosca.TransactionParameters{},
-		0,
-		false,
+		TransactionContext: context,
+		interpreter:        interpreter,
 	}
 
 	params := tosca.CallParameters{
@@ -88,21 +99,32 @@ func TestCalls_InterpreterResultIsHandledCorrectly(t *testing.T) {
 			if string(result.Output) != string(test.output) {
 				t.Errorf("Unexpected output value from interpreter call")
 			}
+			if want, got := test.failureCause, result.FailureCause; want != got {
+				t.Errorf("Unexpected failure cause, want %v, got %v", want, got)
+			}
+			if want, got := test.revertReason, result.RevertReason; want != got {
+				t.Errorf("Unexpected revert reason, want %q, got %q", want, got)
+			}
 		})
 	}
 }
 
+// encodeRevertReason produces the ABI encoding of a call to Error(string)
+// with the given reason, as produced by Solidity's revert statement.
+func encodeRevertReason(reason string) []byte {
+	res := []byte{0x08, 0xc3, 0x79, 0xa0}
+	res = append(res, common.LeftPadBytes([]byte{0x20}, 32)...)
+	res = append(res, common.LeftPadBytes([]byte{byte(len(reason))}, 32)...)
+	return append(res, common.RightPadBytes([]byte(reason), 32)...)
+}
+
 func TestCall_TransferValueInCall(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	context := tosca.NewMockTransactionContext(ctrl)
 	interpreter := tosca.NewMockInterpreter(ctrl)
 	runContext := runContext{
-		context,
-		interpreter,
-		tosca.BlockParameters{},
-		tosca.TransactionParameters{},
-		0,
-		false,
+		TransactionContext: context,
+		interpreter:        interpreter,
 	}
 
 	params := tosca.CallParameters{
@@ -131,17 +153,52 @@ func TestCall_TransferValueInCall(t *testing.T) {
 	}
 }
 
+func TestCall_TracerIsInformedAboutCallFrames(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	context := tosca.NewMockTransactionContext(ctrl)
+	interpreter := tosca.NewMockInterpreter(ctrl)
+	tracer := tosca.NewMockTracer(ctrl)
+	runContext := runContext{
+		TransactionContext: context,
+		interpreter:        interpreter,
+		tracer:             tracer,
+	}
+
+	params := tosca.CallParameters{
+		Sender:    tosca.Address{1},
+		Recipient: tosca.Address{2},
+		Gas:       1000,
+	}
+
+	context.EXPECT().GetCodeHash(params.Recipient).Return(tosca.Hash{})
+	context.EXPECT().GetCode(params.Recipient).Return([]byte{})
+	context.EXPECT().AccountExists(params.Recipient).Return(true)
+	context.EXPECT().CreateSnapshot()
+
+	gomock.InOrder(
+		tracer.EXPECT().OnEnter(0, tosca.Call, params),
+		interpreter.EXPECT().Run(gomock.Any()).DoAndReturn(func(p tosca.Parameters) (tosca.Result, error) {
+			if p.Tracer != tracer {
+				t.Errorf("tracer was not forwarded to the interpreter")
+			}
+			return tosca.Result{Success: true, GasLeft: 10}, nil
+		}),
+		tracer.EXPECT().OnExit(0, tosca.CallResult{Success: true, GasLeft: 10}, nil),
+	)
+
+	_, err := runContext.Call(tosca.Call, params)
+	if err != nil {
+		t.Errorf("Call returned an unexpected error: %v", err)
+	}
+}
+
 func TestTransferValue_InCallRestoreFailed(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	context := tosca.NewMockTransactionContext(ctrl)
 	interpreter := tosca.NewMockInterpreter(ctrl)
 	runContext := runContext{
-		context,
-		interpreter,
-		tosca.BlockParameters{},
-		tosca.TransactionParameters{},
-		0,
-		false,
+		TransactionContext: context,
+		interpreter:        interpreter,
 	}
 
 	params := tosca.CallParameters{
@@ -304,3 +361,80 @@ func TestCreateAddress(t *testing.T) {
 		})
 	}
 }
+
+func TestRunContext_GetBlockHashPrefersHistoryStorageSincePrague(t *testing.T) {
+	tests := map[string]struct {
+		revision tosca.Revision
+		recorded tosca.Word
+		want     tosca.Hash
+	}{
+		"before Prague": {
+			revision: tosca.R13_Cancun,
+			recorded: tosca.Word{0x02},
+			want:     tosca.Hash{0x01},
+		},
+		"since Prague": {
+			revision: tosca.R14_Prague,
+			recorded: tosca.Word{0x02},
+			want:     tosca.Hash{0x02},
+		},
+		"since Prague without recorded hash": {
+			revision: tosca.R14_Prague,
+			want:     tosca.Hash{0x01},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			context := tosca.NewInMemoryTransactionContext(test.revision, nil)
+			context.SetBlockHash(41, tosca.Hash{0x01})
+			context.SetStorage(tosca.HistoryStorageAddress, tosca.Key{31: 41}, test.recorded)
+
+			runContext := runContext{
+				TransactionContext: context,
+				blockParameters: tosca.BlockParameters{
+					BlockNumber: 42,
+					Revision:    test.revision,
+				},
+			}
+			if want, got := test.want, runContext.GetBlockHash(41); want != got {
+				t.Errorf("unexpected block hash, wanted %v, got %v", want, got)
+			}
+		})
+	}
+}
+
+func TestRunContext_ContractCreationIsReportedToContext(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	interpreter := tosca.NewMockInterpreter(ctrl)
+
+	// Since Cancun, contracts self-destructing in the transaction creating
+	// them are deleted. This requires the context to know about the creation.
+	sender := tosca.Address{1}
+	context := tosca.NewInMemoryTransactionContext(tosca.R13_Cancun, map[tosca.Address]tosca.Account{
+		sender: {Nonce: 1},
+	})
+	interpreter.EXPECT().Run(gomock.Any()).DoAndReturn(func(params tosca.Parameters) (tosca.Result, error) {
+		params.Context.SelfDestruct(params.Recipient, sender)
+		return tosca.Result{Success: true, GasLeft: params.Gas}, nil
+	})
+
+	runContext := runContext{
+		TransactionContext: context,
+		interpreter:        interpreter,
+		blockParameters:    tosca.BlockParameters{Revision: tosca.R13_Cancun},
+	}
+	result, err := runContext.Call(tosca.Create, tosca.CallParameters{
+		Sender: sender,
+		Input:  []byte{byte(0)},
+		Gas:    100_000,
+	})
+	if err != nil || !result.Success {
+		t.Fatalf("failed to create contract, result %v, error %v", result, err)
+	}
+	context.EndTransaction()
+
+	if context.AccountExists(result.CreatedAddress) {
+		t.Errorf("self-destructed contract created in the same transaction was not deleted")
+	}
+}
@@ -0,0 +1,148 @@
+// Copyright (c) 2024 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package floria
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/Fantom-foundation/Tosca/go/tosca"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+const (
+	// PerEmptyAccountCost is the intrinsic gas charged for each authorization
+	// of a set-code transaction (EIP-7702).
+	PerEmptyAccountCost = 25_000
+	// PerAuthBaseCost is the cost of an authorization of an account already
+	// existing. The difference to PerEmptyAccountCost is refunded.
+	PerAuthBaseCost = 12_500
+
+	// setCodeAuthorizationMagic prefixes the signed messages of authorizations.
+	setCodeAuthorizationMagic = 0x05
+)
+
+// checkAuthorizations validates the authorization list of the given
+// transaction according to the rules introduced by EIP-7702. Individual
+// authorizations are validated when being applied.
+func checkAuthorizations(blockParameters tosca.BlockParameters, transaction tosca.Transaction) error {
+	if transaction.AuthorizationList == nil {
+		return nil
+	}
+	if blockParameters.Revision < tosca.R14_Prague {
+		return fmt.Errorf("%w: set code transaction before Prague", tosca.ErrTxTypeNotSupported)
+	}
+	if transaction.Recipient == nil {
+		return tosca.ErrSetCodeTxCreate
+	}
+	if len(transaction.AuthorizationList) == 0 {
+		return tosca.ErrEmptyAuthList
+	}
+	return nil
+}
+
+// applyAuthorizations installs the delegations of all valid authorizations in
+// the given list in order. Invalid authorizations are skipped. The result is
+// the gas to be refunded for authorizations of already existing accounts.
+func applyAuthorizations(
+	context tosca.TransactionContext,
+	chainId tosca.Word,
+	authorizations []tosca.SetCodeAuthorization,
+) tosca.Gas {
+	refund := tosca.Gas(0)
+	for _, authorization := range authorizations {
+		authority, ok := validateAuthorization(context, chainId, authorization)
+		if !ok {
+			continue
+		}
+		if context.AccountExists(authority) {
+			refund += PerEmptyAccountCost - PerAuthBaseCost
+		}
+		if authorization.Address == (tosca.Address{}) {
+			context.SetCode(authority, nil)
+		} else {
+			context.SetCode(authority, tosca.NewDelegationDesignator(authorization.Address))
+		}
+		context.SetNonce(authority, authorization.Nonce+1)
+	}
+	return refund
+}
+
+// validateAuthorization checks whether the given authorization may be applied
+// and returns the authority granting it. As required by EIP-7702, the
+// authority is added to the accessed accounts even if its state does not
+// permit the delegation.
+func validateAuthorization(
+	context tosca.TransactionContext,
+	chainId tosca.Word,
+	authorization tosca.SetCodeAuthorization,
+) (tosca.Address, bool) {
+	if authorization.ChainID != (tosca.Word{}) && authorization.ChainID != chainId {
+		return tosca.Address{}, false
+	}
+	if authorization.Nonce == math.MaxUint64 {
+		return tosca.Address{}, false
+	}
+	authority, err := recoverAuthority(authorization)
+	if err != nil {
+		return tosca.Address{}, false
+	}
+	context.AccessAccount(authority)
+	if code := context.GetCode(authority); len(code) != 0 {
+		if _, delegated := tosca.GetDelegationTarget(code); !delegated {
+			return tosca.Address{}, false
+		}
+	}
+	if context.GetNonce(authority) != authorization.Nonce {
+		return tosca.Address{}, false
+	}
+	return authority, true
+}
+
+// recoverAuthority recovers the address of the account signing the given
+// authorization.
+func recoverAuthority(authorization tosca.SetCodeAuthorization) (tosca.Address, error) {
+	r := new(big.Int).SetBytes(authorization.R[:])
+	s := new(big.Int).SetBytes(authorization.S[:])
+	if authorization.V > 1 || !crypto.ValidateSignatureValues(authorization.V, r, s, true) {
+		return tosca.Address{}, fmt.Errorf("invalid signature values")
+	}
+	hash, err := getAuthorizationHash(authorization)
+	if err != nil {
+		return tosca.Address{}, err
+	}
+	signature := make([]byte, crypto.SignatureLength)
+	copy(signature[0:32], authorization.R[:])
+	copy(signature[32:64], authorization.S[:])
+	signature[64] = authorization.V
+	publicKey, err := crypto.Ecrecover(hash, signature)
+	if err != nil {
+		return tosca.Address{}, err
+	}
+	return tosca.Address(crypto.Keccak256(publicKey[1:])[12:]), nil
+}
+
+// getAuthorizationHash computes the hash of the message signed by the
+// authority of the given authorization.
+func getAuthorizationHash(authorization tosca.SetCodeAuthorization) ([]byte, error) {
+	message, err := rlp.EncodeToBytes([]any{
+		new(big.Int).SetBytes(authorization.ChainID[:]),
+		common.Address(authorization.Address),
+		authorization.Nonce,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return crypto.Keccak256(append([]byte{setCodeAuthorizationMagic}, message...)), nil
+}
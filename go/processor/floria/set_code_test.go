@@ -0,0 +1,419 @@
+// Copyright (c) 2024 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package floria
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"errors"
+	"testing"
+
+	"github.com/Fantom-foundation/Tosca/go/tosca"
+	"github.com/ethereum/go-ethereum/crypto"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCheckAuthorizations_AcceptsValidAuthorizationLists(t *testing.T) {
+	block := tosca.BlockParameters{Revision: tosca.R14_Prague}
+	tests := map[string]tosca.Transaction{
+		"no list":   {Recipient: &tosca.Address{1}},
+		"some list": {Recipient: &tosca.Address{1}, AuthorizationList: []tosca.SetCodeAuthorization{{}}},
+	}
+	for name, transaction := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := checkAuthorizations(block, transaction); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestCheckAuthorizations_RejectsInvalidAuthorizationLists(t *testing.T) {
+	authorizations := []tosca.SetCodeAuthorization{{}}
+	tests := map[string]struct {
+		revision    tosca.Revision
+		transaction tosca.Transaction
+		want        error
+	}{
+		"before Prague": {
+			revision:    tosca.R13_Cancun,
+			transaction: tosca.Transaction{Recipient: &tosca.Address{1}, AuthorizationList: authorizations},
+			want:        tosca.ErrTxTypeNotSupported,
+		},
+		"create": {
+			revision:    tosca.R14_Prague,
+			transaction: tosca.Transaction{AuthorizationList: authorizations},
+			want:        tosca.ErrSetCodeTxCreate,
+		},
+		"empty list": {
+			revision:    tosca.R14_Prague,
+			transaction: tosca.Transaction{Recipient: &tosca.Address{1}, AuthorizationList: []tosca.SetCodeAuthorization{}},
+			want:        tosca.ErrEmptyAuthList,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			block := tosca.BlockParameters{Revision: test.revision}
+			if err := checkAuthorizations(block, test.transaction); !errors.Is(err, test.want) {
+				t.Errorf("unexpected error, wanted %v, got %v", test.want, err)
+			}
+		})
+	}
+}
+
+func TestRecoverAuthority_RecoversSigner(t *testing.T) {
+	key, authority := newTestKey(t)
+	authorization := signAuthorization(t, key, tosca.SetCodeAuthorization{
+		ChainID: tosca.Word{31: 1},
+		Address: tosca.Address{2},
+		Nonce:   3,
+	})
+	got, err := recoverAuthority(authorization)
+	if err != nil {
+		t.Fatalf("failed to recover authority: %v", err)
+	}
+	if authority != got {
+		t.Errorf("unexpected authority, wanted %v, got %v", authority, got)
+	}
+
+	// Modifying any signed field changes the recovered address.
+	authorization.Nonce++
+	if got, err := recoverAuthority(authorization); err == nil && got == authority {
+		t.Errorf("modified authorization recovered to original authority")
+	}
+}
+
+func TestRecoverAuthority_RejectsInvalidSignatureValues(t *testing.T) {
+	key, _ := newTestKey(t)
+	valid := signAuthorization(t, key, tosca.SetCodeAuthorization{Address: tosca.Address{2}})
+
+	// The order of the secp256k1 curve, used to produce a too large S value.
+	curveOrder := crypto.S256().Params().N
+
+	tests := map[string]func(*tosca.SetCodeAuthorization){
+		"invalid V":  func(a *tosca.SetCodeAuthorization) { a.V = 27 },
+		"zero R":     func(a *tosca.SetCodeAuthorization) { a.R = tosca.Word{} },
+		"zero S":     func(a *tosca.SetCodeAuthorization) { a.S = tosca.Word{} },
+		"too high S": func(a *tosca.SetCodeAuthorization) { curveOrder.FillBytes(a.S[:]) },
+	}
+	for name, modify := range tests {
+		t.Run(name, func(t *testing.T) {
+			authorization := valid
+			modify(&authorization)
+			if _, err := recoverAuthority(authorization); err == nil {
+				t.Errorf("invalid signature was accepted")
+			}
+		})
+	}
+}
+
+func TestApplyAuthorizations_InstallsValidDelegations(t *testing.T) {
+	chainId := tosca.Word{31: 1}
+	target := tosca.Address{0x42}
+	delegated := &tosca.Account{Nonce: 5, Code: tosca.NewDelegationDesignator(tosca.Address{0x43})}
+	key, authority := newTestKey(t)
+
+	tests := map[string]struct {
+		account    *tosca.Account
+		chainId    tosca.Word
+		address    tosca.Address
+		wantCode   tosca.Code
+		wantRefund tosca.Gas
+	}{
+		"new account": {
+			chainId:  chainId,
+			address:  target,
+			wantCode: tosca.NewDelegationDesignator(target),
+		},
+		"existing account": {
+			account:    &tosca.Account{Nonce: 5, Balance: tosca.NewValue(1)},
+			chainId:    chainId,
+			address:    target,
+			wantCode:   tosca.NewDelegationDesignator(target),
+			wantRefund: PerEmptyAccountCost - PerAuthBaseCost,
+		},
+		"any chain": {
+			address:  target,
+			wantCode: tosca.NewDelegationDesignator(target),
+		},
+		"update of delegation": {
+			account:    delegated,
+			chainId:    chainId,
+			address:    target,
+			wantCode:   tosca.NewDelegationDesignator(target),
+			wantRefund: PerEmptyAccountCost - PerAuthBaseCost,
+		},
+		"removal of delegation": {
+			account:    delegated,
+			chainId:    chainId,
+			address:    tosca.Address{},
+			wantCode:   nil,
+			wantRefund: PerEmptyAccountCost - PerAuthBaseCost,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			accounts := map[tosca.Address]tosca.Account{}
+			nonce := uint64(0)
+			if test.account != nil {
+				accounts[authority] = *test.account
+				nonce = test.account.Nonce
+			}
+			context := tosca.NewInMemoryTransactionContext(tosca.R14_Prague, accounts)
+
+			authorization := signAuthorization(t, key, tosca.SetCodeAuthorization{
+				ChainID: test.chainId,
+				Address: test.address,
+				Nonce:   nonce,
+			})
+
+			refund := applyAuthorizations(context, chainId, []tosca.SetCodeAuthorization{authorization})
+			if want, got := test.wantRefund, refund; want != got {
+				t.Errorf("unexpected refund, wanted %d, got %d", want, got)
+			}
+			if want, got := test.wantCode, context.GetCode(authority); !bytes.Equal(want, got) {
+				t.Errorf("unexpected code, wanted %x, got %x", want, got)
+			}
+			if want, got := nonce+1, context.GetNonce(authority); want != got {
+				t.Errorf("unexpected nonce, wanted %d, got %d", want, got)
+			}
+		})
+	}
+}
+
+func TestApplyAuthorizations_SkipsInvalidAuthorizations(t *testing.T) {
+	chainId := tosca.Word{31: 1}
+	key, authority := newTestKey(t)
+
+	tests := map[string]struct {
+		account       tosca.Account
+		authorization tosca.SetCodeAuthorization
+	}{
+		"other chain": {
+			authorization: tosca.SetCodeAuthorization{ChainID: tosca.Word{31: 2}, Address: tosca.Address{1}},
+		},
+		"wrong nonce": {
+			authorization: tosca.SetCodeAuthorization{ChainID: chainId, Address: tosca.Address{1}, Nonce: 1},
+		},
+		"maximum nonce": {
+			account:       tosca.Account{Nonce: 1<<64 - 1},
+			authorization: tosca.SetCodeAuthorization{ChainID: chainId, Address: tosca.Address{1}, Nonce: 1<<64 - 1},
+		},
+		"authority with code": {
+			account:       tosca.Account{Code: tosca.Code{0x00}},
+			authorization: tosca.SetCodeAuthorization{ChainID: chainId, Address: tosca.Address{1}},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			context := tosca.NewInMemoryTransactionContext(tosca.R14_Prague, map[tosca.Address]tosca.Account{
+				authority: test.account,
+			})
+			authorization := signAuthorization(t, key, test.authorization)
+			if refund := applyAuthorizations(context, chainId, []tosca.SetCodeAuthorization{authorization}); refund != 0 {
+				t.Errorf("unexpected refund %d", refund)
+			}
+			if want, got := test.account.Code, context.GetCode(authority); !bytes.Equal(want, got) {
+				t.Errorf("code was modified, wanted %x, got %x", want, got)
+			}
+			if want, got := test.account.Nonce, context.GetNonce(authority); want != got {
+				t.Errorf("nonce was modified, wanted %d, got %d", want, got)
+			}
+		})
+	}
+}
+
+func TestApplyAuthorizations_MarksAuthorityAsAccessed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	context := tosca.NewMockTransactionContext(ctrl)
+
+	key, authority := newTestKey(t)
+	authorization := signAuthorization(t, key, tosca.SetCodeAuthorization{Address: tosca.Address{1}, Nonce: 1})
+
+	// The authorization is rejected due to the wrong nonce, after the authority
+	// got accessed.
+	gomock.InOrder(
+		context.EXPECT().AccessAccount(authority),
+		context.EXPECT().GetCode(authority),
+		context.EXPECT().GetNonce(authority).Return(uint64(0)),
+	)
+	applyAuthorizations(context, tosca.Word{}, []tosca.SetCodeAuthorization{authorization})
+}
+
+func TestProcessor_SetCodeTransactionsExecuteDelegatedCode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	interpreter := tosca.NewMockInterpreter(ctrl)
+
+	key, sender := newTestKey(t)
+	target := tosca.Address{0x42}
+	targetCode := tosca.Code{byte(0)}
+	context := tosca.NewInMemoryTransactionContext(tosca.R14_Prague, map[tosca.Address]tosca.Account{
+		sender: {Balance: tosca.NewValue(1_000_000), Nonce: 3},
+		target: {Code: targetCode},
+	})
+
+	// The sender delegates to the target and calls itself in the same
+	// transaction. Since the nonce of the sender is incremented first, the
+	// authorization has to use the incremented nonce.
+	authorization := signAuthorization(t, key, tosca.SetCodeAuthorization{Address: target, Nonce: 4})
+	transaction := tosca.Transaction{
+		Sender:            sender,
+		Recipient:         &sender,
+		Nonce:             3,
+		GasLimit:          TxGas + PerEmptyAccountCost,
+		GasPrice:          tosca.NewValue(1),
+		AuthorizationList: []tosca.SetCodeAuthorization{authorization},
+	}
+
+	interpreter.EXPECT().Run(gomock.Any()).DoAndReturn(func(params tosca.Parameters) (tosca.Result, error) {
+		if want, got := targetCode, params.Code; !bytes.Equal(want, got) {
+			t.Errorf("unexpected code, wanted %x, got %x", want, got)
+		}
+		if want, got := sender, params.Recipient; want != got {
+			t.Errorf("unexpected recipient, wanted %v, got %v", want, got)
+		}
+		return tosca.Result{Success: true, GasLeft: params.Gas}, nil
+	})
+
+	processor, err := NewProcessor(interpreter, Config{WithoutStateContracts: true})
+	if err != nil {
+		t.Fatalf("failed to create processor: %v", err)
+	}
+	block := tosca.BlockParameters{Revision: tosca.R14_Prague}
+	receipt, err := processor.Run(block, transaction, context)
+	if err != nil || !receipt.Success {
+		t.Fatalf("failed to run transaction, receipt %v, error %v", receipt, err)
+	}
+	// The sender exists, so the authorization is partially refunded.
+	gasUsed := tosca.Gas(TxGas + PerEmptyAccountCost)
+	if want, got := gasUsed-min(gasUsed/5, PerEmptyAccountCost-PerAuthBaseCost), receipt.GasUsed; want != got {
+		t.Errorf("unexpected gas used, wanted %d, got %d", want, got)
+	}
+	if want, got := tosca.NewDelegationDesignator(target), context.GetCode(sender); !bytes.Equal(want, got) {
+		t.Errorf("unexpected code of sender, wanted %x, got %x", want, got)
+	}
+	if want, got := uint64(5), context.GetNonce(sender); want != got {
+		t.Errorf("unexpected nonce of sender, wanted %d, got %d", want, got)
+	}
+}
+
+func TestProcessor_SelfDestructOfDelegatedAccountDoesNotDeleteIt(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	interpreter := tosca.NewMockInterpreter(ctrl)
+
+	// The sender has not sent any transaction before, delegates to the
+	// target, and calls itself. Neither the increment of its nonce nor the
+	// delegation make it an account created in this transaction.
+	key, sender := newTestKey(t)
+	target := tosca.Address{0x42}
+	context := tosca.NewInMemoryTransactionContext(tosca.R14_Prague, map[tosca.Address]tosca.Account{
+		sender: {Balance: tosca.NewValue(1_000_000)},
+		target: {Code: tosca.Code{byte(0)}},
+	})
+	authorization := signAuthorization(t, key, tosca.SetCodeAuthorization{Address: target, Nonce: 1})
+	transaction := tosca.Transaction{
+		Sender:            sender,
+		Recipient:         &sender,
+		GasLimit:          TxGas + PerEmptyAccountCost,
+		GasPrice:          tosca.NewValue(1),
+		AuthorizationList: []tosca.SetCodeAuthorization{authorization},
+	}
+
+	interpreter.EXPECT().Run(gomock.Any()).DoAndReturn(func(params tosca.Parameters) (tosca.Result, error) {
+		params.Context.SelfDestruct(params.Recipient, tosca.Address{0x43})
+		return tosca.Result{Success: true, GasLeft: params.Gas}, nil
+	})
+
+	processor, err := NewProcessor(interpreter, Config{WithoutStateContracts: true})
+	if err != nil {
+		t.Fatalf("failed to create processor: %v", err)
+	}
+	block := tosca.BlockParameters{Revision: tosca.R14_Prague}
+	receipt, err := processor.Run(block, transaction, context)
+	if err != nil || !receipt.Success {
+		t.Fatalf("failed to run transaction, receipt %v, error %v", receipt, err)
+	}
+	context.EndTransaction()
+
+	if !context.AccountExists(sender) {
+		t.Fatalf("self-destructed delegated account was deleted")
+	}
+	if want, got := tosca.NewDelegationDesignator(target), context.GetCode(sender); !bytes.Equal(want, got) {
+		t.Errorf("unexpected code of sender, wanted %x, got %x", want, got)
+	}
+}
+
+func TestRunContext_FollowsDelegationsSincePrague(t *testing.T) {
+	for _, revision := range []tosca.Revision{tosca.R13_Cancun, tosca.R14_Prague} {
+		for _, kind := range []tosca.CallKind{tosca.Call, tosca.StaticCall, tosca.CallCode, tosca.DelegateCall} {
+			t.Run(revision.String()+"/"+kind.String(), func(t *testing.T) {
+				ctrl := gomock.NewController(t)
+				interpreter := tosca.NewMockInterpreter(ctrl)
+
+				delegating := tosca.Address{1}
+				target := tosca.Address{2}
+				designator := tosca.NewDelegationDesignator(target)
+				targetCode := tosca.Code{byte(0), byte(0)}
+				context := tosca.NewInMemoryTransactionContext(revision, map[tosca.Address]tosca.Account{
+					delegating: {Code: designator},
+					target:     {Code: targetCode},
+				})
+
+				want := targetCode
+				if revision < tosca.R14_Prague {
+					want = designator
+				}
+				interpreter.EXPECT().Run(gomock.Any()).DoAndReturn(func(params tosca.Parameters) (tosca.Result, error) {
+					if !bytes.Equal(want, params.Code) {
+						t.Errorf("unexpected code, wanted %x, got %x", want, params.Code)
+					}
+					return tosca.Result{Success: true}, nil
+				})
+
+				runContext := runContext{
+					TransactionContext: context,
+					interpreter:        interpreter,
+					blockParameters:    tosca.BlockParameters{Revision: revision},
+					config:             Config{WithoutStateContracts: true},
+				}
+				parameters := tosca.CallParameters{Recipient: delegating, CodeAddress: delegating}
+				if _, err := runContext.Call(kind, parameters); err != nil {
+					t.Fatalf("call failed: %v", err)
+				}
+			})
+		}
+	}
+}
+
+func newTestKey(t *testing.T) (*ecdsa.PrivateKey, tosca.Address) {
+	t.Helper()
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	return key, tosca.Address(crypto.PubkeyToAddress(key.PublicKey))
+}
+
+func signAuthorization(t *testing.T, key *ecdsa.PrivateKey, authorization tosca.SetCodeAuthorization) tosca.SetCodeAuthorization {
+	t.Helper()
+	hash, err := getAuthorizationHash(authorization)
+	if err != nil {
+		t.Fatalf("failed to hash authorization: %v", err)
+	}
+	signature, err := crypto.Sign(hash, key)
+	if err != nil {
+		t.Fatalf("failed to sign authorization: %v", err)
+	}
+	copy(authorization.R[:], signature[0:32])
+	copy(authorization.S[:], signature[32:64])
+	authorization.V = signature[64]
+	return authorization
+}
@@ -0,0 +1,58 @@
+// Copyright (c) 2024 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package floria
+
+import (
+	"errors"
+
+	"github.com/Fantom-foundation/Tosca/go/processor/system_contract"
+	"github.com/Fantom-foundation/Tosca/go/tosca"
+)
+
+// getSystemContract returns the system contract enabled by the configuration
+// of this run context at the given address, if there is any. System contracts
+// are kept apart from the registry of precompiled contracts since, unlike
+// those, they are only reachable through plain calls, depend on the origin of
+// the transaction, and are not added to the access list of transactions.
+func (r runContext) getSystemContract(address tosca.Address) (*system_contract.Contract, bool) {
+	contracts := system_contract.Enabled(r.config.SystemContracts, r.config.WithoutStateContracts)
+	for _, contract := range contracts {
+		if contract.Address() == address {
+			return contract, true
+		}
+	}
+	return nil, false
+}
+
+// runSystemContract runs the given system contract for the given call of a
+// transaction sent by the given origin.
+func runSystemContract(
+	state tosca.WorldState,
+	contract *system_contract.Contract,
+	origin tosca.Address,
+	parameters tosca.CallParameters,
+) tosca.CallResult {
+	output, gasLeft, err := contract.Run(state, origin, parameters.Sender, parameters.Input, parameters.Gas)
+	switch {
+	case err == nil:
+		return tosca.CallResult{
+			Success: true,
+			Output:  output,
+			GasLeft: gasLeft,
+		}
+	case errors.Is(err, system_contract.ErrExecutionReverted):
+		return tosca.CallResult{FailureCause: tosca.FailureRevert}
+	case errors.Is(err, system_contract.ErrOutOfGas):
+		return tosca.CallResult{FailureCause: tosca.FailureOutOfGas}
+	default:
+		return tosca.CallResult{FailureCause: tosca.FailurePrecompile}
+	}
+}
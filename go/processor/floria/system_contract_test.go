@@ -0,0 +1,102 @@
+// Copyright (c) 2024 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package floria
+
+import (
+	"testing"
+
+	"github.com/Fantom-foundation/Tosca/go/processor/system_contract"
+	"github.com/Fantom-foundation/Tosca/go/tosca"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSystemContract_EvmWriterIsEnabledByDefault(t *testing.T) {
+	evmWriter := system_contract.EvmWriterAddress()
+	custom := system_contract.MustNew(system_contract.Definition{
+		Address: tosca.Address{1},
+		ABI:     "[]",
+	})
+
+	tests := map[string]struct {
+		config Config
+		want   []tosca.Address
+	}{
+		"default": {
+			config: Config{},
+			want:   []tosca.Address{evmWriter},
+		},
+		"configured": {
+			config: Config{SystemContracts: []*system_contract.Contract{custom}},
+			want:   []tosca.Address{{1}},
+		},
+		"disabled": {
+			config: Config{
+				SystemContracts:       []*system_contract.Contract{custom},
+				WithoutStateContracts: true,
+			},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			context := runContext{config: test.config}
+			for _, address := range []tosca.Address{evmWriter, {1}} {
+				wanted := false
+				for _, want := range test.want {
+					wanted = wanted || want == address
+				}
+				if _, found := context.getSystemContract(address); found != wanted {
+					t.Errorf("unexpected availability of system contract at %v, want %t, got %t", address, wanted, found)
+				}
+			}
+		})
+	}
+}
+
+func TestSystemContract_FailuresAreReportedAsUnsuccessfulCalls(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	state := tosca.NewMockWorldState(ctrl)
+	state.EXPECT().SetNonce(tosca.Address{19: 2}, uint64(6))
+	state.EXPECT().GetNonce(tosca.Address{19: 2}).Return(uint64(1))
+
+	input := append([]byte{0x79, 0xbe, 0xad, 0x38}, make([]byte, 64)...)
+	input[4+31] = 2 // < the account to update
+	input[4+63] = 5 // < the nonce increment
+	tests := map[string]struct {
+		sender tosca.Address
+		gas    tosca.Gas
+		want   tosca.CallResult
+	}{
+		"success": {
+			sender: system_contract.DriverAddress(),
+			gas:    10_000,
+			want:   tosca.CallResult{Success: true, GasLeft: 1_000},
+		},
+		"reverted": {
+			sender: tosca.Address{1},
+			gas:    10_000,
+			want:   tosca.CallResult{FailureCause: tosca.FailureRevert},
+		},
+		"out of gas": {
+			sender: system_contract.DriverAddress(),
+			gas:    10,
+			want:   tosca.CallResult{FailureCause: tosca.FailureOutOfGas},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			params := tosca.CallParameters{Sender: test.sender, Input: input, Gas: test.gas}
+			got := runSystemContract(state, system_contract.EvmWriter(), tosca.Address{1}, params)
+			if test.want.Success != got.Success || test.want.GasLeft != got.GasLeft || test.want.FailureCause != got.FailureCause {
+				t.Errorf("unexpected result, want %+v, got %+v", test.want, got)
+			}
+		})
+	}
+}
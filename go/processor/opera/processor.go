@@ -16,19 +16,17 @@ package geth
 // here to provide a reference implementation for the Tosca EVM implementation.
 
 import (
-	"bytes"
 	"errors"
 	"fmt"
 	"math"
 	"math/big"
-	"strings"
+	"slices"
 
 	"github.com/Fantom-foundation/Tosca/go/geth_adapter"
 	geth_interpreter "github.com/Fantom-foundation/Tosca/go/interpreter/geth"
+	"github.com/Fantom-foundation/Tosca/go/processor/system_contract"
 	"github.com/Fantom-foundation/Tosca/go/tosca"
-	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/core/tracing"
 	"github.com/ethereum/go-ethereum/core/types"
 	geth "github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/params"
@@ -37,45 +35,62 @@ import (
 )
 
 func init() {
-	tosca.RegisterProcessorFactory("geth", newProcessor)
-	tosca.RegisterProcessorFactory("opera", newProcessor)
+	// Code delegations of Prague are not supported, transactions with
+	// authorizations are rejected.
+	info := tosca.ProcessorInfo{
+		NewestSupportedRevision: tosca.R13_Cancun,
+		Tracing:                 true,
+	}
+	tosca.MustRegisterProcessorFactory("geth", newProcessor, info)
+	tosca.MustRegisterProcessorFactory("opera", newProcessor, info)
+}
+
+// Config provides the user-definable options of the geth/opera processor.
+type Config struct {
+	// SystemContracts, if not nil, lists the system contracts available to
+	// transactions. By default, Sonic's EvmWriter contract is available.
+	SystemContracts []*system_contract.Contract
+	// WithoutStateContracts disables all system contracts, including the
+	// Sonic specific EvmWriter contract enabled by default.
+	WithoutStateContracts bool
+	// SystemTransactionGasAllowance, if not zero, replaces the gas limit of
+	// system transactions. By default, their gas limit is used.
+	SystemTransactionGasAllowance tosca.Gas
 }
 
 // newProcessor is a factory function for the geth/opera processor implemented in this file.
 // By including this package, it gets registered in the global processor registry.
-func newProcessor(interpreter tosca.Interpreter) tosca.Processor {
-	return &processor{
-		interpreter: geth_adapter.NewGethInterpreterFactory(interpreter),
+// The processor accepts nil, a Config, or a *Config as configuration.
+func newProcessor(interpreter tosca.Interpreter, config any) (tosca.Processor, error) {
+	var cfg Config
+	switch config := config.(type) {
+	case nil:
+	case Config:
+		cfg = config
+	case *Config:
+		if config != nil {
+			cfg = *config
+		}
+	default:
+		return nil, fmt.Errorf("unsupported configuration for opera processor: %v", config)
 	}
+	if interpreter == nil {
+		return nil, fmt.Errorf("invalid configuration: no interpreter provided")
+	}
+	if cfg.SystemTransactionGasAllowance < 0 {
+		return nil, fmt.Errorf("invalid configuration: negative system transaction gas allowance %d", cfg.SystemTransactionGasAllowance)
+	}
+	return &processor{
+		toscaInterpreter: interpreter,
+		interpreter:      geth_adapter.NewGethInterpreterFactory(interpreter),
+		config:           cfg,
+	}, nil
 }
 
-var (
-	// errNonceTooLow is returned if the nonce of a transaction is lower than the
-	// one present in the local chain.
-	errNonceTooLow = errors.New("nonce too low")
-
-	// errNonceTooHigh is returned if the nonce of a transaction is higher than the
-	// next one expected based on the local chain.
-	errNonceTooHigh = errors.New("nonce too high")
-
-	// errInsufficientFunds is returned if the total cost of executing a transaction
-	// is higher than the balance of the user's account.
-	errInsufficientFunds = errors.New("insufficient funds for gas * price + value")
-
-	// ErrGasLimitReached
-	// errGasUintOverflow is returned when calculating gas usage.
-	errGasUintOverflow = errors.New("gas uint64 overflow")
-
-	// errIntrinsicGas is returned if the transaction is specified to use less gas
-	// than required to start the invocation.
-	errIntrinsicGas = errors.New("intrinsic gas too low")
-
-	// errSenderNoEOA is returned if the sender of a transaction is a contract.
-	errSenderNoEOA = errors.New("sender not an eoa")
-)
-
 type processor struct {
-	interpreter geth.InterpreterFactory
+	toscaInterpreter tosca.Interpreter
+	interpreter      geth.InterpreterFactory
+	config           Config
 }
 
 func (p *processor) Run(
@@ -83,11 +98,54 @@ func (p *processor) Run(
 	transaction tosca.Transaction,
 	context tosca.TransactionContext,
 ) (tosca.Receipt, error) {
+	return p.run(blockParams, transaction, context, false)
+}
+
+// Simulate runs the given transaction without checking the nonce, the sender,
+// and the gas fee caps of the transaction, and without buying gas.
+func (p *processor) Simulate(
+	blockParams tosca.BlockParameters,
+	transaction tosca.Transaction,
+	context tosca.TransactionContext,
+) (tosca.Receipt, error) {
+	return p.run(blockParams, transaction, context, true)
+}
+
+func (p *processor) run(
+	blockParams tosca.BlockParameters,
+	transaction tosca.Transaction,
+	context tosca.TransactionContext,
+	simulate bool,
+) (tosca.Receipt, error) {
 
 	// --- setup ---
 
-	// Hashing function used in the context for BLOCKHASH instruction
+	// The geth EVM used by this processor does not support code delegations.
+	if transaction.AuthorizationList != nil {
+		return tosca.Receipt{}, fmt.Errorf("%w: set code transactions are not supported", tosca.ErrTxTypeNotSupported)
+	}
+
+	tracer := tosca.GetTracer(context)
+	context = tosca.NewTracedTransactionContext(context, tracer)
+
+	// System transactions are issued by the chain and not paid for by the
+	// sender. Like simulated transactions, they are exempt from fee, nonce,
+	// and sender checks.
+	system := isSystemTransaction(transaction)
+	if system && p.config.SystemTransactionGasAllowance != 0 {
+		transaction.GasLimit = p.config.SystemTransactionGasAllowance
+	}
+	paid := !simulate && !system
+
+	// Hashing function used in the context for BLOCKHASH instruction. Since
+	// Prague (EIP-2935), hashes recorded by the history storage contract
+	// take precedence over the hashes provided by the context.
 	getHash := func(num uint64) common.Hash {
+		if blockParams.Revision >= tosca.R14_Prague {
+			if hash, found := tosca.GetHistoricalBlockHash(context, blockParams.BlockNumber, int64(num)); found {
+				return common.Hash(hash)
+			}
+		}
 		return common.Hash(context.GetBlockHash(int64(num)))
 	}
 
@@ -118,18 +176,31 @@ func (p *processor) Run(
 		CanTransfer: canTransferFunc,
 	}
 
+	gasPrice := transaction.GetEffectiveGasPrice(blockParams.BaseFee)
+	if paid {
+		var err error
+		gasPrice, err = getEffectiveGasPrice(blockParams, transaction)
+		if err != nil {
+			return tosca.Receipt{}, err
+		}
+	}
+
 	// Create empty tx context
 	txCtx := geth.TxContext{
 		Origin:   common.Address(transaction.Sender),
-		GasPrice: new(big.Int).SetBytes(transaction.GasPrice[:]),
+		GasPrice: gasPrice.ToBig(),
 	}
 
 	// Create a configuration for the geth EVM.
 	config := geth.Config{
-		Interpreter: p.interpreter,
-		StatePrecompiles: map[common.Address]geth.PrecompiledStateContract{
-			stateContractAddress: preCompiledStateContract{},
-		},
+		Interpreter:      p.interpreter,
+		StatePrecompiles: p.getStatePrecompiles(context),
+	}
+	if tracer != nil {
+		config.Tracer = geth_interpreter.NewCallTracingHooks(tracer, 0, transaction.Sender)
+		config.Interpreter = geth_adapter.NewGethInterpreterFactory(
+			tracingInterpreter{p.toscaInterpreter, tracer},
+		)
 	}
 
 	// Set hard forks for chainconfig
@@ -176,17 +247,34 @@ func (p *processor) Run(
 
 	gas := transaction.GasLimit
 
-	// Check clauses 1-3, buy gas if everything is correct
-	if err := preCheck(transaction, context); err != nil {
-		return tosca.Receipt{}, err
-	}
-	// Check clauses 4-5, subtract intrinsic gas if everything is correct
-	intrinsicGasCosts, err := IntrinsicGas(transaction)
+	// Check clauses 4-5 before buying gas, such that rejected transactions
+	// do not modify the world state.
+	intrinsicGasCosts, err := IntrinsicGas(transaction, blockParams.Revision)
 	if err != nil {
 		return tosca.Receipt{}, err
 	}
 	if gas < intrinsicGasCosts {
-		return tosca.Receipt{GasUsed: transaction.GasLimit}, fmt.Errorf("%w: have %d, want %d", errIntrinsicGas, transaction.GasLimit, intrinsicGasCosts)
+		return tosca.Receipt{}, fmt.Errorf("%w: have %d, want %d", tosca.ErrIntrinsicGas, transaction.GasLimit, intrinsicGasCosts)
+	}
+	// Since Prague (EIP-7623), transactions must pay at least a floor cost
+	// depending on the size of their data.
+	floorGas := tosca.Gas(0)
+	if blockParams.Revision >= tosca.R14_Prague {
+		floorGas = FloorDataGas(transaction)
+		if transaction.GasLimit < floorGas {
+			return tosca.Receipt{}, fmt.Errorf("%w: have %d, want %d", tosca.ErrFloorDataGas, transaction.GasLimit, floorGas)
+		}
+	}
+	// Since Shanghai (EIP-3860) the size of init code is limited.
+	if transaction.Recipient == nil && blockParams.Revision >= tosca.R12_Shanghai && len(transaction.Input) > params.MaxInitCodeSize {
+		return tosca.Receipt{}, fmt.Errorf("%w: code size %v limit %v", tosca.ErrMaxInitCodeSizeExceeded, len(transaction.Input), params.MaxInitCodeSize)
+	}
+	// Check clauses 1-3, buy gas if everything is correct. Simulated and
+	// system transactions are exempt from these checks and are not charged.
+	if paid {
+		if err := preCheck(transaction, gasPrice, context); err != nil {
+			return tosca.Receipt{}, err
+		}
 	}
 	gas -= intrinsicGasCosts
 
@@ -241,8 +329,8 @@ func (p *processor) Run(
 		output, gasLeft, vmError = evm.Call(sender, common.Address(*transaction.Recipient), transaction.Input, uint64(gas), transaction.Value.ToUint256())
 	}
 
-	// For whatever reason, 10% of remaining gas is charged for non-internal transactions.
-	if !isInternal(transaction) {
+	// For whatever reason, 10% of remaining gas is charged for non-system transactions.
+	if !system {
 		gasLeft = gasLeft - gasLeft/10
 	}
 
@@ -267,8 +355,15 @@ func (p *processor) Run(
 		gasLeft += refund
 	}
 
+	// Charge the floor cost if the gas used does not cover it.
+	if gasUsed := uint64(transaction.GasLimit) - gasLeft; gasUsed < uint64(floorGas) {
+		gasLeft = uint64(transaction.GasLimit - floorGas)
+	}
+
 	// refund remaining gas
-	refundGas(transaction, tosca.Gas(gasLeft), context)
+	if paid {
+		refundGas(transaction, gasPrice, tosca.Gas(gasLeft), context)
+	}
 
 	// Extract log messages.
 	logs := make([]tosca.Log, 0)
@@ -284,15 +379,52 @@ func (p *processor) Run(
 		})
 	}
 
+	failureCause := geth_interpreter.ToFailureCause(vmError)
+	if failureCause == tosca.FailureOther && !contractCreation {
+		// Errors of precompiled contracts are not classified by geth.
+		rules := evm.ChainConfig().Rules(blockCtx.BlockNumber, blockCtx.Random != nil, blockCtx.Time)
+		if slices.Contains(geth.ActivePrecompiles(rules), common.Address(*transaction.Recipient)) {
+			failureCause = tosca.FailurePrecompile
+		}
+	}
+	revertReason := ""
+	if failureCause == tosca.FailureRevert {
+		revertReason, _ = tosca.DecodeRevertReason(output)
+	}
+
 	return tosca.Receipt{
-		Success:         vmError == nil,
-		GasUsed:         transaction.GasLimit - tosca.Gas(gasLeft),
-		ContractAddress: createdContract,
-		Output:          output,
-		Logs:            logs,
+		Success:           vmError == nil,
+		GasUsed:           transaction.GasLimit - tosca.Gas(gasLeft),
+		EffectiveGasPrice: gasPrice,
+		ContractAddress:   createdContract,
+		Output:            output,
+		Logs:              logs,
+		FailureCause:      failureCause,
+		RevertReason:      revertReason,
 	}, nil
 }
 
+// getEffectiveGasPrice validates the fee caps of the transaction against the
+// base fee of the block and computes the price paid per unit of gas.
+func getEffectiveGasPrice(blockParams tosca.BlockParameters, transaction tosca.Transaction) (tosca.Value, error) {
+	if blockParams.Revision < tosca.R10_London {
+		if transaction.IsDynamicFee() {
+			return tosca.Value{}, fmt.Errorf("%w: dynamic fee transaction before London", tosca.ErrTxTypeNotSupported)
+		}
+		return transaction.GasPrice, nil
+	}
+	feeCap := transaction.GetGasFeeCap()
+	if tipCap := transaction.GetGasTipCap(); tipCap.Cmp(feeCap) > 0 {
+		return tosca.Value{}, fmt.Errorf("%w: address %v, maxPriorityFeePerGas: %v, maxFeePerGas: %v", tosca.ErrTipAboveFeeCap,
+			transaction.Sender, tipCap, feeCap)
+	}
+	if feeCap.Cmp(blockParams.BaseFee) < 0 {
+		return tosca.Value{}, fmt.Errorf("%w: address %v, maxFeePerGas: %v, baseFee: %v", tosca.ErrFeeCapTooLow,
+			transaction.Sender, feeCap, blockParams.BaseFee)
+	}
+	return transaction.GetEffectiveGasPrice(blockParams.BaseFee), nil
+}
+
 var emptyCodeHash = keccak(nil)
 
 func keccak(data []byte) tosca.Hash {
@@ -303,41 +435,38 @@ func keccak(data []byte) tosca.Hash {
 	return res
 }
 
-func preCheck(transaction tosca.Transaction, state tosca.WorldState) error {
-	// Only check transactions that are not fake
-	// TODO: add support for non-checked transactions
-
+func preCheck(transaction tosca.Transaction, gasPrice tosca.Value, state tosca.WorldState) error {
 	// Make sure this transaction's nonce is correct.
 	stNonce := state.GetNonce(transaction.Sender)
 	if msgNonce := transaction.Nonce; stNonce < msgNonce {
 		//skippedTxsNonceTooHighMeter.Mark(1)
-		return fmt.Errorf("%w: address %v, tx: %d state: %d", errNonceTooHigh,
+		return fmt.Errorf("%w: address %v, tx: %d state: %d", tosca.ErrNonceTooHigh,
 			transaction.Sender, msgNonce, stNonce)
 	} else if stNonce > msgNonce {
 		//skippedTxsNonceTooLowMeter.Mark(1)
-		return fmt.Errorf("%w: address %v, tx: %d state: %d", errNonceTooLow,
+		return fmt.Errorf("%w: address %v, tx: %d state: %d", tosca.ErrNonceTooLow,
 			transaction.Sender, msgNonce, stNonce)
 	}
 	// Make sure the sender is an EOA (Externally Owned Account)
 	if codeHash := state.GetCodeHash(transaction.Sender); codeHash != emptyCodeHash && codeHash != (tosca.Hash{}) {
-		return fmt.Errorf("%w: address %v, codehash: %s", errSenderNoEOA,
+		return fmt.Errorf("%w: address %v, codehash: %s", tosca.ErrSenderNoEOA,
 			transaction.Sender, codeHash)
 	}
 
-	// Note: Opera doesn't need to check gasFeeCap >= BaseFee, because it's already checked by epochcheck
-	return buyGas(transaction, state)
+	return buyGas(transaction, gasPrice, state)
 }
 
-func buyGas(tx tosca.Transaction, state tosca.WorldState) error {
+func buyGas(tx tosca.Transaction, gasPrice tosca.Value, state tosca.WorldState) error {
 	// TODO: support arithmetic operations with Value type
-	gasPrice := tx.GasPrice.ToUint256()
 	mgval := uint256.NewInt(uint64(tx.GasLimit))
-	mgval = mgval.Mul(mgval, gasPrice)
+	mgval = mgval.Mul(mgval, gasPrice.ToUint256())
 	// Note: Opera doesn't need to check against gasFeeCap instead of gasPrice, as it's too aggressive in the asynchronous environment
+	// The sender must still be able to cover the transferred value.
+	balanceCheck := new(uint256.Int).Add(mgval, tx.Value.ToUint256())
 	balance := state.GetBalance(tx.Sender)
-	if have, want := balance.ToUint256(), mgval; have.Cmp(want) < 0 {
+	if have, want := balance.ToUint256(), balanceCheck; have.Cmp(want) < 0 {
 		//skippedTxsNoBalanceMeter.Mark(1)
-		return fmt.Errorf("%w: address %v have %v want %v", errInsufficientFunds, tx.Sender, have, want)
+		return fmt.Errorf("%w: address %v have %v want %v", tosca.ErrInsufficientFunds, tx.Sender, have, want)
 	}
 	// TODO: track block-wide gas usage
 	/*
@@ -355,10 +484,10 @@ func buyGas(tx tosca.Transaction, state tosca.WorldState) error {
 	return nil
 }
 
-func refundGas(tx tosca.Transaction, gasLeft tosca.Gas, state tosca.WorldState) {
+func refundGas(tx tosca.Transaction, gasPrice tosca.Value, gasLeft tosca.Gas, state tosca.WorldState) {
 
 	// Return wei for remaining gas, exchanged at the original rate.
-	refund := new(uint256.Int).Mul(new(uint256.Int).SetUint64(uint64(gasLeft)), tx.GasPrice.ToUint256())
+	refund := new(uint256.Int).Mul(new(uint256.Int).SetUint64(uint64(gasLeft)), gasPrice.ToUint256())
 
 	cur := state.GetBalance(tx.Sender)
 	updated := new(uint256.Int).Add(cur.ToUint256(), refund)
@@ -373,7 +502,7 @@ func refundGas(tx tosca.Transaction, gasLeft tosca.Gas, state tosca.WorldState)
 }
 
 // IntrinsicGas computes the 'intrinsic gas' for a message with the given data.
-func IntrinsicGas(transaction tosca.Transaction) (tosca.Gas, error) {
+func IntrinsicGas(transaction tosca.Transaction, revision tosca.Revision) (tosca.Gas, error) {
 	// Set the starting gas for the raw transaction
 	var gas uint64
 	if transaction.Recipient == nil {
@@ -392,15 +521,23 @@ func IntrinsicGas(transaction tosca.Transaction) (tosca.Gas, error) {
 		}
 		// Make sure we don't exceed uint64 for all data combinations
 		if (math.MaxUint64-gas)/params.TxDataNonZeroGasEIP2028 < nz {
-			return transaction.GasLimit, geth.ErrOutOfGas
+			return transaction.GasLimit, tosca.ErrGasUintOverflow
 		}
 		gas += nz * params.TxDataNonZeroGasEIP2028
 
 		z := uint64(len(transaction.Input)) - nz
 		if (math.MaxUint64-gas)/params.TxDataZeroGas < z {
-			return transaction.GasLimit, errGasUintOverflow
+			return transaction.GasLimit, tosca.ErrGasUintOverflow
 		}
 		gas += z * params.TxDataZeroGas
+
+		if transaction.Recipient == nil && revision >= tosca.R12_Shanghai {
+			lenWords := toWordSize(uint64(len(transaction.Input)))
+			if (math.MaxUint64-gas)/params.InitCodeWordGas < lenWords {
+				return transaction.GasLimit, tosca.ErrGasUintOverflow
+			}
+			gas += lenWords * params.InitCodeWordGas
+		}
 	}
 	accessList := transaction.AccessList
 	if accessList != nil {
@@ -412,212 +549,79 @@ func IntrinsicGas(transaction tosca.Transaction) (tosca.Gas, error) {
 	return tosca.Gas(gas), nil
 }
 
-func isInternal(transaction tosca.Transaction) bool {
-	return transaction.Sender == tosca.Address{}
+// toWordSize returns the ceiled word size required for init code payment calculation.
+func toWordSize(size uint64) uint64 {
+	if size > math.MaxUint64-31 {
+		return math.MaxUint64/32 + 1
+	}
+	return (size + 31) / 32
 }
 
-// Source: https://github.com/Fantom-foundation/Sonic/blob/main/opera/contracts/evmwriter/evm_writer.go#L24
-
-// driverAddress is the NodeDriver contract address
-var driverAddress = common.HexToAddress("0xd100a01e00000000000000000000000000000000")
+const (
+	txCostFloorPerToken   = 10 // Gas charged per token of data by the floor cost (EIP-7623).
+	txTokenPerNonZeroByte = 4  // Tokens counted for a non-zero byte of data (EIP-7623).
+)
 
-// stateContractAddress is the EvmWriter pre-compiled contract address
-var stateContractAddress = common.HexToAddress("0xd100ec0000000000000000000000000000000000")
+// FloorDataGas computes the minimum gas to be paid for a message with the
+// given data since Prague (EIP-7623).
+func FloorDataGas(transaction tosca.Transaction) tosca.Gas {
+	tokens := uint64(len(transaction.Input))
+	for _, byt := range transaction.Input {
+		if byt != 0 {
+			tokens += txTokenPerNonZeroByte - 1
+		}
+	}
+	return tosca.Gas(params.TxGas + tokens*txCostFloorPerToken)
+}
 
-// stateContractABI is the input ABI used to generate the binding from
-var stateContractABI string = "[{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"num\",\"type\":\"uint256\"}],\"name\":\"AdvanceEpochs\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"internalType\":\"bytes\",\"name\":\"diff\",\"type\":\"bytes\"}],\"name\":\"UpdateNetworkRules\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"version\",\"type\":\"uint256\"}],\"name\":\"UpdateNetworkVersion\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"uint256\",\"name\":\"validatorID\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"bytes\",\"name\":\"pubkey\",\"type\":\"bytes\"}],\"name\":\"UpdateValidatorPubkey\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"uint256\",\"name\":\"validatorID\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"weight\",\"type\":\"uint256\"}],\"name\":\"UpdateValidatorWeight\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"backend\",\"type\":\"address\"}],\"name\":\"UpdatedBackend\",\"type\":\"event\"},{\"constant\":false,\"inputs\":[{\"internalType\":\"address\",\"name\":\"_backend\",\"type\":\"address\"}],\"name\":\"setBackend\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"internalType\":\"address\",\"name\":\"_backend\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"_evmWriterAddress\",\"type\":\"address\"}],\"name\":\"initialize\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"internalType\":\"address\",\"name\":\"acc\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"value\",\"type\":\"uint256\"}],\"name\":\"setBalance\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"internalType\":\"address\",\"name\":\"acc\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"from\",\"type\":\"address\"}],\"name\":\"copyCode\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"internalType\":\"address\",\"name\":\"acc\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"with\",\"type\":\"address\"}],\"name\":\"swapCode\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"internalType\":\"address\",\"name\":\"acc\",\"type\":\"address\"},{\"internalType\":\"bytes32\",\"name\":\"key\",\"type\":\"bytes32\"},{\"internalType\":\"bytes32\",\"name\":\"value\",\"type\":\"bytes32\"}],\"name\":\"setStorage\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"internalType\":\"address\",\"name\":\"acc\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"diff\",\"type\":\"uint256\"}],\"name\":\"incNonce\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"internalType\":\"bytes\",\"name\":\"diff\",\"type\":\"bytes\"}],\"name\":\"updateNetworkRules\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"version\",\"type\":\"uint256\"}],\"name\":\"updateNetworkVersion\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"num\",\"type\":\"uint256\"}],\"name\":\"advanceEpochs\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"validatorID\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"value\",\"type\":\"uint256\"}],\"name\":\"updateValidatorWeight\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"validatorID\",\"type\":\"uint256\"},{\"internalType\":\"bytes\",\"name\":\"pubkey\",\"type\":\"bytes\"}],\"name\":\"updateValidatorPubkey\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"internalType\":\"address\",\"name\":\"_auth\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"validatorID\",\"type\":\"uint256\"},{\"internalType\":\"bytes\",\"name\":\"pubkey\",\"type\":\"bytes\"},{\"internalType\":\"uint256\",\"name\":\"status\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"createdEpoch\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"createdTime\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"deactivatedEpoch\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"deactivatedTime\",\"type\":\"uint256\"}],\"name\":\"setGenesisValidator\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"internalType\":\"address\",\"name\":\"delegator\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"toValidatorID\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"stake\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"lockedStake\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"lockupFromEpoch\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"lockupEndTime\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"lockupDuration\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"earlyUnlockPenalty\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"rewards\",\"type\":\"uint256\"}],\"name\":\"setGenesisDelegation\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"validatorID\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"status\",\"type\":\"uint256\"}],\"name\":\"deactivateValidator\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"internalType\":\"uint256[]\",\"name\":\"nextValidatorIDs\",\"type\":\"uint256[]\"}],\"name\":\"sealEpochValidators\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"internalType\":\"uint256[]\",\"name\":\"offlineTimes\",\"type\":\"uint256[]\"},{\"internalType\":\"uint256[]\",\"name\":\"offlineBlocks\",\"type\":\"uint256[]\"},{\"internalType\":\"uint256[]\",\"name\":\"uptimes\",\"type\":\"uint256[]\"},{\"internalType\":\"uint256[]\",\"name\":\"originatedTxsFee\",\"type\":\"uint256[]\"}],\"name\":\"sealEpoch\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"internalType\":\"uint256[]\",\"name\":\"offlineTimes\",\"type\":\"uint256[]\"},{\"internalType\":\"uint256[]\",\"name\":\"offlineBlocks\",\"type\":\"uint256[]\"},{\"internalType\":\"uint256[]\",\"name\":\"uptimes\",\"type\":\"uint256[]\"},{\"internalType\":\"uint256[]\",\"name\":\"originatedTxsFee\",\"type\":\"uint256[]\"},{\"internalType\":\"uint256\",\"name\":\"usedGas\",\"type\":\"uint256\"}],\"name\":\"sealEpochV1\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"}]"
+func isSystemTransaction(transaction tosca.Transaction) bool {
+	return transaction.Kind == tosca.SystemTransaction
+}
 
-var (
-	setBalanceMethodID []byte
-	copyCodeMethodID   []byte
-	swapCodeMethodID   []byte
-	setStorageMethodID []byte
-	incNonceMethodID   []byte
-)
+// systemContract adapts a system contract to the interface of state
+// precompiled contracts of geth. The contract operates directly on the state
+// of the processed transaction.
+type systemContract struct {
+	contract *system_contract.Contract
+	state    tosca.WorldState
+}
 
-func init() {
-	abi, err := abi.JSON(strings.NewReader(stateContractABI))
-	if err != nil {
-		panic(err)
-	}
-
-	for name, constID := range map[string]*[]byte{
-		"setBalance": &setBalanceMethodID,
-		"copyCode":   &copyCodeMethodID,
-		"swapCode":   &swapCodeMethodID,
-		"setStorage": &setStorageMethodID,
-		"incNonce":   &incNonceMethodID,
-	} {
-		method, exist := abi.Methods[name]
-		if !exist {
-			panic("unknown EvmWriter method")
+// getStatePrecompiles returns the system contracts enabled by the
+// configuration of this processor, operating on the given state.
+func (p *processor) getStatePrecompiles(state tosca.WorldState) map[common.Address]geth.PrecompiledStateContract {
+	contracts := system_contract.Enabled(p.config.SystemContracts, p.config.WithoutStateContracts)
+	if contracts == nil {
+		return nil
+	}
+	res := make(map[common.Address]geth.PrecompiledStateContract, len(contracts))
+	for _, contract := range contracts {
+		res[common.Address(contract.Address())] = systemContract{
+			contract: contract,
+			state:    state,
 		}
-
-		*constID = make([]byte, len(method.ID))
-		copy(*constID, method.ID)
 	}
+	return res
 }
 
-// preCompiledStateContract is a Fantom specific pre-compiled contract that enables
-// arbitrary state manipulation for book-keeping and testing purposes.
-// It is copied here to avoid a dependency to the Sonic project, which would risk
-// substantial dependency issues in down-stream projects.
-// Source: https://github.com/Fantom-foundation/Sonic/blob/34b607b882eca12fe25cfc28cbcfa869def6d3f3/opera/contracts/evmwriter/evm_writer.go#L54
-type preCompiledStateContract struct{}
-
-func (preCompiledStateContract) Run(
-	stateDB geth.StateDB,
+func (c systemContract) Run(
+	_ geth.StateDB,
 	_ geth.BlockContext,
 	txCtx geth.TxContext,
 	caller common.Address,
 	input []byte,
 	suppliedGas uint64,
 ) ([]byte, uint64, error) {
-	if caller != driverAddress {
-		return nil, 0, geth.ErrExecutionReverted
-	}
-	if len(input) < 4 {
-		return nil, 0, geth.ErrExecutionReverted
-	}
-	if bytes.Equal(input[:4], setBalanceMethodID) {
-		input = input[4:]
-		// setBalance
-		if suppliedGas < params.CallValueTransferGas {
-			return nil, 0, geth.ErrOutOfGas
-		}
-		suppliedGas -= params.CallValueTransferGas
-		if len(input) != 64 {
-			return nil, 0, geth.ErrExecutionReverted
-		}
-
-		acc := common.BytesToAddress(input[12:32])
-		input = input[32:]
-		value := new(uint256.Int).SetBytes(input[:32])
-
-		if acc == txCtx.Origin {
-			// Origin balance shouldn't decrease during his transaction
-			return nil, 0, geth.ErrExecutionReverted
-		}
-
-		balance := stateDB.GetBalance(acc)
-		if balance.Cmp(value) >= 0 {
-			diff := new(uint256.Int).Sub(balance, value)
-			stateDB.SubBalance(acc, diff, tracing.BalanceChangeUnspecified)
-		} else {
-			diff := new(uint256.Int).Sub(value, balance)
-			stateDB.AddBalance(acc, diff, tracing.BalanceChangeUnspecified)
-		}
-	} else if bytes.Equal(input[:4], copyCodeMethodID) {
-		input = input[4:]
-		// copyCode
-		if suppliedGas < params.CreateGas {
-			return nil, 0, geth.ErrOutOfGas
-		}
-		suppliedGas -= params.CreateGas
-		if len(input) != 64 {
-			return nil, 0, geth.ErrExecutionReverted
-		}
-
-		accTo := common.BytesToAddress(input[12:32])
-		input = input[32:]
-		accFrom := common.BytesToAddress(input[12:32])
-
-		code := stateDB.GetCode(accFrom)
-		if code == nil {
-			code = []byte{}
-		}
-		cost := uint64(len(code)) * (params.CreateDataGas + params.MemoryGas)
-		if suppliedGas < cost {
-			return nil, 0, geth.ErrOutOfGas
-		}
-		suppliedGas -= cost
-		if accTo != accFrom {
-			stateDB.SetCode(accTo, code)
-		}
-	} else if bytes.Equal(input[:4], swapCodeMethodID) {
-		input = input[4:]
-		// swapCode
-		cost := 2 * params.CreateGas
-		if suppliedGas < cost {
-			return nil, 0, geth.ErrOutOfGas
-		}
-		suppliedGas -= cost
-		if len(input) != 64 {
-			return nil, 0, geth.ErrExecutionReverted
-		}
-
-		acc0 := common.BytesToAddress(input[12:32])
-		input = input[32:]
-		acc1 := common.BytesToAddress(input[12:32])
-		code0 := stateDB.GetCode(acc0)
-		if code0 == nil {
-			code0 = []byte{}
-		}
-		code1 := stateDB.GetCode(acc1)
-		if code1 == nil {
-			code1 = []byte{}
-		}
-		cost0 := uint64(len(code0)) * (params.CreateDataGas + params.MemoryGas)
-		cost1 := uint64(len(code1)) * (params.CreateDataGas + params.MemoryGas)
-		cost = (cost0 + cost1) / 2 // 50% discount because trie size won't increase after pruning
-		if suppliedGas < cost {
-			return nil, 0, geth.ErrOutOfGas
-		}
-		suppliedGas -= cost
-		if acc0 != acc1 {
-			stateDB.SetCode(acc0, code1)
-			stateDB.SetCode(acc1, code0)
-		}
-	} else if bytes.Equal(input[:4], setStorageMethodID) {
-		input = input[4:]
-		// setStorage
-		if suppliedGas < params.SstoreSetGasEIP2200 {
-			return nil, 0, geth.ErrOutOfGas
-		}
-		suppliedGas -= params.SstoreSetGasEIP2200
-		if len(input) != 96 {
-			return nil, 0, geth.ErrExecutionReverted
-		}
-		acc := common.BytesToAddress(input[12:32])
-		input = input[32:]
-		key := common.BytesToHash(input[:32])
-		input = input[32:]
-		value := common.BytesToHash(input[:32])
-
-		stateDB.SetState(acc, key, value)
-	} else if bytes.Equal(input[:4], incNonceMethodID) {
-		input = input[4:]
-		// incNonce
-		if suppliedGas < params.CallValueTransferGas {
-			return nil, 0, geth.ErrOutOfGas
-		}
-		suppliedGas -= params.CallValueTransferGas
-		if len(input) != 64 {
-			return nil, 0, geth.ErrExecutionReverted
-		}
-
-		acc := common.BytesToAddress(input[12:32])
-		input = input[32:]
-		value := new(big.Int).SetBytes(input[:32])
-
-		if acc == txCtx.Origin {
-			// Origin nonce shouldn't change during his transaction
-			return nil, 0, geth.ErrExecutionReverted
-		}
-
-		if value.Cmp(common.Big256) >= 0 {
-			// Don't allow large nonce increasing to prevent a nonce overflow
-			return nil, 0, geth.ErrExecutionReverted
-		}
-		if value.Sign() <= 0 {
-			return nil, 0, geth.ErrExecutionReverted
-		}
-
-		stateDB.SetNonce(acc, stateDB.GetNonce(acc)+value.Uint64())
-	} else {
+	gas := tosca.Gas(min(suppliedGas, math.MaxInt64))
+	output, gasLeft, err := c.contract.Run(c.state, tosca.Address(txCtx.Origin), tosca.Address(caller), input, gas)
+	switch {
+	case errors.Is(err, system_contract.ErrExecutionReverted):
 		return nil, 0, geth.ErrExecutionReverted
+	case errors.Is(err, system_contract.ErrOutOfGas):
+		return nil, 0, geth.ErrOutOfGas
+	case err != nil:
+		return nil, 0, err
 	}
-	return nil, suppliedGas, nil
+	return output, uint64(gasLeft), nil
 }
 
 // canTransferFunc is the signature of a transfer function
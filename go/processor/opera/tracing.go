@@ -0,0 +1,28 @@
+// Copyright (c) 2024 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package geth
+
+import (
+	"github.com/Fantom-foundation/Tosca/go/tosca"
+)
+
+// tracingInterpreter is a wrapper of a Tosca interpreter attaching a tracer
+// to the parameters of every run. It is required since the geth adapter is
+// not aware of the tracer of the processed transaction.
+type tracingInterpreter struct {
+	tosca.Interpreter
+	tracer tosca.Tracer
+}
+
+func (i tracingInterpreter) Run(params tosca.Parameters) (tosca.Result, error) {
+	params.Tracer = i.tracer
+	return i.Interpreter.Run(params)
+}
@@ -0,0 +1,141 @@
+// Copyright (c) 2024 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package system_contract
+
+import (
+	"math/big"
+
+	"github.com/Fantom-foundation/Tosca/go/tosca"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// The EvmWriter is a Sonic specific system contract enabling arbitrary state
+// manipulation for book-keeping and testing purposes. It is re-implemented
+// here to avoid a dependency to the Sonic project, which would risk
+// substantial dependency issues in down-stream projects.
+// Source: https://github.com/Fantom-foundation/Sonic/blob/34b607b882eca12fe25cfc28cbcfa869def6d3f3/opera/contracts/evmwriter/evm_writer.go#L54
+
+const (
+	callValueTransferGas = tosca.Gas(9000)
+	createGas            = tosca.Gas(32000)
+	createDataGas        = tosca.Gas(200)
+	memoryGas            = tosca.Gas(3)
+	sStoreSetGasEIP2200  = tosca.Gas(20000)
+)
+
+// DriverAddress is the address of Sonic's NodeDriver contract, the only
+// account authorized to call the EvmWriter.
+// It is wrapped in a function to be immutable
+func DriverAddress() tosca.Address {
+	return tosca.Address(common.HexToAddress("0xd100a01e00000000000000000000000000000000"))
+}
+
+// EvmWriterAddress is the address of the EvmWriter system contract.
+// It is wrapped in a function to be immutable
+func EvmWriterAddress() tosca.Address {
+	return tosca.Address(common.HexToAddress("0xd100ec0000000000000000000000000000000000"))
+}
+
+// EvmWriter returns the EvmWriter system contract of the Sonic network.
+func EvmWriter() *Contract {
+	return evmWriter
+}
+
+var evmWriter = MustNew(Definition{
+	Address:           EvmWriterAddress(),
+	ABI:               evmWriterABI,
+	AuthorizedCallers: []tosca.Address{DriverAddress()},
+	Methods: map[string]Method{
+		"setBalance": {Gas: callValueTransferGas, Handler: setBalance},
+		"copyCode":   {Gas: createGas, Handler: copyCode},
+		"swapCode":   {Gas: 2 * createGas, Handler: swapCode},
+		"setStorage": {Gas: sStoreSetGasEIP2200, Handler: setStorage},
+		"incNonce":   {Gas: callValueTransferGas, Handler: incNonce},
+	},
+})
+
+func setBalance(context *Context, arguments []any) error {
+	account := tosca.Address(arguments[0].(common.Address))
+	if account == context.Origin {
+		// Origin balance shouldn't decrease during his transaction
+		return ErrExecutionReverted
+	}
+	context.State.SetBalance(account, toValue(arguments[1].(*big.Int)))
+	return nil
+}
+
+func copyCode(context *Context, arguments []any) error {
+	accountTo := tosca.Address(arguments[0].(common.Address))
+	accountFrom := tosca.Address(arguments[1].(common.Address))
+
+	code := context.State.GetCode(accountFrom)
+	if err := context.UseGas(tosca.Gas(len(code)) * (createDataGas + memoryGas)); err != nil {
+		return err
+	}
+	if accountFrom != accountTo {
+		context.State.SetCode(accountTo, code)
+	}
+	return nil
+}
+
+func swapCode(context *Context, arguments []any) error {
+	account0 := tosca.Address(arguments[0].(common.Address))
+	account1 := tosca.Address(arguments[1].(common.Address))
+
+	code0 := context.State.GetCode(account0)
+	code1 := context.State.GetCode(account1)
+	cost0 := tosca.Gas(len(code0)) * (createDataGas + memoryGas)
+	cost1 := tosca.Gas(len(code1)) * (createDataGas + memoryGas)
+	// 50% discount because trie size won't increase after pruning
+	if err := context.UseGas((cost0 + cost1) / 2); err != nil {
+		return err
+	}
+	if account0 != account1 {
+		context.State.SetCode(account0, code1)
+		context.State.SetCode(account1, code0)
+	}
+	return nil
+}
+
+func setStorage(context *Context, arguments []any) error {
+	account := tosca.Address(arguments[0].(common.Address))
+	key := tosca.Key(arguments[1].([32]byte))
+	value := tosca.Word(arguments[2].([32]byte))
+	context.State.SetStorage(account, key, value)
+	return nil
+}
+
+func incNonce(context *Context, arguments []any) error {
+	account := tosca.Address(arguments[0].(common.Address))
+	diff := arguments[1].(*big.Int)
+
+	if account == context.Origin {
+		// Origin nonce shouldn't change during his transaction
+		return ErrExecutionReverted
+	}
+	if diff.Sign() <= 0 || diff.Cmp(big.NewInt(256)) >= 0 {
+		// Don't allow large nonce increasing to prevent a nonce overflow
+		return ErrExecutionReverted
+	}
+	context.State.SetNonce(account, context.State.GetNonce(account)+diff.Uint64())
+	return nil
+}
+
+// toValue converts a decoded uint256 argument into a value.
+func toValue(value *big.Int) tosca.Value {
+	var res tosca.Value
+	value.FillBytes(res[:])
+	return res
+}
+
+// evmWriterABI is the ABI of the NodeDriver, including the methods of the
+// EvmWriter.
+const evmWriterABI = "[{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"num\",\"type\":\"uint256\"}],\"name\":\"AdvanceEpochs\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"internalType\":\"bytes\",\"name\":\"diff\",\"type\":\"bytes\"}],\"name\":\"UpdateNetworkRules\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"version\",\"type\":\"uint256\"}],\"name\":\"UpdateNetworkVersion\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"uint256\",\"name\":\"validatorID\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"bytes\",\"name\":\"pubkey\",\"type\":\"bytes\"}],\"name\":\"UpdateValidatorPubkey\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"uint256\",\"name\":\"validatorID\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"weight\",\"type\":\"uint256\"}],\"name\":\"UpdateValidatorWeight\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"backend\",\"type\":\"address\"}],\"name\":\"UpdatedBackend\",\"type\":\"event\"},{\"constant\":false,\"inputs\":[{\"internalType\":\"address\",\"name\":\"_backend\",\"type\":\"address\"}],\"name\":\"setBackend\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"internalType\":\"address\",\"name\":\"_backend\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"_evmWriterAddress\",\"type\":\"address\"}],\"name\":\"initialize\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"internalType\":\"address\",\"name\":\"acc\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"value\",\"type\":\"uint256\"}],\"name\":\"setBalance\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"internalType\":\"address\",\"name\":\"acc\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"from\",\"type\":\"address\"}],\"name\":\"copyCode\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"internalType\":\"address\",\"name\":\"acc\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"with\",\"type\":\"address\"}],\"name\":\"swapCode\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"internalType\":\"address\",\"name\":\"acc\",\"type\":\"address\"},{\"internalType\":\"bytes32\",\"name\":\"key\",\"type\":\"bytes32\"},{\"internalType\":\"bytes32\",\"name\":\"value\",\"type\":\"bytes32\"}],\"name\":\"setStorage\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"internalType\":\"address\",\"name\":\"acc\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"diff\",\"type\":\"uint256\"}],\"name\":\"incNonce\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"internalType\":\"bytes\",\"name\":\"diff\",\"type\":\"bytes\"}],\"name\":\"updateNetworkRules\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"version\",\"type\":\"uint256\"}],\"name\":\"updateNetworkVersion\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"num\",\"type\":\"uint256\"}],\"name\":\"advanceEpochs\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"validatorID\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"value\",\"type\":\"uint256\"}],\"name\":\"updateValidatorWeight\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"validatorID\",\"type\":\"uint256\"},{\"internalType\":\"bytes\",\"name\":\"pubkey\",\"type\":\"bytes\"}],\"name\":\"updateValidatorPubkey\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"internalType\":\"address\",\"name\":\"_auth\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"validatorID\",\"type\":\"uint256\"},{\"internalType\":\"bytes\",\"name\":\"pubkey\",\"type\":\"bytes\"},{\"internalType\":\"uint256\",\"name\":\"status\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"createdEpoch\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"createdTime\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"deactivatedEpoch\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"deactivatedTime\",\"type\":\"uint256\"}],\"name\":\"setGenesisValidator\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"internalType\":\"address\",\"name\":\"delegator\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"toValidatorID\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"stake\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"lockedStake\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"lockupFromEpoch\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"lockupEndTime\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"lockupDuration\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"earlyUnlockPenalty\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"rewards\",\"type\":\"uint256\"}],\"name\":\"setGenesisDelegation\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"validatorID\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"status\",\"type\":\"uint256\"}],\"name\":\"deactivateValidator\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"internalType\":\"uint256[]\",\"name\":\"nextValidatorIDs\",\"type\":\"uint256[]\"}],\"name\":\"sealEpochValidators\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"internalType\":\"uint256[]\",\"name\":\"offlineTimes\",\"type\":\"uint256[]\"},{\"internalType\":\"uint256[]\",\"name\":\"offlineBlocks\",\"type\":\"uint256[]\"},{\"internalType\":\"uint256[]\",\"name\":\"uptimes\",\"type\":\"uint256[]\"},{\"internalType\":\"uint256[]\",\"name\":\"originatedTxsFee\",\"type\":\"uint256[]\"}],\"name\":\"sealEpoch\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"internalType\":\"uint256[]\",\"name\":\"offlineTimes\",\"type\":\"uint256[]\"},{\"internalType\":\"uint256[]\",\"name\":\"offlineBlocks\",\"type\":\"uint256[]\"},{\"internalType\":\"uint256[]\",\"name\":\"uptimes\",\"type\":\"uint256[]\"},{\"internalType\":\"uint256[]\",\"name\":\"originatedTxsFee\",\"type\":\"uint256[]\"},{\"internalType\":\"uint256\",\"name\":\"usedGas\",\"type\":\"uint256\"}],\"name\":\"sealEpochV1\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"}]"
@@ -0,0 +1,183 @@
+// Copyright (c) 2024 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package system_contract
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Fantom-foundation/Tosca/go/tosca"
+	"go.uber.org/mock/gomock"
+)
+
+var (
+	setBalanceSelector = []byte{0xe3, 0x04, 0x43, 0xbc}
+	copyCodeSelector   = []byte{0xd6, 0xa0, 0xc7, 0xaf}
+	swapCodeSelector   = []byte{0x07, 0x69, 0x0b, 0x2a}
+	setStorageSelector = []byte{0x39, 0xe5, 0x03, 0xab}
+	incNonceSelector   = []byte{0x79, 0xbe, 0xad, 0x38}
+)
+
+func TestEvmWriter_MethodsUpdateState(t *testing.T) {
+	account0 := tosca.Address{0x10}
+	account1 := tosca.Address{0x11}
+	codeSize := 42
+
+	tests := map[string]struct {
+		input   []byte
+		gasUsed tosca.Gas
+		setup   func(*tosca.MockWorldState)
+	}{
+		"setBalance": {
+			input:   encodeCall(setBalanceSelector, word(account0[:]), word([]byte{5})),
+			gasUsed: callValueTransferGas,
+			setup: func(state *tosca.MockWorldState) {
+				state.EXPECT().SetBalance(account0, tosca.NewValue(5))
+			},
+		},
+		"copyCode": {
+			input:   encodeCall(copyCodeSelector, word(account0[:]), word(account1[:])),
+			gasUsed: createGas + tosca.Gas(codeSize)*(createDataGas+memoryGas),
+			setup: func(state *tosca.MockWorldState) {
+				state.EXPECT().GetCode(account1).Return(make(tosca.Code, codeSize))
+				state.EXPECT().SetCode(account0, make(tosca.Code, codeSize))
+			},
+		},
+		"copyCode to itself": {
+			input:   encodeCall(copyCodeSelector, word(account0[:]), word(account0[:])),
+			gasUsed: createGas,
+			setup: func(state *tosca.MockWorldState) {
+				state.EXPECT().GetCode(account0).Return(nil)
+			},
+		},
+		"swapCode": {
+			input:   encodeCall(swapCodeSelector, word(account0[:]), word(account1[:])),
+			gasUsed: 2*createGas + tosca.Gas(codeSize)*(createDataGas+memoryGas),
+			setup: func(state *tosca.MockWorldState) {
+				state.EXPECT().GetCode(account0).Return(make(tosca.Code, codeSize))
+				state.EXPECT().GetCode(account1).Return(make(tosca.Code, codeSize))
+				state.EXPECT().SetCode(account0, make(tosca.Code, codeSize))
+				state.EXPECT().SetCode(account1, make(tosca.Code, codeSize))
+			},
+		},
+		"setStorage": {
+			input:   encodeCall(setStorageSelector, word(account0[:]), word([]byte{1}), word([]byte{2})),
+			gasUsed: sStoreSetGasEIP2200,
+			setup: func(state *tosca.MockWorldState) {
+				state.EXPECT().SetStorage(account0, tosca.Key{31: 1}, tosca.Word{31: 2})
+			},
+		},
+		"incNonce": {
+			input:   encodeCall(incNonceSelector, word(account0[:]), word([]byte{5})),
+			gasUsed: callValueTransferGas,
+			setup: func(state *tosca.MockWorldState) {
+				state.EXPECT().GetNonce(account0).Return(uint64(7))
+				state.EXPECT().SetNonce(account0, uint64(12))
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			state := tosca.NewMockWorldState(ctrl)
+			test.setup(state)
+
+			gas := tosca.Gas(1_000_000)
+			_, gasLeft, err := EvmWriter().Run(state, tosca.Address{1}, DriverAddress(), test.input, gas)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if want, got := gas-test.gasUsed, gasLeft; want != got {
+				t.Errorf("unexpected gas left, want %d, got %d", want, got)
+			}
+		})
+	}
+}
+
+func TestEvmWriter_InvalidCallsAreReverted(t *testing.T) {
+	origin := tosca.Address{1}
+	account := tosca.Address{2}
+
+	tests := map[string]struct {
+		caller tosca.Address
+		input  []byte
+		want   error
+	}{
+		"unauthorized caller": {
+			caller: account,
+			input:  encodeCall(setBalanceSelector, word(account[:]), word([]byte{5})),
+			want:   ErrExecutionReverted,
+		},
+		"invalid input length": {
+			caller: DriverAddress(),
+			input:  encodeCall(setBalanceSelector, word(account[:])),
+			want:   ErrExecutionReverted,
+		},
+		"setBalance of origin": {
+			caller: DriverAddress(),
+			input:  encodeCall(setBalanceSelector, word(origin[:]), word([]byte{5})),
+			want:   ErrExecutionReverted,
+		},
+		"incNonce of origin": {
+			caller: DriverAddress(),
+			input:  encodeCall(incNonceSelector, word(origin[:]), word([]byte{5})),
+			want:   ErrExecutionReverted,
+		},
+		"incNonce by zero": {
+			caller: DriverAddress(),
+			input:  encodeCall(incNonceSelector, word(account[:]), word([]byte{0})),
+			want:   ErrExecutionReverted,
+		},
+		"incNonce by 256": {
+			caller: DriverAddress(),
+			input:  encodeCall(incNonceSelector, word(account[:]), word([]byte{1, 0})),
+			want:   ErrExecutionReverted,
+		},
+		"copyCode out of gas": {
+			caller: DriverAddress(),
+			input:  encodeCall(copyCodeSelector, word(account[:]), word(origin[:])),
+			want:   ErrOutOfGas,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			state := tosca.NewMockWorldState(ctrl)
+			state.EXPECT().GetCode(origin).Return(make(tosca.Code, 1000)).AnyTimes()
+
+			_, gasLeft, err := EvmWriter().Run(state, origin, test.caller, test.input, createGas+1000)
+			if !errors.Is(err, test.want) {
+				t.Errorf("unexpected error, want %v, got %v", test.want, err)
+			}
+			if gasLeft != 0 {
+				t.Errorf("failed call did not consume all gas, got %d left", gasLeft)
+			}
+		})
+	}
+}
+
+// word left-pads the given data to a 32-byte ABI word.
+func word(data []byte) []byte {
+	res := make([]byte, 32)
+	copy(res[32-len(data):], data)
+	return res
+}
+
+// encodeCall concatenates the given selector and ABI words.
+func encodeCall(selector []byte, words ...[]byte) []byte {
+	res := append([]byte{}, selector...)
+	for _, word := range words {
+		res = append(res, word...)
+	}
+	return res
+}
@@ -0,0 +1,205 @@
+// Copyright (c) 2024 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+// Package system_contract provides a framework for chain specific contracts
+// implemented natively in Go, granting privileged access to the world state
+// to a set of authorized callers. A system contract is declared by an ABI and
+// a Go handler for each supported method. The framework takes care of the
+// dispatching of calls, the authorization of callers, the decoding of
+// arguments, and the charging of gas, such that all processors enabling a
+// system contract share the same implementation.
+package system_contract
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Fantom-foundation/Tosca/go/tosca"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+const (
+	// ErrExecutionReverted is returned by calls of system contracts failing
+	// due to invalid inputs or unauthorized callers.
+	ErrExecutionReverted = tosca.ConstError("execution reverted")
+
+	// ErrOutOfGas is returned by calls of system contracts not providing
+	// enough gas to cover the costs of the called method.
+	ErrOutOfGas = tosca.ConstError("out of gas")
+)
+
+// Definition declares a system contract.
+type Definition struct {
+	// Address is the address the contract is reachable at.
+	Address tosca.Address
+	// ABI is the JSON description of the contract's interface. It may list
+	// methods not implemented by the contract, which are rejected if called.
+	ABI string
+	// AuthorizedCallers lists the only accounts allowed to call the contract.
+	AuthorizedCallers []tosca.Address
+	// Methods maps the names of the implemented methods in the ABI to their
+	// implementations.
+	Methods map[string]Method
+}
+
+// Method is the implementation of a method of a system contract.
+type Method struct {
+	// Gas is charged for each call of the method before its arguments are
+	// decoded. Costs depending on the arguments or the state are charged by
+	// the handler through the context.
+	Gas tosca.Gas
+	// Handler executes the method on the given arguments, decoded according
+	// to the ABI of the contract. Returned errors mark the call as failed.
+	// Handlers should return ErrExecutionReverted for invalid arguments.
+	Handler func(context *Context, arguments []any) error
+}
+
+// Context is the environment of a single call of a system contract method.
+type Context struct {
+	State   tosca.WorldState // < the state of the ongoing transaction
+	Origin  tosca.Address    // < the sender of the ongoing transaction
+	Caller  tosca.Address    // < the account calling the contract
+	gasLeft tosca.Gas
+}
+
+// GasLeft returns the gas still available to the running method.
+func (c *Context) GasLeft() tosca.Gas {
+	return c.gasLeft
+}
+
+// UseGas charges the given amount of gas. If not enough gas is left,
+// ErrOutOfGas is returned, which should be returned by the handler.
+func (c *Context) UseGas(amount tosca.Gas) error {
+	if amount < 0 || c.gasLeft < amount {
+		c.gasLeft = 0
+		return ErrOutOfGas
+	}
+	c.gasLeft -= amount
+	return nil
+}
+
+// Contract is a system contract created from a Definition. Contracts are
+// stateless and may be shared by any number of processors.
+type Contract struct {
+	address    tosca.Address
+	authorized []tosca.Address
+	methods    map[[4]byte]method
+}
+
+type method struct {
+	Method
+	abi abi.Method
+}
+
+// New creates a system contract from the given definition. An error is
+// returned if the ABI is invalid or does not list an implemented method.
+func New(definition Definition) (*Contract, error) {
+	parsed, err := abi.JSON(strings.NewReader(definition.ABI))
+	if err != nil {
+		return nil, fmt.Errorf("invalid ABI: %w", err)
+	}
+	methods := make(map[[4]byte]method, len(definition.Methods))
+	for name, implementation := range definition.Methods {
+		declaration, found := parsed.Methods[name]
+		if !found {
+			return nil, fmt.Errorf("method %s is not declared in the ABI", name)
+		}
+		if implementation.Handler == nil {
+			return nil, fmt.Errorf("method %s has no handler", name)
+		}
+		methods[[4]byte(declaration.ID)] = method{implementation, declaration}
+	}
+	return &Contract{
+		address:    definition.Address,
+		authorized: slices.Clone(definition.AuthorizedCallers),
+		methods:    methods,
+	}, nil
+}
+
+// MustNew is like New but panics on errors. It is intended for contracts
+// declared at package initialization.
+func MustNew(definition Definition) *Contract {
+	contract, err := New(definition)
+	if err != nil {
+		panic(err)
+	}
+	return contract
+}
+
+// Address returns the address the contract is reachable at.
+func (c *Contract) Address() tosca.Address {
+	return c.address
+}
+
+// Run executes a call of the given caller to this contract with the given
+// input and gas. It returns the output of the call and the gas left. Failed
+// calls return an error and consume all provided gas.
+func (c *Contract) Run(
+	state tosca.WorldState,
+	origin tosca.Address,
+	caller tosca.Address,
+	input tosca.Data,
+	gas tosca.Gas,
+) (tosca.Data, tosca.Gas, error) {
+	if !slices.Contains(c.authorized, caller) {
+		return nil, 0, fmt.Errorf("%w: caller %v is not authorized", ErrExecutionReverted, caller)
+	}
+	if len(input) < 4 {
+		return nil, 0, fmt.Errorf("%w: missing method selector", ErrExecutionReverted)
+	}
+	method, found := c.methods[[4]byte(input[:4])]
+	if !found {
+		return nil, 0, fmt.Errorf("%w: unknown method selector %x", ErrExecutionReverted, input[:4])
+	}
+	if gas < method.Gas {
+		return nil, 0, ErrOutOfGas
+	}
+
+	arguments, err := method.abi.Inputs.Unpack(input[4:])
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: %v", ErrExecutionReverted, err)
+	}
+	// Arguments must not be followed by any additional data.
+	if encoded, err := method.abi.Inputs.Pack(arguments...); err != nil || len(encoded) != len(input)-4 {
+		return nil, 0, fmt.Errorf("%w: invalid encoding of arguments", ErrExecutionReverted)
+	}
+
+	context := &Context{
+		State:   state,
+		Origin:  origin,
+		Caller:  caller,
+		gasLeft: gas - method.Gas,
+	}
+	if err := method.Handler(context, arguments); err != nil {
+		return nil, 0, err
+	}
+	return nil, context.gasLeft, nil
+}
+
+// DefaultContracts returns the system contracts available to transactions if
+// a processor is not configured otherwise, matching the setup of the Sonic
+// network.
+func DefaultContracts() []*Contract {
+	return []*Contract{EvmWriter()}
+}
+
+// Enabled returns the system contracts enabled by a processor configuration
+// listing the given contracts. If the list is nil, the default contracts are
+// enabled. If state contracts are disabled, no contract is enabled at all.
+func Enabled(contracts []*Contract, withoutStateContracts bool) []*Contract {
+	if withoutStateContracts {
+		return nil
+	}
+	if contracts == nil {
+		return DefaultContracts()
+	}
+	return contracts
+}
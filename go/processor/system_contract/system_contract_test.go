@@ -0,0 +1,211 @@
+// Copyright (c) 2024 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package system_contract
+
+import (
+	"errors"
+	"math/big"
+	"slices"
+	"testing"
+
+	"github.com/Fantom-foundation/Tosca/go/tosca"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"go.uber.org/mock/gomock"
+)
+
+const testABI = `[
+	{"type":"function","name":"set","inputs":[{"name":"value","type":"uint256"}],"outputs":[]},
+	{"type":"function","name":"unimplemented","inputs":[],"outputs":[]}
+]`
+
+var (
+	setSelector           = crypto.Keccak256([]byte("set(uint256)"))[:4:4]
+	unimplementedSelector = crypto.Keccak256([]byte("unimplemented()"))[:4:4]
+)
+
+func TestNew_RejectsInvalidDefinitions(t *testing.T) {
+	handler := func(*Context, []any) error { return nil }
+	tests := map[string]Definition{
+		"invalid ABI": {
+			ABI: "not an ABI",
+		},
+		"undeclared method": {
+			ABI:     testABI,
+			Methods: map[string]Method{"unknown": {Handler: handler}},
+		},
+		"missing handler": {
+			ABI:     testABI,
+			Methods: map[string]Method{"set": {}},
+		},
+	}
+	for name, definition := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := New(definition); err == nil {
+				t.Errorf("invalid definition was accepted")
+			}
+		})
+	}
+}
+
+func TestContract_RunDispatchesCallsToHandlers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	state := tosca.NewMockWorldState(ctrl)
+	state.EXPECT().SetNonce(tosca.Address{1}, uint64(42))
+
+	contract := MustNew(Definition{
+		Address:           tosca.Address{1},
+		ABI:               testABI,
+		AuthorizedCallers: []tosca.Address{{2}},
+		Methods: map[string]Method{
+			"set": {Gas: 100, Handler: func(context *Context, arguments []any) error {
+				if want, got := (tosca.Address{3}), context.Origin; want != got {
+					t.Errorf("unexpected origin, want %v, got %v", want, got)
+				}
+				if want, got := (tosca.Address{2}), context.Caller; want != got {
+					t.Errorf("unexpected caller, want %v, got %v", want, got)
+				}
+				if err := context.UseGas(50); err != nil {
+					return err
+				}
+				context.State.SetNonce(tosca.Address{1}, arguments[0].(*big.Int).Uint64())
+				return nil
+			}},
+		},
+	})
+
+	input := append(setSelector, common.LeftPadBytes([]byte{42}, 32)...)
+	_, gasLeft, err := contract.Run(state, tosca.Address{3}, tosca.Address{2}, input, 1000)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want, got := tosca.Gas(850), gasLeft; want != got {
+		t.Errorf("unexpected gas left, want %d, got %d", want, got)
+	}
+}
+
+func TestContract_RunRejectsInvalidCalls(t *testing.T) {
+	contract := MustNew(Definition{
+		ABI:               testABI,
+		AuthorizedCallers: []tosca.Address{{2}},
+		Methods: map[string]Method{
+			"set": {Gas: 100, Handler: func(context *Context, arguments []any) error {
+				return context.UseGas(1000)
+			}},
+		},
+	})
+	argument := common.LeftPadBytes([]byte{1}, 32)
+
+	tests := map[string]struct {
+		caller tosca.Address
+		input  []byte
+		gas    tosca.Gas
+		want   error
+	}{
+		"unauthorized caller": {
+			caller: tosca.Address{3},
+			input:  append(setSelector, argument...),
+			gas:    10_000,
+			want:   ErrExecutionReverted,
+		},
+		"missing selector": {
+			caller: tosca.Address{2},
+			input:  []byte{1, 2, 3},
+			gas:    10_000,
+			want:   ErrExecutionReverted,
+		},
+		"unknown method": {
+			caller: tosca.Address{2},
+			input:  []byte{1, 2, 3, 4},
+			gas:    10_000,
+			want:   ErrExecutionReverted,
+		},
+		"unimplemented method": {
+			caller: tosca.Address{2},
+			input:  unimplementedSelector,
+			gas:    10_000,
+			want:   ErrExecutionReverted,
+		},
+		"too short arguments": {
+			caller: tosca.Address{2},
+			input:  append(setSelector, argument[:31]...),
+			gas:    10_000,
+			want:   ErrExecutionReverted,
+		},
+		"trailing data": {
+			caller: tosca.Address{2},
+			input:  append(append(setSelector, argument...), 0),
+			gas:    10_000,
+			want:   ErrExecutionReverted,
+		},
+		"insufficient gas for method": {
+			caller: tosca.Address{2},
+			input:  append(setSelector, argument...),
+			gas:    99,
+			want:   ErrOutOfGas,
+		},
+		"insufficient gas for handler": {
+			caller: tosca.Address{2},
+			input:  append(setSelector, argument...),
+			gas:    1099,
+			want:   ErrOutOfGas,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			input := append([]byte{}, test.input...)
+			_, gasLeft, err := contract.Run(nil, tosca.Address{}, test.caller, input, test.gas)
+			if !errors.Is(err, test.want) {
+				t.Errorf("unexpected error, want %v, got %v", test.want, err)
+			}
+			if gasLeft != 0 {
+				t.Errorf("failed call did not consume all gas, got %d left", gasLeft)
+			}
+		})
+	}
+}
+
+func TestContext_UseGasFailsIfNotEnoughGasIsLeft(t *testing.T) {
+	context := &Context{gasLeft: 10}
+	if err := context.UseGas(4); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want, got := tosca.Gas(6), context.GasLeft(); want != got {
+		t.Errorf("unexpected gas left, want %d, got %d", want, got)
+	}
+	if err := context.UseGas(7); !errors.Is(err, ErrOutOfGas) {
+		t.Errorf("unexpected error, want %v, got %v", ErrOutOfGas, err)
+	}
+}
+
+func TestEnabled_SelectsContractsByConfiguration(t *testing.T) {
+	custom := MustNew(Definition{Address: tosca.Address{1}, ABI: testABI})
+
+	tests := map[string]struct {
+		contracts             []*Contract
+		withoutStateContracts bool
+		want                  []*Contract
+	}{
+		"default":           {want: []*Contract{EvmWriter()}},
+		"custom":            {contracts: []*Contract{custom}, want: []*Contract{custom}},
+		"none":              {contracts: []*Contract{}, want: []*Contract{}},
+		"disabled":          {withoutStateContracts: true},
+		"disabled override": {contracts: []*Contract{custom}, withoutStateContracts: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if want, got := test.want, Enabled(test.contracts, test.withoutStateContracts); !slices.Equal(want, got) {
+				t.Errorf("unexpected contracts, wanted %v, got %v", want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+// Copyright (c) 2024 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package tosca
+
+import (
+	"bytes"
+	"slices"
+)
+
+// AccessListResult summarizes the access list generated for a transaction.
+type AccessListResult struct {
+	AccessList               []AccessTuple // < accounts and storage slots accessed by the transaction
+	GasUsed                  Gas           // < gas used by the transaction with the access list
+	GasUsedWithoutAccessList Gas           // < gas used by the transaction without an access list
+}
+
+// CreateAccessList determines the access list (EIP-2930) covering all accounts
+// and storage slots accessed by the given transaction when run by the given
+// processor. The sender, the recipient or created contract, and the given
+// precompiled contracts are excluded from the list, since they are accessible
+// at no extra cost anyway (EIP-2929). Storage slots of these accounts are
+// included, though.
+//
+// Since an access list may alter the execution of a transaction, for instance
+// through gas-dependent branches, the transaction is re-run with the list of
+// the previous iteration until the list is stable. All runs are conducted on
+// snapshots of the given context, which are reverted afterwards. Thus, the
+// context is not modified.
+func CreateAccessList(
+	processor Processor,
+	blockParameters BlockParameters,
+	transaction Transaction,
+	context TransactionContext,
+	precompiles []Address,
+) (AccessListResult, error) {
+	run := func(accessList []AccessTuple) (Receipt, *accessRecorder, error) {
+		transaction := transaction
+		transaction.AccessList = accessList
+		recorder := newAccessRecorder(context, accessList)
+		snapshot := context.CreateSnapshot()
+		defer context.RestoreSnapshot(snapshot)
+		receipt, err := processor.Run(blockParameters, transaction, recorder)
+		return receipt, recorder, err
+	}
+
+	excluded := map[Address]struct{}{transaction.Sender: {}}
+	if transaction.Recipient != nil {
+		excluded[*transaction.Recipient] = struct{}{}
+	}
+	for _, address := range precompiles {
+		excluded[address] = struct{}{}
+	}
+
+	accessList := transaction.AccessList
+	for {
+		receipt, recorder, err := run(accessList)
+		if err != nil {
+			return AccessListResult{}, err
+		}
+		if receipt.ContractAddress != nil {
+			excluded[*receipt.ContractAddress] = struct{}{}
+		}
+		next := recorder.getAccessList(excluded)
+		if !accessListsEqual(accessList, next) {
+			accessList = next
+			continue
+		}
+
+		withoutList, _, err := run(nil)
+		if err != nil {
+			return AccessListResult{}, err
+		}
+		return AccessListResult{
+			AccessList:               next,
+			GasUsed:                  receipt.GasUsed,
+			GasUsedWithoutAccessList: withoutList.GasUsed,
+		}, nil
+	}
+}
+
+// accessRecorder is a TransactionContext recording all accounts and storage
+// slots accessed through it.
+type accessRecorder struct {
+	TransactionContext
+	accessed map[Address]map[Key]struct{}
+}
+
+// newAccessRecorder creates a recorder on top of the given context. The
+// entries of the given access list are considered to be accessed, such that
+// access lists produced by successive runs are growing monotonically.
+func newAccessRecorder(context TransactionContext, accessList []AccessTuple) *accessRecorder {
+	res := &accessRecorder{
+		TransactionContext: context,
+		accessed:           map[Address]map[Key]struct{}{},
+	}
+	for _, tuple := range accessList {
+		res.addAddress(tuple.Address)
+		for _, key := range tuple.Keys {
+			res.addSlot(tuple.Address, key)
+		}
+	}
+	return res
+}
+
+func (r *accessRecorder) addAddress(address Address) {
+	if _, found := r.accessed[address]; !found {
+		r.accessed[address] = map[Key]struct{}{}
+	}
+}
+
+func (r *accessRecorder) addSlot(address Address, key Key) {
+	r.addAddress(address)
+	r.accessed[address][key] = struct{}{}
+}
+
+func (r *accessRecorder) AccessAccount(address Address) AccessStatus {
+	r.addAddress(address)
+	return r.TransactionContext.AccessAccount(address)
+}
+
+func (r *accessRecorder) AccessStorage(address Address, key Key) AccessStatus {
+	r.addSlot(address, key)
+	return r.TransactionContext.AccessStorage(address, key)
+}
+
+func (r *accessRecorder) GetTracer() Tracer {
+	return GetTracer(r.TransactionContext)
+}
+
+// getAccessList produces the recorded accesses as an access list, sorted by
+// addresses and keys. Excluded accounts are only listed if any of their
+// storage slots got accessed.
+func (r *accessRecorder) getAccessList(excluded map[Address]struct{}) []AccessTuple {
+	res := []AccessTuple{}
+	for address, slots := range r.accessed {
+		if _, found := excluded[address]; found && len(slots) == 0 {
+			continue
+		}
+		keys := make([]Key, 0, len(slots))
+		for key := range slots {
+			keys = append(keys, key)
+		}
+		slices.SortFunc(keys, func(a, b Key) int {
+			return bytes.Compare(a[:], b[:])
+		})
+		res = append(res, AccessTuple{Address: address, Keys: keys})
+	}
+	slices.SortFunc(res, func(a, b AccessTuple) int {
+		return bytes.Compare(a.Address[:], b.Address[:])
+	})
+	return res
+}
+
+func accessListsEqual(a, b []AccessTuple) bool {
+	return slices.EqualFunc(a, b, func(a, b AccessTuple) bool {
+		return a.Address == b.Address && slices.Equal(a.Keys, b.Keys)
+	})
+}
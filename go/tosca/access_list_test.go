@@ -0,0 +1,147 @@
+// Copyright (c) 2024 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package tosca
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestCreateAccessList_ListsAccessedAccountsAndSlots(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	processor := NewMockProcessor(ctrl)
+
+	sender := Address{1}
+	recipient := Address{2}
+	precompile := Address{3}
+	processor.EXPECT().Run(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ BlockParameters, transaction Transaction, context TransactionContext) (Receipt, error) {
+			context.AccessAccount(sender)
+			context.AccessAccount(recipient)
+			context.AccessAccount(precompile)
+			context.AccessStorage(recipient, Key{2})
+			context.AccessStorage(recipient, Key{1})
+			context.AccessAccount(Address{5})
+			context.AccessStorage(Address{4}, Key{7})
+			return Receipt{GasUsed: Gas(30_000 - 100*len(transaction.AccessList))}, nil
+		}).AnyTimes()
+
+	context := NewInMemoryTransactionContext(R13_Cancun, nil)
+	transaction := Transaction{Sender: sender, Recipient: &recipient}
+	result, err := CreateAccessList(processor, BlockParameters{}, transaction, context, []Address{precompile})
+	if err != nil {
+		t.Fatalf("failed to create access list: %v", err)
+	}
+
+	want := []AccessTuple{
+		{Address: recipient, Keys: []Key{{1}, {2}}},
+		{Address: Address{4}, Keys: []Key{{7}}},
+		{Address: Address{5}, Keys: []Key{}},
+	}
+	if got := result.AccessList; !reflect.DeepEqual(want, got) {
+		t.Errorf("unexpected access list, wanted %v, got %v", want, got)
+	}
+	if want, got := Gas(29_700), result.GasUsed; want != got {
+		t.Errorf("unexpected gas used, wanted %d, got %d", want, got)
+	}
+	if want, got := Gas(30_000), result.GasUsedWithoutAccessList; want != got {
+		t.Errorf("unexpected gas used without access list, wanted %d, got %d", want, got)
+	}
+}
+
+func TestCreateAccessList_IteratesUntilListIsStable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	processor := NewMockProcessor(ctrl)
+
+	// Each run accesses one more account than listed in the access list.
+	processor.EXPECT().Run(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ BlockParameters, transaction Transaction, context TransactionContext) (Receipt, error) {
+			if len(transaction.AccessList) < 3 {
+				context.AccessAccount(Address{byte(10 + len(transaction.AccessList))})
+			}
+			return Receipt{}, nil
+		}).Times(5)
+
+	context := NewInMemoryTransactionContext(R13_Cancun, nil)
+	result, err := CreateAccessList(processor, BlockParameters{}, Transaction{}, context, nil)
+	if err != nil {
+		t.Fatalf("failed to create access list: %v", err)
+	}
+	if want, got := 3, len(result.AccessList); want != got {
+		t.Errorf("unexpected length of access list, wanted %d, got %d", want, got)
+	}
+}
+
+func TestCreateAccessList_ExcludesCreatedContract(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	processor := NewMockProcessor(ctrl)
+
+	created := Address{7}
+	processor.EXPECT().Run(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ BlockParameters, _ Transaction, context TransactionContext) (Receipt, error) {
+			context.AccessAccount(created)
+			return Receipt{ContractAddress: &created}, nil
+		}).AnyTimes()
+
+	context := NewInMemoryTransactionContext(R13_Cancun, nil)
+	result, err := CreateAccessList(processor, BlockParameters{}, Transaction{}, context, nil)
+	if err != nil {
+		t.Fatalf("failed to create access list: %v", err)
+	}
+	if want, got := 0, len(result.AccessList); want != got {
+		t.Errorf("unexpected length of access list, wanted %d, got %d", want, got)
+	}
+}
+
+func TestCreateAccessList_DoesNotModifyContext(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	processor := NewMockProcessor(ctrl)
+	processor.EXPECT().Run(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ BlockParameters, _ Transaction, context TransactionContext) (Receipt, error) {
+			context.AccessStorage(Address{1}, Key{1})
+			context.SetStorage(Address{1}, Key{1}, Word{1})
+			return Receipt{}, nil
+		}).AnyTimes()
+
+	context := NewInMemoryTransactionContext(R13_Cancun, nil)
+	if _, err := CreateAccessList(processor, BlockParameters{}, Transaction{}, context, nil); err != nil {
+		t.Fatalf("failed to create access list: %v", err)
+	}
+	if want, got := (Word{}), context.GetStorage(Address{1}, Key{1}); want != got {
+		t.Errorf("storage was modified, wanted %v, got %v", want, got)
+	}
+	if _, slotPresent := context.IsSlotInAccessList(Address{1}, Key{1}); slotPresent {
+		t.Errorf("access of storage slot was not reverted")
+	}
+}
+
+func TestCreateAccessList_ForwardsRejectionsOfTransactions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	processor := NewMockProcessor(ctrl)
+	processor.EXPECT().Run(gomock.Any(), gomock.Any(), gomock.Any()).Return(Receipt{}, ErrNonceTooLow)
+
+	context := NewInMemoryTransactionContext(R13_Cancun, nil)
+	_, err := CreateAccessList(processor, BlockParameters{}, Transaction{}, context, nil)
+	if !errors.Is(err, ErrNonceTooLow) {
+		t.Errorf("unexpected error, wanted %v, got %v", ErrNonceTooLow, err)
+	}
+}
+
+func TestAccessRecorder_ForwardsContractCreationsToContext(t *testing.T) {
+	context := NewInMemoryTransactionContext(R13_Cancun, nil)
+	recorder := newAccessRecorder(context, nil)
+	if !createAndSelfDestruct(recorder, context, Address{1}) {
+		t.Errorf("contract created and self-destructed in the same transaction was not deleted")
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright (c) 2024 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package tosca
+
+import "fmt"
+
+//go:generate mockgen -source block_executor.go -destination block_executor_mock.go -package tosca
+
+// BlockContext is a TransactionContext which can be used for processing a
+// sequence of transactions. After each transaction EndTransaction is called to
+// finalize its effects and to reset transaction-local information like logs,
+// access lists, and transient storage.
+type BlockContext interface {
+	TransactionContext
+	EndTransaction()
+}
+
+// BlockExecutor processes all transactions of a block using a Processor. It
+// enforces the gas limit of the block, pays the transaction fees to the
+// coinbase of the block, and produces receipts including block-level
+// information like the cumulative gas used and the indices of logs.
+type BlockExecutor struct {
+	processor Processor
+}
+
+// NewBlockExecutor creates a block executor running individual transactions
+// on the given processor.
+func NewBlockExecutor(processor Processor) *BlockExecutor {
+	return &BlockExecutor{processor: processor}
+}
+
+// BlockResult summarizes the result of processing the transactions of a block.
+type BlockResult struct {
+	Receipts []BlockReceipt       // < receipts of the included transactions, in order
+	Skipped  []SkippedTransaction // < transactions not included in the block
+	GasUsed  Gas                  // < gas used by all included transactions
+}
+
+// BlockReceipt extends the receipt of a transaction by information on the
+// position of the transaction within its block.
+type BlockReceipt struct {
+	Receipt
+	TransactionIndex  int  // < the position of the transaction among the included transactions
+	CumulativeGasUsed Gas  // < gas used by this and all preceding transactions of the block
+	FirstLogIndex     uint // < block-wide index of the first log of the transaction
+}
+
+// SkippedTransaction identifies a transaction not included in a block and the
+// reason for its exclusion.
+type SkippedTransaction struct {
+	Index int   // < the position of the transaction in the list of processed transactions
+	Err   error // < the reason for skipping the transaction
+}
+
+// ErrBlockGasLimitReached is reported for transactions exceeding the gas
+// remaining in a block.
+const ErrBlockGasLimitReached = ConstError("block gas limit reached")
+
+// Run processes the given transactions in order on the given context.
+// Transactions which are not fitting into the remaining gas of the block, or
+// which are rejected by the processor, are skipped. Their effects on the
+// context are reverted.
+func (e *BlockExecutor) Run(
+	block BlockParameters,
+	transactions []Transaction,
+	context BlockContext,
+) BlockResult {
+	// Since Prague (EIP-2935), the hash of the parent block is recorded in
+	// the history storage contract before any transaction is processed.
+	if block.Revision >= R14_Prague {
+		RecordParentBlockHash(block, context)
+		context.EndTransaction()
+	}
+
+	result := BlockResult{}
+	logIndex := uint(0)
+	for i, transaction := range transactions {
+		receipt, err := e.runTransaction(block, transaction, result.GasUsed, context)
+		if err != nil {
+			result.Skipped = append(result.Skipped, SkippedTransaction{Index: i, Err: err})
+			continue
+		}
+		result.GasUsed += receipt.GasUsed
+		result.Receipts = append(result.Receipts, BlockReceipt{
+			Receipt:           receipt,
+			TransactionIndex:  len(result.Receipts),
+			CumulativeGasUsed: result.GasUsed,
+			FirstLogIndex:     logIndex,
+		})
+		logIndex += uint(len(receipt.Logs))
+	}
+	return result
+}
+
+func (e *BlockExecutor) runTransaction(
+	block BlockParameters,
+	transaction Transaction,
+	blockGasUsed Gas,
+	context BlockContext,
+) (Receipt, error) {
+	defer context.EndTransaction()
+
+	if transaction.GasLimit > block.GasLimit-blockGasUsed {
+		return Receipt{}, fmt.Errorf("%w: %d > %d", ErrBlockGasLimitReached, transaction.GasLimit, block.GasLimit-blockGasUsed)
+	}
+	// System transactions are not paid for, so neither fee caps are checked
+	// nor fees are transferred to the coinbase.
+	system := transaction.Kind == SystemTransaction
+	if feeCap := transaction.GetGasFeeCap(); !system && block.Revision >= R10_London && feeCap.Cmp(block.BaseFee) < 0 {
+		return Receipt{}, fmt.Errorf("%w: %v < %v", ErrFeeCapTooLow, feeCap, block.BaseFee)
+	}
+
+	snapshot := context.CreateSnapshot()
+	receipt, err := e.processor.Run(block, transaction, context)
+	if err != nil {
+		context.RestoreSnapshot(snapshot)
+		return Receipt{}, err
+	}
+
+	if system {
+		return receipt, nil
+	}
+	fee := getTipPerGas(block, transaction).Scale(uint64(receipt.GasUsed))
+	if fee != (Value{}) {
+		context.SetBalance(block.Coinbase, Add(context.GetBalance(block.Coinbase), fee))
+	}
+	return receipt, nil
+}
+
+// getTipPerGas computes the share of the effective gas price paid to the
+// coinbase. Since London (EIP-1559) the base fee is burned.
+func getTipPerGas(block BlockParameters, transaction Transaction) Value {
+	if block.Revision < R10_London {
+		return transaction.GasPrice
+	}
+	return Sub(transaction.GetEffectiveGasPrice(block.BaseFee), block.BaseFee)
+}
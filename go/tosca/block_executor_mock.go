@@ -0,0 +1,412 @@
+// Copyright (c) 2024 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+// Code generated by MockGen. DO NOT EDIT.
+// Source: block_executor.go
+//
+// Generated by this command:
+//
+//	mockgen -source block_executor.go -destination block_executor_mock.go -package tosca
+//
+
+// Package tosca is a generated GoMock package.
+package tosca
+
+import (
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockBlockContext is a mock of BlockContext interface.
+type MockBlockContext struct {
+	ctrl     *gomock.Controller
+	recorder *MockBlockContextMockRecorder
+}
+
+// MockBlockContextMockRecorder is the mock recorder for MockBlockContext.
+type MockBlockContextMockRecorder struct {
+	mock *MockBlockContext
+}
+
+// NewMockBlockContext creates a new mock instance.
+func NewMockBlockContext(ctrl *gomock.Controller) *MockBlockContext {
+	mock := &MockBlockContext{ctrl: ctrl}
+	mock.recorder = &MockBlockContextMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBlockContext) EXPECT() *MockBlockContextMockRecorder {
+	return m.recorder
+}
+
+// AccessAccount mocks base method.
+func (m *MockBlockContext) AccessAccount(arg0 Address) AccessStatus {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AccessAccount", arg0)
+	ret0, _ := ret[0].(AccessStatus)
+	return ret0
+}
+
+// AccessAccount indicates an expected call of AccessAccount.
+func (mr *MockBlockContextMockRecorder) AccessAccount(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccessAccount", reflect.TypeOf((*MockBlockContext)(nil).AccessAccount), arg0)
+}
+
+// AccessStorage mocks base method.
+func (m *MockBlockContext) AccessStorage(arg0 Address, arg1 Key) AccessStatus {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AccessStorage", arg0, arg1)
+	ret0, _ := ret[0].(AccessStatus)
+	return ret0
+}
+
+// AccessStorage indicates an expected call of AccessStorage.
+func (mr *MockBlockContextMockRecorder) AccessStorage(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccessStorage", reflect.TypeOf((*MockBlockContext)(nil).AccessStorage), arg0, arg1)
+}
+
+// AccountExists mocks base method.
+func (m *MockBlockContext) AccountExists(arg0 Address) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AccountExists", arg0)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// AccountExists indicates an expected call of AccountExists.
+func (mr *MockBlockContextMockRecorder) AccountExists(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccountExists", reflect.TypeOf((*MockBlockContext)(nil).AccountExists), arg0)
+}
+
+// CreateContract mocks base method.
+func (m *MockBlockContext) CreateContract(arg0 Address) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "CreateContract", arg0)
+}
+
+// CreateContract indicates an expected call of CreateContract.
+func (mr *MockBlockContextMockRecorder) CreateContract(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateContract", reflect.TypeOf((*MockBlockContext)(nil).CreateContract), arg0)
+}
+
+// CreateSnapshot mocks base method.
+func (m *MockBlockContext) CreateSnapshot() Snapshot {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSnapshot")
+	ret0, _ := ret[0].(Snapshot)
+	return ret0
+}
+
+// CreateSnapshot indicates an expected call of CreateSnapshot.
+func (mr *MockBlockContextMockRecorder) CreateSnapshot() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSnapshot", reflect.TypeOf((*MockBlockContext)(nil).CreateSnapshot))
+}
+
+// EmitLog mocks base method.
+func (m *MockBlockContext) EmitLog(arg0 Log) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "EmitLog", arg0)
+}
+
+// EmitLog indicates an expected call of EmitLog.
+func (mr *MockBlockContextMockRecorder) EmitLog(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EmitLog", reflect.TypeOf((*MockBlockContext)(nil).EmitLog), arg0)
+}
+
+// EndTransaction mocks base method.
+func (m *MockBlockContext) EndTransaction() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "EndTransaction")
+}
+
+// EndTransaction indicates an expected call of EndTransaction.
+func (mr *MockBlockContextMockRecorder) EndTransaction() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EndTransaction", reflect.TypeOf((*MockBlockContext)(nil).EndTransaction))
+}
+
+// GetBalance mocks base method.
+func (m *MockBlockContext) GetBalance(arg0 Address) Value {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBalance", arg0)
+	ret0, _ := ret[0].(Value)
+	return ret0
+}
+
+// GetBalance indicates an expected call of GetBalance.
+func (mr *MockBlockContextMockRecorder) GetBalance(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBalance", reflect.TypeOf((*MockBlockContext)(nil).GetBalance), arg0)
+}
+
+// GetBlockHash mocks base method.
+func (m *MockBlockContext) GetBlockHash(number int64) Hash {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlockHash", number)
+	ret0, _ := ret[0].(Hash)
+	return ret0
+}
+
+// GetBlockHash indicates an expected call of GetBlockHash.
+func (mr *MockBlockContextMockRecorder) GetBlockHash(number any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockHash", reflect.TypeOf((*MockBlockContext)(nil).GetBlockHash), number)
+}
+
+// GetCode mocks base method.
+func (m *MockBlockContext) GetCode(arg0 Address) Code {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCode", arg0)
+	ret0, _ := ret[0].(Code)
+	return ret0
+}
+
+// GetCode indicates an expected call of GetCode.
+func (mr *MockBlockContextMockRecorder) GetCode(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCode", reflect.TypeOf((*MockBlockContext)(nil).GetCode), arg0)
+}
+
+// GetCodeHash mocks base method.
+func (m *MockBlockContext) GetCodeHash(arg0 Address) Hash {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCodeHash", arg0)
+	ret0, _ := ret[0].(Hash)
+	return ret0
+}
+
+// GetCodeHash indicates an expected call of GetCodeHash.
+func (mr *MockBlockContextMockRecorder) GetCodeHash(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCodeHash", reflect.TypeOf((*MockBlockContext)(nil).GetCodeHash), arg0)
+}
+
+// GetCodeSize mocks base method.
+func (m *MockBlockContext) GetCodeSize(arg0 Address) int {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCodeSize", arg0)
+	ret0, _ := ret[0].(int)
+	return ret0
+}
+
+// GetCodeSize indicates an expected call of GetCodeSize.
+func (mr *MockBlockContextMockRecorder) GetCodeSize(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCodeSize", reflect.TypeOf((*MockBlockContext)(nil).GetCodeSize), arg0)
+}
+
+// GetCommittedStorage mocks base method.
+func (m *MockBlockContext) GetCommittedStorage(addr Address, key Key) Word {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCommittedStorage", addr, key)
+	ret0, _ := ret[0].(Word)
+	return ret0
+}
+
+// GetCommittedStorage indicates an expected call of GetCommittedStorage.
+func (mr *MockBlockContextMockRecorder) GetCommittedStorage(addr, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCommittedStorage", reflect.TypeOf((*MockBlockContext)(nil).GetCommittedStorage), addr, key)
+}
+
+// GetLogs mocks base method.
+func (m *MockBlockContext) GetLogs() []Log {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLogs")
+	ret0, _ := ret[0].([]Log)
+	return ret0
+}
+
+// GetLogs indicates an expected call of GetLogs.
+func (mr *MockBlockContextMockRecorder) GetLogs() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLogs", reflect.TypeOf((*MockBlockContext)(nil).GetLogs))
+}
+
+// GetNonce mocks base method.
+func (m *MockBlockContext) GetNonce(arg0 Address) uint64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNonce", arg0)
+	ret0, _ := ret[0].(uint64)
+	return ret0
+}
+
+// GetNonce indicates an expected call of GetNonce.
+func (mr *MockBlockContextMockRecorder) GetNonce(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNonce", reflect.TypeOf((*MockBlockContext)(nil).GetNonce), arg0)
+}
+
+// GetStorage mocks base method.
+func (m *MockBlockContext) GetStorage(arg0 Address, arg1 Key) Word {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStorage", arg0, arg1)
+	ret0, _ := ret[0].(Word)
+	return ret0
+}
+
+// GetStorage indicates an expected call of GetStorage.
+func (mr *MockBlockContextMockRecorder) GetStorage(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStorage", reflect.TypeOf((*MockBlockContext)(nil).GetStorage), arg0, arg1)
+}
+
+// GetTransientStorage mocks base method.
+func (m *MockBlockContext) GetTransientStorage(arg0 Address, arg1 Key) Word {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransientStorage", arg0, arg1)
+	ret0, _ := ret[0].(Word)
+	return ret0
+}
+
+// GetTransientStorage indicates an expected call of GetTransientStorage.
+func (mr *MockBlockContextMockRecorder) GetTransientStorage(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransientStorage", reflect.TypeOf((*MockBlockContext)(nil).GetTransientStorage), arg0, arg1)
+}
+
+// HasSelfDestructed mocks base method.
+func (m *MockBlockContext) HasSelfDestructed(addr Address) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasSelfDestructed", addr)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// HasSelfDestructed indicates an expected call of HasSelfDestructed.
+func (mr *MockBlockContextMockRecorder) HasSelfDestructed(addr any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasSelfDestructed", reflect.TypeOf((*MockBlockContext)(nil).HasSelfDestructed), addr)
+}
+
+// IsAddressInAccessList mocks base method.
+func (m *MockBlockContext) IsAddressInAccessList(addr Address) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsAddressInAccessList", addr)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsAddressInAccessList indicates an expected call of IsAddressInAccessList.
+func (mr *MockBlockContextMockRecorder) IsAddressInAccessList(addr any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsAddressInAccessList", reflect.TypeOf((*MockBlockContext)(nil).IsAddressInAccessList), addr)
+}
+
+// IsSlotInAccessList mocks base method.
+func (m *MockBlockContext) IsSlotInAccessList(addr Address, key Key) (bool, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsSlotInAccessList", addr, key)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// IsSlotInAccessList indicates an expected call of IsSlotInAccessList.
+func (mr *MockBlockContextMockRecorder) IsSlotInAccessList(addr, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsSlotInAccessList", reflect.TypeOf((*MockBlockContext)(nil).IsSlotInAccessList), addr, key)
+}
+
+// RestoreSnapshot mocks base method.
+func (m *MockBlockContext) RestoreSnapshot(arg0 Snapshot) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RestoreSnapshot", arg0)
+}
+
+// RestoreSnapshot indicates an expected call of RestoreSnapshot.
+func (mr *MockBlockContextMockRecorder) RestoreSnapshot(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreSnapshot", reflect.TypeOf((*MockBlockContext)(nil).RestoreSnapshot), arg0)
+}
+
+// SelfDestruct mocks base method.
+func (m *MockBlockContext) SelfDestruct(addr, beneficiary Address) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelfDestruct", addr, beneficiary)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// SelfDestruct indicates an expected call of SelfDestruct.
+func (mr *MockBlockContextMockRecorder) SelfDestruct(addr, beneficiary any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelfDestruct", reflect.TypeOf((*MockBlockContext)(nil).SelfDestruct), addr, beneficiary)
+}
+
+// SetBalance mocks base method.
+func (m *MockBlockContext) SetBalance(arg0 Address, arg1 Value) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetBalance", arg0, arg1)
+}
+
+// SetBalance indicates an expected call of SetBalance.
+func (mr *MockBlockContextMockRecorder) SetBalance(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBalance", reflect.TypeOf((*MockBlockContext)(nil).SetBalance), arg0, arg1)
+}
+
+// SetCode mocks base method.
+func (m *MockBlockContext) SetCode(arg0 Address, arg1 Code) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetCode", arg0, arg1)
+}
+
+// SetCode indicates an expected call of SetCode.
+func (mr *MockBlockContextMockRecorder) SetCode(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCode", reflect.TypeOf((*MockBlockContext)(nil).SetCode), arg0, arg1)
+}
+
+// SetNonce mocks base method.
+func (m *MockBlockContext) SetNonce(arg0 Address, arg1 uint64) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetNonce", arg0, arg1)
+}
+
+// SetNonce indicates an expected call of SetNonce.
+func (mr *MockBlockContextMockRecorder) SetNonce(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetNonce", reflect.TypeOf((*MockBlockContext)(nil).SetNonce), arg0, arg1)
+}
+
+// SetStorage mocks base method.
+func (m *MockBlockContext) SetStorage(arg0 Address, arg1 Key, arg2 Word) StorageStatus {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetStorage", arg0, arg1, arg2)
+	ret0, _ := ret[0].(StorageStatus)
+	return ret0
+}
+
+// SetStorage indicates an expected call of SetStorage.
+func (mr *MockBlockContextMockRecorder) SetStorage(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetStorage", reflect.TypeOf((*MockBlockContext)(nil).SetStorage), arg0, arg1, arg2)
+}
+
+// SetTransientStorage mocks base method.
+func (m *MockBlockContext) SetTransientStorage(arg0 Address, arg1 Key, arg2 Word) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetTransientStorage", arg0, arg1, arg2)
+}
+
+// SetTransientStorage indicates an expected call of SetTransientStorage.
+func (mr *MockBlockContextMockRecorder) SetTransientStorage(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTransientStorage", reflect.TypeOf((*MockBlockContext)(nil).SetTransientStorage), arg0, arg1, arg2)
+}
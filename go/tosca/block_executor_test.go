@@ -0,0 +1,270 @@
+// Copyright (c) 2024 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package tosca
+
+import (
+	"errors"
+	"testing"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestInMemoryTransactionContext_ImplementsBlockContext(t *testing.T) {
+	var _ BlockContext = &InMemoryTransactionContext{}
+}
+
+func TestBlockExecutor_ProducesCumulativeReceipts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	processor := NewMockProcessor(ctrl)
+	context := NewInMemoryTransactionContext(R13_Cancun, nil)
+
+	block := BlockParameters{GasLimit: 100_000, Revision: R13_Cancun}
+	transactions := []Transaction{
+		{Nonce: 0, GasLimit: 30_000},
+		{Nonce: 1, GasLimit: 30_000},
+		{Nonce: 2, GasLimit: 30_000},
+	}
+	logs := [][]Log{
+		{{Address: Address{1}}, {Address: Address{2}}},
+		{},
+		{{Address: Address{3}}},
+	}
+	for i, transaction := range transactions {
+		processor.EXPECT().Run(block, transaction, context).Return(Receipt{
+			Success: true,
+			GasUsed: Gas(21_000 + i),
+			Logs:    logs[i],
+		}, nil)
+	}
+
+	result := NewBlockExecutor(processor).Run(block, transactions, context)
+
+	if want, got := 3, len(result.Receipts); want != got {
+		t.Fatalf("unexpected number of receipts, wanted %d, got %d", want, got)
+	}
+	if want, got := Gas(63_003), result.GasUsed; want != got {
+		t.Errorf("unexpected block gas used, wanted %d, got %d", want, got)
+	}
+	wantCumulativeGas := []Gas{21_000, 42_001, 63_003}
+	wantFirstLogIndex := []uint{0, 2, 2}
+	for i, receipt := range result.Receipts {
+		if want, got := i, receipt.TransactionIndex; want != got {
+			t.Errorf("unexpected transaction index, wanted %d, got %d", want, got)
+		}
+		if want, got := wantCumulativeGas[i], receipt.CumulativeGasUsed; want != got {
+			t.Errorf("unexpected cumulative gas used, wanted %d, got %d", want, got)
+		}
+		if want, got := wantFirstLogIndex[i], receipt.FirstLogIndex; want != got {
+			t.Errorf("unexpected first log index, wanted %d, got %d", want, got)
+		}
+	}
+}
+
+func TestBlockExecutor_SkipsTransactionsExceedingBlockGasLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	processor := NewMockProcessor(ctrl)
+	context := NewInMemoryTransactionContext(R13_Cancun, nil)
+
+	block := BlockParameters{GasLimit: 50_000, Revision: R13_Cancun}
+	transactions := []Transaction{
+		{Nonce: 0, GasLimit: 40_000},
+		{Nonce: 1, GasLimit: 40_000},
+		{Nonce: 2, GasLimit: 25_000},
+	}
+	processor.EXPECT().Run(block, transactions[0], context).Return(Receipt{GasUsed: 25_000}, nil)
+	processor.EXPECT().Run(block, transactions[2], context).Return(Receipt{GasUsed: 21_000}, nil)
+
+	result := NewBlockExecutor(processor).Run(block, transactions, context)
+
+	if want, got := 2, len(result.Receipts); want != got {
+		t.Fatalf("unexpected number of receipts, wanted %d, got %d", want, got)
+	}
+	if want, got := 1, len(result.Skipped); want != got {
+		t.Fatalf("unexpected number of skipped transactions, wanted %d, got %d", want, got)
+	}
+	if want, got := 1, result.Skipped[0].Index; want != got {
+		t.Errorf("unexpected skipped transaction, wanted %d, got %d", want, got)
+	}
+	if !errors.Is(result.Skipped[0].Err, ErrBlockGasLimitReached) {
+		t.Errorf("unexpected error: %v", result.Skipped[0].Err)
+	}
+	if want, got := Gas(46_000), result.GasUsed; want != got {
+		t.Errorf("unexpected block gas used, wanted %d, got %d", want, got)
+	}
+}
+
+func TestBlockExecutor_RevertsEffectsOfRejectedTransactions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	processor := NewMockProcessor(ctrl)
+	context := NewInMemoryTransactionContext(R13_Cancun, nil)
+
+	block := BlockParameters{GasLimit: 50_000, Revision: R13_Cancun}
+	transaction := Transaction{GasLimit: 21_000}
+	injectedError := errors.New("injected error")
+	processor.EXPECT().Run(block, transaction, context).DoAndReturn(
+		func(_ BlockParameters, _ Transaction, context TransactionContext) (Receipt, error) {
+			context.SetBalance(Address{1}, NewValue(1))
+			return Receipt{}, injectedError
+		})
+
+	result := NewBlockExecutor(processor).Run(block, []Transaction{transaction}, context)
+
+	if want, got := 0, len(result.Receipts); want != got {
+		t.Fatalf("unexpected number of receipts, wanted %d, got %d", want, got)
+	}
+	if want, got := 1, len(result.Skipped); want != got {
+		t.Fatalf("unexpected number of skipped transactions, wanted %d, got %d", want, got)
+	}
+	if !errors.Is(result.Skipped[0].Err, injectedError) {
+		t.Errorf("unexpected error: %v", result.Skipped[0].Err)
+	}
+	if context.AccountExists(Address{1}) {
+		t.Errorf("effects of rejected transaction were not reverted")
+	}
+}
+
+func TestBlockExecutor_EndsEachTransaction(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	processor := NewMockProcessor(ctrl)
+	context := NewMockBlockContext(ctrl)
+
+	block := BlockParameters{GasLimit: 50_000, Revision: R13_Cancun}
+	transactions := []Transaction{{GasLimit: 100_000}, {GasLimit: 21_000}}
+
+	gomock.InOrder(
+		context.EXPECT().EndTransaction(),
+		context.EXPECT().CreateSnapshot().Return(Snapshot(0)),
+		processor.EXPECT().Run(block, transactions[1], context).Return(Receipt{GasUsed: 21_000}, nil),
+		context.EXPECT().EndTransaction(),
+	)
+
+	NewBlockExecutor(processor).Run(block, transactions, context)
+}
+
+func TestBlockExecutor_RecordsParentBlockHashSincePrague(t *testing.T) {
+	for _, revision := range []Revision{R13_Cancun, R14_Prague} {
+		t.Run(revision.String(), func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			processor := NewMockProcessor(ctrl)
+			context := NewInMemoryTransactionContext(revision, map[Address]Account{
+				HistoryStorageAddress: {Code: Code{0}},
+			})
+			context.SetBlockHash(41, Hash{0x41})
+
+			block := BlockParameters{BlockNumber: 42, GasLimit: 50_000, Revision: revision}
+			NewBlockExecutor(processor).Run(block, nil, context)
+
+			want := Word{}
+			if revision >= R14_Prague {
+				want = Word{0x41}
+			}
+			if got := context.GetStorage(HistoryStorageAddress, Key{31: 41}); want != got {
+				t.Errorf("unexpected recorded hash, wanted %v, got %v", want, got)
+			}
+		})
+	}
+}
+
+func TestBlockExecutor_PaysFeesToCoinbaseDependingOnRevision(t *testing.T) {
+	coinbase := Address{0xc}
+	tests := map[string]struct {
+		revision Revision
+		want     Value
+	}{
+		"berlin pays full gas price": {R09_Berlin, NewValue(5 * 21_000)},
+		"london burns base fee":      {R10_London, NewValue(3 * 21_000)},
+		"cancun burns base fee":      {R13_Cancun, NewValue(3 * 21_000)},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			processor := NewMockProcessor(ctrl)
+			context := NewInMemoryTransactionContext(test.revision, nil)
+
+			block := BlockParameters{
+				GasLimit: 50_000,
+				Coinbase: coinbase,
+				BaseFee:  NewValue(2),
+				Revision: test.revision,
+			}
+			transaction := Transaction{GasLimit: 30_000, GasPrice: NewValue(5)}
+			processor.EXPECT().Run(block, transaction, context).Return(Receipt{GasUsed: 21_000}, nil)
+
+			NewBlockExecutor(processor).Run(block, []Transaction{transaction}, context)
+
+			if want, got := test.want, context.GetBalance(coinbase); want != got {
+				t.Errorf("unexpected coinbase balance, wanted %v, got %v", want, got)
+			}
+		})
+	}
+}
+
+func TestBlockExecutor_PaysEffectiveTipOfDynamicFeeTransactionsToCoinbase(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	processor := NewMockProcessor(ctrl)
+	context := NewInMemoryTransactionContext(R13_Cancun, nil)
+
+	coinbase := Address{0xc}
+	block := BlockParameters{GasLimit: 50_000, Coinbase: coinbase, BaseFee: NewValue(8), Revision: R13_Cancun}
+	transaction := Transaction{GasLimit: 21_000, GasFeeCap: NewValue(10), GasTipCap: NewValue(5)}
+	processor.EXPECT().Run(block, transaction, context).Return(Receipt{GasUsed: 21_000}, nil)
+
+	NewBlockExecutor(processor).Run(block, []Transaction{transaction}, context)
+
+	// The tip is limited by the fee cap.
+	if want, got := NewValue(2*21_000), context.GetBalance(coinbase); want != got {
+		t.Errorf("unexpected coinbase balance, wanted %v, got %v", want, got)
+	}
+}
+
+func TestBlockExecutor_SkipsTransactionsWithFeeCapBelowBaseFee(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	processor := NewMockProcessor(ctrl)
+	context := NewInMemoryTransactionContext(R10_London, nil)
+
+	block := BlockParameters{GasLimit: 50_000, BaseFee: NewValue(10), Revision: R10_London}
+	transactions := []Transaction{
+		{GasLimit: 21_000, GasPrice: NewValue(9)},
+		{GasLimit: 21_000, GasFeeCap: NewValue(9), GasTipCap: NewValue(1)},
+	}
+
+	result := NewBlockExecutor(processor).Run(block, transactions, context)
+
+	if want, got := 2, len(result.Skipped); want != got {
+		t.Fatalf("unexpected number of skipped transactions, wanted %d, got %d", want, got)
+	}
+	for _, skipped := range result.Skipped {
+		if !errors.Is(skipped.Err, ErrFeeCapTooLow) {
+			t.Errorf("unexpected error: %v", skipped.Err)
+		}
+	}
+}
+
+func TestBlockExecutor_SystemTransactionsPayNoFees(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	processor := NewMockProcessor(ctrl)
+	context := NewInMemoryTransactionContext(R13_Cancun, nil)
+
+	coinbase := Address{0xc}
+	block := BlockParameters{GasLimit: 50_000, Coinbase: coinbase, BaseFee: NewValue(10), Revision: R13_Cancun}
+	transaction := Transaction{GasLimit: 21_000, Kind: SystemTransaction}
+	processor.EXPECT().Run(block, transaction, context).Return(Receipt{Success: true, GasUsed: 21_000}, nil)
+
+	result := NewBlockExecutor(processor).Run(block, []Transaction{transaction}, context)
+
+	if want, got := 0, len(result.Skipped); want != got {
+		t.Fatalf("unexpected number of skipped transactions, wanted %d, got %d", want, got)
+	}
+	if want, got := (Value{}), context.GetBalance(coinbase); want != got {
+		t.Errorf("unexpected coinbase balance, wanted %v, got %v", want, got)
+	}
+}
@@ -0,0 +1,195 @@
+// Copyright (c) 2024 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package tosca
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// CallTracer is a Tracer recording the tree of call frames of a transaction.
+// The recorded tree serializes to the JSON format produced by geth's
+// callTracer, such that tools consuming geth traces can process it unchanged.
+type CallTracer struct {
+	root  *CallFrame
+	stack []*CallFrame
+}
+
+// NewCallTracer creates a tracer recording the call frames of transactions.
+func NewCallTracer() *CallTracer {
+	return &CallTracer{}
+}
+
+// GetResult returns the root frame of the most recently traced transaction,
+// or nil if no transaction has been traced.
+func (t *CallTracer) GetResult() *CallFrame {
+	return t.root
+}
+
+// CallFrame summarizes the execution of a single call or contract creation
+// and all its nested calls.
+type CallFrame struct {
+	Kind         CallKind     // < the kind of the call
+	From         Address      // < the account initiating the call
+	To           *Address     // < the called account or created contract, nil for failed creations
+	Value        *Value       // < the value passed along with the call, nil for static calls
+	Gas          Gas          // < the gas provided to the call
+	GasUsed      Gas          // < the gas consumed by the call, including nested calls
+	Input        Data         // < the call data or init code
+	Output       Data         // < the return data or deployed code
+	Error        string       // < the reason of a failure, empty for successful calls
+	RevertReason string       // < the decoded reason of a revert, if available
+	Calls        []*CallFrame // < the nested calls, in order
+}
+
+func (t *CallTracer) OnStep(StepInfo) {}
+
+func (t *CallTracer) OnEnter(depth int, kind CallKind, parameters CallParameters) {
+	frame := &CallFrame{
+		Kind:  kind,
+		From:  parameters.Sender,
+		Gas:   parameters.Gas,
+		Input: append(Data{}, parameters.Input...),
+	}
+	to := parameters.Recipient
+	switch kind {
+	case DelegateCall, CallCode:
+		// The code of the target is run in the context of the caller.
+		frame.From = parameters.Recipient
+		to = parameters.CodeAddress
+	}
+	if kind != Create && kind != Create2 {
+		frame.To = &to
+	}
+	if kind != StaticCall {
+		value := parameters.Value
+		frame.Value = &value
+	}
+
+	if depth == 0 {
+		t.root = frame
+		t.stack = t.stack[:0]
+	} else if len(t.stack) > 0 {
+		parent := t.stack[len(t.stack)-1]
+		parent.Calls = append(parent.Calls, frame)
+	}
+	t.stack = append(t.stack, frame)
+}
+
+func (t *CallTracer) OnExit(depth int, result CallResult, err error) {
+	if len(t.stack) == 0 {
+		return
+	}
+	frame := t.stack[len(t.stack)-1]
+	t.stack = t.stack[:len(t.stack)-1]
+
+	frame.GasUsed = frame.Gas - min(frame.Gas, result.GasLeft)
+	if frame.Kind == Create || frame.Kind == Create2 {
+		created := result.CreatedAddress
+		frame.To = &created
+	}
+	if err == nil && result.Success {
+		frame.Output = append(Data{}, result.Output...)
+		return
+	}
+
+	if frame.Kind == Create || frame.Kind == Create2 {
+		frame.To = nil
+	}
+	cause := result.FailureCause
+	if cause == FailureNone {
+		// Failures other than reverts consume all gas and produce no output.
+		cause = FailureOther
+		if result.GasLeft > 0 || len(result.Output) > 0 {
+			cause = FailureRevert
+		}
+	}
+	switch {
+	case err != nil:
+		frame.Error = err.Error()
+	case cause == FailureRevert:
+		frame.Error = cause.String()
+		frame.Output = append(Data{}, result.Output...)
+		frame.RevertReason = result.RevertReason
+		if frame.RevertReason == "" {
+			frame.RevertReason, _ = DecodeRevertReason(result.Output)
+		}
+	default:
+		frame.Error = cause.String()
+	}
+}
+
+func (t *CallTracer) OnStorageChange(Address, Key, Word, Word) {}
+
+func (t *CallTracer) OnBalanceChange(Address, Value, Value) {}
+
+func (t *CallTracer) OnLog(Log) {}
+
+// MarshalJSON produces the JSON representation of the frame used by geth's
+// callTracer. Quantities and byte strings are hex encoded.
+func (f *CallFrame) MarshalJSON() ([]byte, error) {
+	type callFrameJson struct {
+		Type         string       `json:"type"`
+		From         Address      `json:"from"`
+		Gas          string       `json:"gas"`
+		GasUsed      string       `json:"gasUsed"`
+		To           *Address     `json:"to,omitempty"`
+		Input        string       `json:"input"`
+		Output       string       `json:"output,omitempty"`
+		Error        string       `json:"error,omitempty"`
+		RevertReason string       `json:"revertReason,omitempty"`
+		Calls        []*CallFrame `json:"calls,omitempty"`
+		Value        string       `json:"value,omitempty"`
+	}
+	kind, err := getCallFrameType(f.Kind)
+	if err != nil {
+		return nil, err
+	}
+	res := callFrameJson{
+		Type:         kind,
+		From:         f.From,
+		Gas:          fmt.Sprintf("0x%x", uint64(f.Gas)),
+		GasUsed:      fmt.Sprintf("0x%x", uint64(f.GasUsed)),
+		To:           f.To,
+		Input:        fmt.Sprintf("0x%x", []byte(f.Input)),
+		Error:        f.Error,
+		RevertReason: f.RevertReason,
+		Calls:        f.Calls,
+	}
+	if len(f.Output) > 0 {
+		res.Output = fmt.Sprintf("0x%x", []byte(f.Output))
+	}
+	if f.Value != nil {
+		res.Value = fmt.Sprintf("0x%x", f.Value.ToBig())
+	}
+	return json.Marshal(res)
+}
+
+// getCallFrameType returns the name of the instruction causing a call of the
+// given kind, which is used to identify the type of call frames.
+func getCallFrameType(kind CallKind) (string, error) {
+	switch kind {
+	case Call:
+		return "CALL", nil
+	case StaticCall:
+		return "STATICCALL", nil
+	case DelegateCall:
+		return "DELEGATECALL", nil
+	case CallCode:
+		return "CALLCODE", nil
+	case Create:
+		return "CREATE", nil
+	case Create2:
+		return "CREATE2", nil
+	default:
+		return "", fmt.Errorf("invalid call kind: %v", kind)
+	}
+}
@@ -0,0 +1,188 @@
+// Copyright (c) 2024 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package tosca
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestCallTracer_ImplementsTracer(t *testing.T) {
+	var _ Tracer = &CallTracer{}
+}
+
+func TestCallTracer_RecordsTreeOfCallFrames(t *testing.T) {
+	tracer := NewCallTracer()
+	tracer.OnEnter(0, Call, CallParameters{Sender: Address{1}, Recipient: Address{2}, Gas: 1000, Value: NewValue(5)})
+	tracer.OnEnter(1, StaticCall, CallParameters{Sender: Address{2}, Recipient: Address{3}, Gas: 500})
+	tracer.OnExit(1, CallResult{Success: true, GasLeft: 400, Output: Data{1}}, nil)
+	tracer.OnEnter(1, Create, CallParameters{Sender: Address{2}, Gas: 300})
+	tracer.OnExit(1, CallResult{Success: true, GasLeft: 100, CreatedAddress: Address{4}}, nil)
+	tracer.OnExit(0, CallResult{Success: true, GasLeft: 50}, nil)
+
+	root := tracer.GetResult()
+	if root == nil {
+		t.Fatalf("no call frame recorded")
+	}
+	if want, got := Gas(950), root.GasUsed; want != got {
+		t.Errorf("unexpected gas used, wanted %d, got %d", want, got)
+	}
+	if want, got := 2, len(root.Calls); want != got {
+		t.Fatalf("unexpected number of nested calls, wanted %d, got %d", want, got)
+	}
+
+	static := root.Calls[0]
+	if static.Kind != StaticCall || *static.To != (Address{3}) || static.Value != nil {
+		t.Errorf("unexpected static call frame: %+v", static)
+	}
+	if want, got := Gas(100), static.GasUsed; want != got {
+		t.Errorf("unexpected gas used, wanted %d, got %d", want, got)
+	}
+
+	create := root.Calls[1]
+	if create.To == nil || *create.To != (Address{4}) {
+		t.Errorf("unexpected created address: %v", create.To)
+	}
+}
+
+func TestCallTracer_ReportsCodeAddressAsTargetOfDelegateCalls(t *testing.T) {
+	for _, kind := range []CallKind{DelegateCall, CallCode} {
+		t.Run(kind.String(), func(t *testing.T) {
+			tracer := NewCallTracer()
+			tracer.OnEnter(0, kind, CallParameters{Sender: Address{1}, Recipient: Address{2}, CodeAddress: Address{3}})
+			tracer.OnExit(0, CallResult{Success: true}, nil)
+
+			frame := tracer.GetResult()
+			if want, got := (Address{2}), frame.From; want != got {
+				t.Errorf("unexpected sender, wanted %v, got %v", want, got)
+			}
+			if want, got := (Address{3}), *frame.To; want != got {
+				t.Errorf("unexpected target, wanted %v, got %v", want, got)
+			}
+		})
+	}
+}
+
+func TestCallTracer_RecordsFailures(t *testing.T) {
+	revert := append(append([]byte{}, revertSelector...), encodeAbiString("out of funds")...)
+	tests := map[string]struct {
+		kind             CallKind
+		result           CallResult
+		err              error
+		wantError        string
+		wantRevertReason string
+		wantTo           bool
+	}{
+		"revert with reason": {
+			kind:             Call,
+			result:           CallResult{GasLeft: 10, Output: revert},
+			wantError:        "execution reverted",
+			wantRevertReason: "out of funds",
+			wantTo:           true,
+		},
+		"revert without output": {
+			kind:      Call,
+			result:    CallResult{GasLeft: 10},
+			wantError: "execution reverted",
+			wantTo:    true,
+		},
+		"failure": {
+			kind:      Call,
+			wantError: "execution failed",
+			wantTo:    true,
+		},
+		"error": {
+			kind:      Call,
+			err:       errors.New("injected error"),
+			wantError: "injected error",
+			wantTo:    true,
+		},
+		"revert with decoded reason": {
+			kind:             Call,
+			result:           CallResult{FailureCause: FailureRevert, RevertReason: "decoded"},
+			wantError:        "execution reverted",
+			wantRevertReason: "decoded",
+			wantTo:           true,
+		},
+		"failure with cause": {
+			kind:      Call,
+			result:    CallResult{FailureCause: FailureOutOfGas},
+			wantError: "out of gas",
+			wantTo:    true,
+		},
+		"failed creation": {
+			kind:      Create,
+			result:    CallResult{CreatedAddress: Address{4}},
+			wantError: "execution failed",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			tracer := NewCallTracer()
+			tracer.OnEnter(0, test.kind, CallParameters{Recipient: Address{2}, Gas: 100})
+			tracer.OnExit(0, test.result, test.err)
+
+			frame := tracer.GetResult()
+			if want, got := test.wantError, frame.Error; want != got {
+				t.Errorf("unexpected error, wanted %q, got %q", want, got)
+			}
+			if want, got := test.wantRevertReason, frame.RevertReason; want != got {
+				t.Errorf("unexpected revert reason, wanted %q, got %q", want, got)
+			}
+			if want, got := test.wantTo, frame.To != nil; want != got {
+				t.Errorf("unexpected presence of target, wanted %t, got %t", want, got)
+			}
+		})
+	}
+}
+
+func TestCallFrame_MarshalJSONProducesGethFormat(t *testing.T) {
+	to := Address{2}
+	nested := Address{3}
+	value := NewValue(16)
+	frame := &CallFrame{
+		Kind:    Call,
+		From:    Address{1},
+		To:      &to,
+		Value:   &value,
+		Gas:     1000,
+		GasUsed: 21,
+		Input:   Data{0x12, 0x34},
+		Calls: []*CallFrame{{
+			Kind:    StaticCall,
+			From:    to,
+			To:      &nested,
+			Gas:     100,
+			GasUsed: 100,
+			Error:   "execution failed",
+		}},
+	}
+
+	data, err := json.Marshal(frame)
+	if err != nil {
+		t.Fatalf("failed to marshal call frame: %v", err)
+	}
+	want := `{"type":"CALL",` +
+		`"from":"0x0100000000000000000000000000000000000000",` +
+		`"gas":"0x3e8","gasUsed":"0x15",` +
+		`"to":"0x0200000000000000000000000000000000000000",` +
+		`"input":"0x1234",` +
+		`"calls":[{"type":"STATICCALL",` +
+		`"from":"0x0200000000000000000000000000000000000000",` +
+		`"gas":"0x64","gasUsed":"0x64",` +
+		`"to":"0x0300000000000000000000000000000000000000",` +
+		`"input":"0x","error":"execution failed"}],` +
+		`"value":"0x10"}`
+	if got := string(data); want != got {
+		t.Errorf("unexpected JSON encoding\nwanted %s\n   got %s", want, got)
+	}
+}
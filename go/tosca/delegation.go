@@ -0,0 +1,31 @@
+// Copyright (c) 2024 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package tosca
+
+import "bytes"
+
+// delegationPrefix is the prefix of delegation designators (EIP-7702).
+var delegationPrefix = []byte{0xef, 0x01, 0x00}
+
+// NewDelegationDesignator creates the code installed in accounts delegating
+// the execution of their code to the given address (EIP-7702).
+func NewDelegationDesignator(address Address) Code {
+	return append(bytes.Clone(delegationPrefix), address[:]...)
+}
+
+// GetDelegationTarget returns the address the execution of the given code is
+// delegated to, if the code is a delegation designator (EIP-7702).
+func GetDelegationTarget(code Code) (Address, bool) {
+	if len(code) != len(delegationPrefix)+len(Address{}) || !bytes.HasPrefix(code, delegationPrefix) {
+		return Address{}, false
+	}
+	return Address(code[len(delegationPrefix):]), true
+}
@@ -0,0 +1,51 @@
+// Copyright (c) 2024 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package tosca
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestNewDelegationDesignator_ProducesPrefixedAddress(t *testing.T) {
+	address := Address{1, 2, 3, 19: 4}
+	want := append([]byte{0xef, 0x01, 0x00}, address[:]...)
+	if got := NewDelegationDesignator(address); !bytes.Equal(want, got) {
+		t.Errorf("unexpected designator, wanted %x, got %x", want, got)
+	}
+}
+
+func TestGetDelegationTarget_RecognizesDesignators(t *testing.T) {
+	address := Address{1, 2, 3, 19: 4}
+	designator := NewDelegationDesignator(address)
+	tests := map[string]struct {
+		code  Code
+		want  Address
+		found bool
+	}{
+		"empty":          {nil, Address{}, false},
+		"designator":     {designator, address, true},
+		"zero address":   {NewDelegationDesignator(Address{}), Address{}, true},
+		"too short":      {designator[:len(designator)-1], Address{}, false},
+		"too long":       {append(bytes.Clone(designator), 0), Address{}, false},
+		"wrong version":  {append(Code{0xef, 0x01, 0x01}, address[:]...), Address{}, false},
+		"regular code":   {append(Code{0x60, 0x01, 0x00}, address[:]...), Address{}, false},
+		"eof style code": {Code{0xef, 0x00, 0x01}, Address{}, false},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, found := GetDelegationTarget(test.code)
+			if test.found != found || test.want != got {
+				t.Errorf("unexpected result, wanted (%v, %t), got (%v, %t)", test.want, test.found, got, found)
+			}
+		})
+	}
+}
@@ -17,3 +17,75 @@ type ConstError string
 func (e ConstError) Error() string {
 	return string(e)
 }
+
+// Errors reported by processors for transactions violating consensus rules.
+// Such transactions can not be included in a block and processors do not
+// modify the world state when rejecting them. Transactions failing during
+// their execution, on the other hand, are reported by unsuccessful receipts.
+const (
+	// ErrNonceTooLow is returned if the nonce of a transaction is lower than
+	// the nonce of the sender account.
+	ErrNonceTooLow = ConstError("nonce too low")
+
+	// ErrNonceTooHigh is returned if the nonce of a transaction is higher than
+	// the nonce of the sender account.
+	ErrNonceTooHigh = ConstError("nonce too high")
+
+	// ErrInsufficientFunds is returned if the total cost of executing a
+	// transaction is higher than the balance of the sender account.
+	ErrInsufficientFunds = ConstError("insufficient funds for gas * price + value")
+
+	// ErrIntrinsicGas is returned if the transaction is specified to use less
+	// gas than required to start the invocation.
+	ErrIntrinsicGas = ConstError("intrinsic gas too low")
+
+	// ErrFloorDataGas is returned if the transaction is specified to use less
+	// gas than required by the floor cost of its data (EIP-7623).
+	ErrFloorDataGas = ConstError("insufficient gas for floor data gas cost")
+
+	// ErrSenderNoEOA is returned if the sender of a transaction is a contract.
+	ErrSenderNoEOA = ConstError("sender not an eoa")
+
+	// ErrGasUintOverflow is returned if the gas costs of a transaction
+	// overflow when being computed.
+	ErrGasUintOverflow = ConstError("gas uint64 overflow")
+
+	// ErrMaxInitCodeSizeExceeded is returned if a contract creation
+	// transaction provides more init code than allowed (EIP-3860).
+	ErrMaxInitCodeSizeExceeded = ConstError("max initcode size exceeded")
+
+	// ErrFeeCapTooLow is returned if the fee cap of a transaction is lower
+	// than the base fee of the block.
+	ErrFeeCapTooLow = ConstError("max fee per gas less than block base fee")
+
+	// ErrTipAboveFeeCap is returned if the tip cap of a transaction is higher
+	// than its fee cap.
+	ErrTipAboveFeeCap = ConstError("max priority fee per gas higher than max fee per gas")
+
+	// ErrTxTypeNotSupported is returned if a transaction uses a feature not
+	// supported by the revision of the block.
+	ErrTxTypeNotSupported = ConstError("transaction type not supported")
+
+	// ErrBlobTxCreate is returned if a blob transaction creates a contract.
+	ErrBlobTxCreate = ConstError("blob transaction of type create")
+
+	// ErrTooManyBlobs is returned if a blob transaction exceeds the blob gas
+	// limit of a block.
+	ErrTooManyBlobs = ConstError("blob transaction has too many blobs")
+
+	// ErrInvalidBlobHashVersion is returned if a blob hash of a transaction
+	// does not have the expected version.
+	ErrInvalidBlobHashVersion = ConstError("blob with invalid hash version")
+
+	// ErrBlobFeeCapTooLow is returned if the blob fee cap of a transaction is
+	// lower than the blob base fee of the block.
+	ErrBlobFeeCapTooLow = ConstError("max fee per blob gas less than block blob gas fee")
+
+	// ErrSetCodeTxCreate is returned if a set-code transaction creates a
+	// contract (EIP-7702).
+	ErrSetCodeTxCreate = ConstError("set code transaction of type create")
+
+	// ErrEmptyAuthList is returned if a set-code transaction does not carry
+	// any authorization (EIP-7702).
+	ErrEmptyAuthList = ConstError("set code transaction with empty auth list")
+)
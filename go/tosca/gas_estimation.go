@@ -0,0 +1,138 @@
+// Copyright (c) 2024 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package tosca
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/holiman/uint256"
+)
+
+// ErrGasRequiredExceedsAllowance is returned by EstimateGas if a transaction
+// does not succeed with the maximum gas limit available to it.
+const ErrGasRequiredExceedsAllowance = ConstError("gas required exceeds allowance")
+
+// callStipend is the free gas provided to the recipient of a value transfer,
+// which must be available to the caller even though it is not consumed.
+const callStipend = 2300
+
+// EstimateGas determines the minimal gas limit for which the given transaction
+// is successfully executed by the given processor. The transaction is run
+// repeatedly with different gas limits on snapshots of the given context,
+// each of which is reverted afterwards. Thus, the context is not modified.
+//
+// The search is bounded by the gas limit of the transaction, or by the gas
+// limit of the block if the transaction does not specify one, and by the gas
+// the sender can afford. The gas used reported by receipts is only a hint,
+// since it may include charges for unused gas, like the 10% charged by Sonic,
+// while gas reserved for nested calls by the 63/64 rule is not included.
+func EstimateGas(
+	processor Processor,
+	blockParameters BlockParameters,
+	transaction Transaction,
+	context TransactionContext,
+) (Gas, error) {
+	hi := transaction.GasLimit
+	if hi == 0 {
+		hi = blockParameters.GasLimit
+	}
+	allowance, err := getGasAllowance(transaction, context)
+	if err != nil {
+		return 0, err
+	}
+	if allowance < hi {
+		hi = allowance
+	}
+	if hi <= 0 {
+		return 0, fmt.Errorf("%w: no gas available", ErrGasRequiredExceedsAllowance)
+	}
+
+	run := func(gasLimit Gas) (Receipt, bool, error) {
+		transaction := transaction
+		transaction.GasLimit = gasLimit
+		snapshot := context.CreateSnapshot()
+		defer context.RestoreSnapshot(snapshot)
+		receipt, err := processor.Run(blockParameters, transaction, context)
+		if errors.Is(err, ErrIntrinsicGas) || errors.Is(err, ErrFloorDataGas) {
+			return receipt, false, nil
+		}
+		if err != nil {
+			return receipt, false, err
+		}
+		return receipt, receipt.Success, nil
+	}
+
+	receipt, success, err := run(hi)
+	if err != nil {
+		return 0, err
+	}
+	if !success {
+		return 0, fmt.Errorf("%w (%d)", ErrGasRequiredExceedsAllowance, hi)
+	}
+
+	// Processors charging a share s of the unused gas report gas used of
+	// u + s*(hi-u) for an execution consuming u units of gas. For s of at most
+	// 1/10, u is thus at least (10*used-hi)/9, which is a lower bound for the
+	// gas limit required by the transaction.
+	lo := max((10*receipt.GasUsed-hi)/9-1, 0)
+
+	// Gas reserved by the 63/64 rule is not consumed by nested calls. Thus,
+	// transactions frequently succeed with a limit slightly above the used gas.
+	if optimistic := (receipt.GasUsed + callStipend) * 64 / 63; lo < optimistic && optimistic < hi {
+		_, success, err := run(optimistic)
+		if err != nil {
+			return 0, err
+		}
+		if success {
+			hi = optimistic
+		} else {
+			lo = optimistic
+		}
+	}
+
+	// Invariant: the transaction fails with lo and succeeds with hi.
+	for lo+1 < hi {
+		mid := lo + (hi-lo)/2
+		_, success, err := run(mid)
+		if err != nil {
+			return 0, err
+		}
+		if success {
+			hi = mid
+		} else {
+			lo = mid
+		}
+	}
+	return hi, nil
+}
+
+// getGasAllowance computes the maximum amount of gas the sender of the given
+// transaction can pay for after transferring the value of the transaction.
+func getGasAllowance(transaction Transaction, context TransactionContext) (Gas, error) {
+	feeCap := transaction.GetGasFeeCap()
+	if feeCap == (Value{}) {
+		return Gas(maxGas), nil
+	}
+	balance := context.GetBalance(transaction.Sender)
+	if balance.Cmp(transaction.Value) < 0 {
+		return 0, fmt.Errorf("%w: address %v have %v want %v", ErrInsufficientFunds, transaction.Sender, balance, transaction.Value)
+	}
+	available := Sub(balance, transaction.Value).ToUint256()
+	allowance := new(uint256.Int).Div(available, feeCap.ToUint256())
+	if !allowance.IsUint64() || allowance.Uint64() > maxGas {
+		return Gas(maxGas), nil
+	}
+	return Gas(allowance.Uint64()), nil
+}
+
+// maxGas is the largest gas limit considered by the gas estimation.
+const maxGas = 1<<63 - 1
@@ -0,0 +1,166 @@
+// Copyright (c) 2024 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package tosca
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"go.uber.org/mock/gomock"
+)
+
+// expectGasConsumption configures the given processor to succeed for gas limits
+// of at least required gas, consuming the given amount of gas. If requested,
+// 10% of the unused gas is charged in addition, as done by Sonic.
+func expectGasConsumption(processor *MockProcessor, required, consumed Gas, sonicCharge bool) {
+	processor.EXPECT().Run(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ BlockParameters, transaction Transaction, context TransactionContext) (Receipt, error) {
+			if transaction.GasLimit < 21_000 {
+				return Receipt{}, ErrIntrinsicGas
+			}
+			context.SetNonce(transaction.Sender, context.GetNonce(transaction.Sender)+1)
+			if transaction.GasLimit < required {
+				return Receipt{GasUsed: transaction.GasLimit}, nil
+			}
+			gasUsed := consumed
+			if sonicCharge {
+				gasUsed += (transaction.GasLimit - consumed) / 10
+			}
+			return Receipt{Success: true, GasUsed: gasUsed}, nil
+		}).AnyTimes()
+}
+
+func TestEstimateGas_FindsMinimalGasLimit(t *testing.T) {
+	tests := []struct {
+		required, consumed Gas
+	}{
+		{21_000, 21_000},
+		{50_000, 50_000},
+		{50_000, 40_000}, // < gas reserved for nested calls is not consumed
+		{50_000, 21_000},
+		{9_999_999, 9_999_999},
+	}
+	for _, test := range tests {
+		for _, sonicCharge := range []bool{false, true} {
+			t.Run(fmt.Sprintf("%d/%d/%t", test.required, test.consumed, sonicCharge), func(t *testing.T) {
+				ctrl := gomock.NewController(t)
+				processor := NewMockProcessor(ctrl)
+				expectGasConsumption(processor, test.required, test.consumed, sonicCharge)
+
+				context := NewInMemoryTransactionContext(R13_Cancun, nil)
+				block := BlockParameters{GasLimit: 10_000_000}
+				got, err := EstimateGas(processor, block, Transaction{}, context)
+				if err != nil {
+					t.Fatalf("failed to estimate gas: %v", err)
+				}
+				if want := test.required; want != got {
+					t.Errorf("unexpected gas estimate, wanted %d, got %d", want, got)
+				}
+			})
+		}
+	}
+}
+
+func TestEstimateGas_CoversFloorDataGasSincePrague(t *testing.T) {
+	const floorGas = 40_000
+	ctrl := gomock.NewController(t)
+	processor := NewMockProcessor(ctrl)
+	processor.EXPECT().Run(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ BlockParameters, transaction Transaction, _ TransactionContext) (Receipt, error) {
+			if transaction.GasLimit < 21_000 {
+				return Receipt{}, ErrIntrinsicGas
+			}
+			if transaction.GasLimit < floorGas {
+				return Receipt{}, ErrFloorDataGas
+			}
+			return Receipt{Success: true, GasUsed: floorGas}, nil
+		}).AnyTimes()
+
+	context := NewInMemoryTransactionContext(R14_Prague, nil)
+	block := BlockParameters{GasLimit: 10_000_000, Revision: R14_Prague}
+	got, err := EstimateGas(processor, block, Transaction{}, context)
+	if err != nil {
+		t.Fatalf("failed to estimate gas: %v", err)
+	}
+	if want := Gas(floorGas); want != got {
+		t.Errorf("unexpected gas estimate, wanted %d, got %d", want, got)
+	}
+}
+
+func TestEstimateGas_DoesNotModifyContext(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	processor := NewMockProcessor(ctrl)
+	expectGasConsumption(processor, 30_000, 30_000, true)
+
+	sender := Address{1}
+	context := NewInMemoryTransactionContext(R13_Cancun, map[Address]Account{sender: {Nonce: 4}})
+	transaction := Transaction{Sender: sender, Nonce: 4, GasLimit: 100_000}
+	if _, err := EstimateGas(processor, BlockParameters{}, transaction, context); err != nil {
+		t.Fatalf("failed to estimate gas: %v", err)
+	}
+	if want, got := uint64(4), context.GetNonce(sender); want != got {
+		t.Errorf("context was modified, wanted nonce %d, got %d", want, got)
+	}
+}
+
+func TestEstimateGas_IsLimitedByTransactionGasLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	processor := NewMockProcessor(ctrl)
+	expectGasConsumption(processor, 30_000, 30_000, false)
+
+	context := NewInMemoryTransactionContext(R13_Cancun, nil)
+	block := BlockParameters{GasLimit: 10_000_000}
+	_, err := EstimateGas(processor, block, Transaction{GasLimit: 29_999}, context)
+	if !errors.Is(err, ErrGasRequiredExceedsAllowance) {
+		t.Errorf("unexpected error, wanted %v, got %v", ErrGasRequiredExceedsAllowance, err)
+	}
+}
+
+func TestEstimateGas_IsLimitedByBalanceOfSender(t *testing.T) {
+	sender := Address{1}
+	tests := map[string]struct {
+		balance Value
+		want    error
+	}{
+		"sufficient balance":   {NewValue(2*30_000 + 10), nil},
+		"insufficient balance": {NewValue(2*30_000 + 9), ErrGasRequiredExceedsAllowance},
+		"value not covered":    {NewValue(9), ErrInsufficientFunds},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			processor := NewMockProcessor(ctrl)
+			expectGasConsumption(processor, 30_000, 30_000, false)
+
+			context := NewInMemoryTransactionContext(R13_Cancun, map[Address]Account{sender: {Balance: test.balance}})
+			transaction := Transaction{Sender: sender, GasPrice: NewValue(2), Value: NewValue(10)}
+			block := BlockParameters{GasLimit: 10_000_000}
+			_, err := EstimateGas(processor, block, transaction, context)
+			if !errors.Is(err, test.want) {
+				t.Errorf("unexpected error, wanted %v, got %v", test.want, err)
+			}
+		})
+	}
+}
+
+func TestEstimateGas_ForwardsRejectionsOfTransactions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	processor := NewMockProcessor(ctrl)
+	processor.EXPECT().Run(gomock.Any(), gomock.Any(), gomock.Any()).Return(Receipt{}, ErrNonceTooHigh)
+
+	context := NewInMemoryTransactionContext(R13_Cancun, nil)
+	block := BlockParameters{GasLimit: 10_000_000}
+	_, err := EstimateGas(processor, block, Transaction{}, context)
+	if !errors.Is(err, ErrNonceTooHigh) {
+		t.Errorf("unexpected error, wanted %v, got %v", ErrNonceTooHigh, err)
+	}
+}
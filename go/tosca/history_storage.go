@@ -0,0 +1,57 @@
+// Copyright (c) 2024 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package tosca
+
+import "encoding/binary"
+
+// HistoryStorageAddress is the address of the system contract recording the
+// hashes of recent blocks in its storage since Prague (EIP-2935).
+var HistoryStorageAddress = Address{
+	0x00, 0x00, 0xF9, 0x08, 0x27, 0xF1, 0xC5, 0x3a, 0x10, 0xcb,
+	0x7A, 0x02, 0x33, 0x5B, 0x17, 0x53, 0x20, 0x00, 0x29, 0x35,
+}
+
+// HistoryServeWindow is the number of block hashes retained by the history
+// storage contract. Storage slots are reused in a ring-buffer fashion.
+const HistoryServeWindow = 8191
+
+// RecordParentBlockHash writes the hash of the parent of the given block into
+// the storage of the history storage contract. It is to be called at the
+// start of each block since Prague, before processing its transactions. The
+// hash of the parent is obtained from the given context. Nothing is recorded
+// if the history storage contract has not been deployed.
+func RecordParentBlockHash(block BlockParameters, context TransactionContext) {
+	if block.BlockNumber <= 0 || context.GetCodeSize(HistoryStorageAddress) == 0 {
+		return
+	}
+	parent := block.BlockNumber - 1
+	context.SetStorage(HistoryStorageAddress, getHistoryStorageKey(parent), Word(context.GetBlockHash(parent)))
+}
+
+// GetHistoricalBlockHash looks up the hash of the block with the given number
+// in the history storage contract, as seen while processing the block with
+// the given current number. The result is false if the requested block is not
+// within the served window or if its hash has not been recorded.
+func GetHistoricalBlockHash(state WorldState, current int64, number int64) (Hash, bool) {
+	if number < 0 || number >= current || current-number > HistoryServeWindow {
+		return Hash{}, false
+	}
+	hash := Hash(state.GetStorage(HistoryStorageAddress, getHistoryStorageKey(number)))
+	return hash, hash != (Hash{})
+}
+
+// getHistoryStorageKey returns the storage slot holding the hash of the
+// block with the given number.
+func getHistoryStorageKey(number int64) Key {
+	var key Key
+	binary.BigEndian.PutUint64(key[24:], uint64(number)%HistoryServeWindow)
+	return key
+}
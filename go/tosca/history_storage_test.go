@@ -0,0 +1,99 @@
+// Copyright (c) 2024 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package tosca
+
+import "testing"
+
+// newHistoryStorageContext creates a context in which the history storage
+// contract is deployed.
+func newHistoryStorageContext() *InMemoryTransactionContext {
+	return NewInMemoryTransactionContext(R14_Prague, map[Address]Account{
+		HistoryStorageAddress: {Code: Code{0}},
+	})
+}
+
+func TestRecordParentBlockHash_WritesHashOfParentToHistoryStorage(t *testing.T) {
+	context := newHistoryStorageContext()
+	context.SetBlockHash(41, Hash{0x41})
+
+	RecordParentBlockHash(BlockParameters{BlockNumber: 42}, context)
+
+	if want, got := (Word{0x41}), context.GetStorage(HistoryStorageAddress, Key{31: 41}); want != got {
+		t.Errorf("unexpected recorded hash, wanted %v, got %v", want, got)
+	}
+}
+
+func TestRecordParentBlockHash_IgnoresGenesisBlock(t *testing.T) {
+	context := newHistoryStorageContext()
+	context.SetBlockHash(-1, Hash{0x01})
+	RecordParentBlockHash(BlockParameters{BlockNumber: 0}, context)
+	if got := context.GetStorage(HistoryStorageAddress, getHistoryStorageKey(-1)); got != (Word{}) {
+		t.Errorf("history storage was modified for the genesis block")
+	}
+}
+
+func TestRecordParentBlockHash_IgnoresMissingHistoryStorageContract(t *testing.T) {
+	context := NewInMemoryTransactionContext(R14_Prague, nil)
+	context.SetBlockHash(41, Hash{0x41})
+
+	RecordParentBlockHash(BlockParameters{BlockNumber: 42}, context)
+
+	if context.AccountExists(HistoryStorageAddress) {
+		t.Errorf("history storage was modified without a deployed contract")
+	}
+}
+
+func TestRecordParentBlockHash_ReusesSlotsAfterServeWindow(t *testing.T) {
+	context := newHistoryStorageContext()
+	context.SetBlockHash(5, Hash{0x05})
+	context.SetBlockHash(5+HistoryServeWindow, Hash{0x06})
+
+	RecordParentBlockHash(BlockParameters{BlockNumber: 6}, context)
+	RecordParentBlockHash(BlockParameters{BlockNumber: 6 + HistoryServeWindow}, context)
+
+	if want, got := (Word{0x06}), context.GetStorage(HistoryStorageAddress, Key{31: 5}); want != got {
+		t.Errorf("unexpected recorded hash, wanted %v, got %v", want, got)
+	}
+}
+
+func TestGetHistoricalBlockHash_ServesRecordedHashesWithinWindow(t *testing.T) {
+	const current = 10_000
+	context := newHistoryStorageContext()
+	for _, number := range []int64{current - HistoryServeWindow - 1, current - HistoryServeWindow, current - 1} {
+		context.SetBlockHash(number, Hash{byte(number)})
+		RecordParentBlockHash(BlockParameters{BlockNumber: number + 1}, context)
+	}
+
+	tests := map[string]struct {
+		number int64
+		found  bool
+	}{
+		"parent":            {number: current - 1, found: true},
+		"oldest in window":  {number: current - HistoryServeWindow, found: true},
+		"older than window": {number: current - HistoryServeWindow - 1},
+		"current":           {number: current},
+		"future":            {number: current + 1},
+		"negative":          {number: -1},
+		"not recorded":      {number: current - 2},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			hash, found := GetHistoricalBlockHash(context, current, test.number)
+			if want, got := test.found, found; want != got {
+				t.Fatalf("unexpected lookup result, wanted %t, got %t", want, got)
+			}
+			if want, got := (Hash{byte(test.number)}), hash; found && want != got {
+				t.Errorf("unexpected hash, wanted %v, got %v", want, got)
+			}
+		})
+	}
+}
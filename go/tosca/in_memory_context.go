@@ -0,0 +1,350 @@
+// Copyright (c) 2024 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package tosca
+
+import (
+	"bytes"
+	"maps"
+	"slices"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// Account summarizes the state of a single account of an in-memory world
+// state as used by an InMemoryTransactionContext.
+type Account struct {
+	Balance Value
+	Nonce   uint64
+	Code    Code
+	Storage map[Key]Word
+}
+
+// Clone creates an independent copy of this account.
+func (a Account) Clone() Account {
+	a.Code = bytes.Clone(a.Code)
+	a.Storage = maps.Clone(a.Storage)
+	return a
+}
+
+// InMemoryTransactionContext is a TransactionContext implementation keeping
+// the entire world state in memory. It may be used to run processors end to
+// end without the need for an external state database.
+//
+// All modifications are recorded in a journal, enabling nested snapshots to
+// be restored. Besides the world state, the context tracks all information
+// required within a transaction: original storage values for computing the
+// status of storage updates, transient storage, access lists, logs, and
+// self-destructed accounts. EndTransaction needs to be called to finalize a
+// transaction before the next transaction may be processed on the same state.
+//
+// Accounts created by contract creations in the current transaction are to be
+// reported through CreateContract. Since Cancun (EIP-6780) only those
+// accounts are deleted when self-destructing.
+//
+// InMemoryTransactionContext instances are not thread safe.
+type InMemoryTransactionContext struct {
+	revision    Revision
+	accounts    map[Address]*Account
+	original    map[Address]map[Key]Word // < storage values at the start of the transaction
+	transient   map[Address]map[Key]Word
+	accessed    map[Address]map[Key]struct{}
+	created     map[Address]struct{}
+	destructed  map[Address]struct{}
+	logs        []Log
+	blockHashes map[int64]Hash
+	journal     []func()
+}
+
+// NewInMemoryTransactionContext creates a new context based on a copy of the
+// given accounts. The revision determines the self-destruct semantics.
+func NewInMemoryTransactionContext(revision Revision, accounts map[Address]Account) *InMemoryTransactionContext {
+	res := &InMemoryTransactionContext{
+		revision:    revision,
+		accounts:    make(map[Address]*Account, len(accounts)),
+		blockHashes: map[int64]Hash{},
+	}
+	for address, account := range accounts {
+		account := account.Clone()
+		res.accounts[address] = &account
+	}
+	res.resetTransactionState()
+	return res
+}
+
+// GetAccounts returns a copy of the current state of all accounts.
+func (c *InMemoryTransactionContext) GetAccounts() map[Address]Account {
+	res := make(map[Address]Account, len(c.accounts))
+	for address, account := range c.accounts {
+		res[address] = account.Clone()
+	}
+	return res
+}
+
+// SetBlockHash registers the hash to be reported for the given block number.
+func (c *InMemoryTransactionContext) SetBlockHash(number int64, hash Hash) {
+	c.blockHashes[number] = hash
+}
+
+// EndTransaction finalizes the current transaction. Self-destructed accounts
+// are deleted and all transaction-local information, including logs, access
+// lists, and transient storage, is discarded. Snapshots taken before this
+// call can no longer be restored.
+func (c *InMemoryTransactionContext) EndTransaction() {
+	for address := range c.destructed {
+		_, created := c.created[address]
+		if c.revision < R13_Cancun || created {
+			delete(c.accounts, address)
+		}
+	}
+	c.resetTransactionState()
+}
+
+func (c *InMemoryTransactionContext) resetTransactionState() {
+	c.original = map[Address]map[Key]Word{}
+	c.transient = map[Address]map[Key]Word{}
+	c.accessed = map[Address]map[Key]struct{}{}
+	c.created = map[Address]struct{}{}
+	c.destructed = map[Address]struct{}{}
+	c.logs = nil
+	c.journal = nil
+}
+
+// --- World State ---
+
+func (c *InMemoryTransactionContext) AccountExists(address Address) bool {
+	_, found := c.accounts[address]
+	return found
+}
+
+func (c *InMemoryTransactionContext) GetBalance(address Address) Value {
+	if account, found := c.accounts[address]; found {
+		return account.Balance
+	}
+	return Value{}
+}
+
+func (c *InMemoryTransactionContext) SetBalance(address Address, value Value) {
+	account := c.getOrCreateAccount(address)
+	old := account.Balance
+	account.Balance = value
+	c.record(func() { account.Balance = old })
+}
+
+func (c *InMemoryTransactionContext) GetNonce(address Address) uint64 {
+	if account, found := c.accounts[address]; found {
+		return account.Nonce
+	}
+	return 0
+}
+
+func (c *InMemoryTransactionContext) SetNonce(address Address, nonce uint64) {
+	account := c.getOrCreateAccount(address)
+	old := account.Nonce
+	account.Nonce = nonce
+	c.record(func() { account.Nonce = old })
+}
+
+func (c *InMemoryTransactionContext) GetCode(address Address) Code {
+	if account, found := c.accounts[address]; found {
+		return account.Code
+	}
+	return nil
+}
+
+func (c *InMemoryTransactionContext) GetCodeHash(address Address) Hash {
+	account, found := c.accounts[address]
+	if !found {
+		return Hash{}
+	}
+	hasher := sha3.NewLegacyKeccak256()
+	hasher.Write(account.Code)
+	var hash Hash
+	hasher.Sum(hash[:0])
+	return hash
+}
+
+func (c *InMemoryTransactionContext) GetCodeSize(address Address) int {
+	return len(c.GetCode(address))
+}
+
+func (c *InMemoryTransactionContext) SetCode(address Address, code Code) {
+	account := c.getOrCreateAccount(address)
+	old := account.Code
+	account.Code = bytes.Clone(code)
+	c.record(func() { account.Code = old })
+}
+
+func (c *InMemoryTransactionContext) GetStorage(address Address, key Key) Word {
+	if account, found := c.accounts[address]; found {
+		return account.Storage[key]
+	}
+	return Word{}
+}
+
+func (c *InMemoryTransactionContext) SetStorage(address Address, key Key, value Word) StorageStatus {
+	current := c.GetStorage(address, key)
+	original := c.GetCommittedStorage(address, key)
+	if _, found := c.original[address][key]; !found {
+		if c.original[address] == nil {
+			c.original[address] = map[Key]Word{}
+		}
+		c.original[address][key] = current
+	}
+
+	account := c.getOrCreateAccount(address)
+	if account.Storage == nil {
+		account.Storage = map[Key]Word{}
+	}
+	setWord(account.Storage, key, value)
+	c.record(func() { setWord(account.Storage, key, current) })
+	return GetStorageStatus(original, current, value)
+}
+
+func (c *InMemoryTransactionContext) SelfDestruct(address Address, beneficiary Address) bool {
+	balance := c.GetBalance(address)
+	c.SetBalance(address, Value{})
+	c.SetBalance(beneficiary, Add(c.GetBalance(beneficiary), balance))
+	if _, created := c.created[address]; c.revision < R13_Cancun || created {
+		c.SetBalance(address, Value{})
+	}
+
+	if _, found := c.destructed[address]; found {
+		return false
+	}
+	c.destructed[address] = struct{}{}
+	c.record(func() { delete(c.destructed, address) })
+	return true
+}
+
+// --- Transaction Context ---
+
+// CreateContract marks the given account as created by a contract creation
+// in the current transaction.
+func (c *InMemoryTransactionContext) CreateContract(address Address) {
+	if _, created := c.created[address]; created {
+		return
+	}
+	c.created[address] = struct{}{}
+	c.record(func() { delete(c.created, address) })
+}
+
+func (c *InMemoryTransactionContext) CreateSnapshot() Snapshot {
+	return Snapshot(len(c.journal))
+}
+
+func (c *InMemoryTransactionContext) RestoreSnapshot(snapshot Snapshot) {
+	for len(c.journal) > int(snapshot) {
+		c.journal[len(c.journal)-1]()
+		c.journal = c.journal[:len(c.journal)-1]
+	}
+}
+
+func (c *InMemoryTransactionContext) GetTransientStorage(address Address, key Key) Word {
+	return c.transient[address][key]
+}
+
+func (c *InMemoryTransactionContext) SetTransientStorage(address Address, key Key, value Word) {
+	storage := c.transient[address]
+	if storage == nil {
+		storage = map[Key]Word{}
+		c.transient[address] = storage
+	}
+	old := storage[key]
+	setWord(storage, key, value)
+	c.record(func() { setWord(storage, key, old) })
+}
+
+func (c *InMemoryTransactionContext) AccessAccount(address Address) AccessStatus {
+	if _, found := c.accessed[address]; found {
+		return WarmAccess
+	}
+	c.accessed[address] = map[Key]struct{}{}
+	c.record(func() { delete(c.accessed, address) })
+	return ColdAccess
+}
+
+func (c *InMemoryTransactionContext) AccessStorage(address Address, key Key) AccessStatus {
+	c.AccessAccount(address)
+	keys := c.accessed[address]
+	if _, found := keys[key]; found {
+		return WarmAccess
+	}
+	keys[key] = struct{}{}
+	c.record(func() { delete(keys, key) })
+	return ColdAccess
+}
+
+func (c *InMemoryTransactionContext) EmitLog(log Log) {
+	size := len(c.logs)
+	c.logs = append(c.logs, Log{
+		Address: log.Address,
+		Topics:  slices.Clone(log.Topics),
+		Data:    bytes.Clone(log.Data),
+	})
+	c.record(func() { c.logs = c.logs[:size] })
+}
+
+func (c *InMemoryTransactionContext) GetLogs() []Log {
+	return slices.Clone(c.logs)
+}
+
+func (c *InMemoryTransactionContext) GetBlockHash(number int64) Hash {
+	return c.blockHashes[number]
+}
+
+func (c *InMemoryTransactionContext) GetCommittedStorage(address Address, key Key) Word {
+	if value, found := c.original[address][key]; found {
+		return value
+	}
+	return c.GetStorage(address, key)
+}
+
+func (c *InMemoryTransactionContext) IsAddressInAccessList(address Address) bool {
+	_, found := c.accessed[address]
+	return found
+}
+
+func (c *InMemoryTransactionContext) IsSlotInAccessList(address Address, key Key) (addressPresent, slotPresent bool) {
+	keys, addressPresent := c.accessed[address]
+	_, slotPresent = keys[key]
+	return addressPresent, slotPresent
+}
+
+func (c *InMemoryTransactionContext) HasSelfDestructed(address Address) bool {
+	_, found := c.destructed[address]
+	return found
+}
+
+// getOrCreateAccount returns the account with the given address, creating an
+// empty account if it does not exist yet.
+func (c *InMemoryTransactionContext) getOrCreateAccount(address Address) *Account {
+	if account, found := c.accounts[address]; found {
+		return account
+	}
+	account := &Account{}
+	c.accounts[address] = account
+	c.record(func() { delete(c.accounts, address) })
+	return account
+}
+
+// record adds an undo operation to the journal of this context.
+func (c *InMemoryTransactionContext) record(undo func()) {
+	c.journal = append(c.journal, undo)
+}
+
+// setWord updates the given storage, dropping zero values.
+func setWord(storage map[Key]Word, key Key, value Word) {
+	if value == (Word{}) {
+		delete(storage, key)
+	} else {
+		storage[key] = value
+	}
+}
@@ -0,0 +1,397 @@
+// Copyright (c) 2024 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package tosca
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInMemoryTransactionContext_ImplementsTransactionContext(t *testing.T) {
+	var _ TransactionContext = &InMemoryTransactionContext{}
+}
+
+func TestInMemoryTransactionContext_InitialAccountsAreCopied(t *testing.T) {
+	accounts := map[Address]Account{
+		{1}: {Balance: NewValue(1), Nonce: 2, Code: Code{3}, Storage: map[Key]Word{{4}: {5}}},
+	}
+	context := NewInMemoryTransactionContext(R13_Cancun, accounts)
+	context.SetStorage(Address{1}, Key{4}, Word{6})
+	context.SetCode(Address{1}, Code{7})
+
+	if want, got := (Word{5}), accounts[Address{1}].Storage[Key{4}]; want != got {
+		t.Errorf("initial accounts were modified, wanted %v, got %v", want, got)
+	}
+	if want, got := byte(3), accounts[Address{1}].Code[0]; want != got {
+		t.Errorf("initial accounts were modified, wanted %v, got %v", want, got)
+	}
+}
+
+func TestInMemoryTransactionContext_AccountsCanBeReadAndModified(t *testing.T) {
+	context := NewInMemoryTransactionContext(R13_Cancun, nil)
+	address := Address{1}
+	if context.AccountExists(address) {
+		t.Fatalf("account should not exist")
+	}
+	if want, got := (Hash{}), context.GetCodeHash(address); want != got {
+		t.Errorf("unexpected code hash of missing account, wanted %v, got %v", want, got)
+	}
+
+	context.SetBalance(address, NewValue(1))
+	context.SetNonce(address, 2)
+	context.SetCode(address, Code{3, 4})
+
+	if !context.AccountExists(address) {
+		t.Errorf("account should exist")
+	}
+	if want, got := NewValue(1), context.GetBalance(address); want != got {
+		t.Errorf("unexpected balance, wanted %v, got %v", want, got)
+	}
+	if want, got := uint64(2), context.GetNonce(address); want != got {
+		t.Errorf("unexpected nonce, wanted %v, got %v", want, got)
+	}
+	if want, got := (Code{3, 4}), context.GetCode(address); !reflect.DeepEqual(want, got) {
+		t.Errorf("unexpected code, wanted %v, got %v", want, got)
+	}
+	if want, got := 2, context.GetCodeSize(address); want != got {
+		t.Errorf("unexpected code size, wanted %v, got %v", want, got)
+	}
+
+	empty := Address{2}
+	context.SetBalance(empty, Value{})
+	// keccak256 of the empty code
+	want := Hash{
+		0xc5, 0xd2, 0x46, 0x01, 0x86, 0xf7, 0x23, 0x3c, 0x92, 0x7e, 0x7d, 0xb2, 0xdc, 0xc7, 0x03, 0xc0,
+		0xe5, 0x00, 0xb6, 0x53, 0xca, 0x82, 0x27, 0x3b, 0x7b, 0xfa, 0xd8, 0x04, 0x5d, 0x85, 0xa4, 0x70,
+	}
+	if got := context.GetCodeHash(empty); want != got {
+		t.Errorf("unexpected code hash of empty account, wanted %v, got %v", want, got)
+	}
+}
+
+func TestInMemoryTransactionContext_SetStorageReportsStatusBasedOnOriginalValue(t *testing.T) {
+	address := Address{1}
+	key := Key{2}
+	zero, x, y := Word{}, Word{1}, Word{2}
+
+	tests := []struct {
+		original, current, new Word
+		want                   StorageStatus
+	}{
+		{zero, zero, zero, StorageAssigned},
+		{zero, zero, x, StorageAdded},
+		{x, x, zero, StorageDeleted},
+		{x, x, y, StorageModified},
+		{x, zero, y, StorageDeletedAdded},
+		{x, y, zero, StorageModifiedDeleted},
+		{x, zero, x, StorageDeletedRestored},
+		{zero, y, zero, StorageAddedDeleted},
+		{x, y, x, StorageModifiedRestored},
+	}
+
+	for _, test := range tests {
+		t.Run(test.want.String(), func(t *testing.T) {
+			context := NewInMemoryTransactionContext(R13_Cancun, map[Address]Account{
+				address: {Storage: map[Key]Word{key: test.original}},
+			})
+			context.SetStorage(address, key, test.current)
+			if want, got := test.original, context.GetCommittedStorage(address, key); want != got {
+				t.Errorf("unexpected committed value, wanted %v, got %v", want, got)
+			}
+			if want, got := test.want, context.SetStorage(address, key, test.new); want != got {
+				t.Errorf("unexpected storage status, wanted %v, got %v", want, got)
+			}
+			if want, got := test.new, context.GetStorage(address, key); want != got {
+				t.Errorf("unexpected storage value, wanted %v, got %v", want, got)
+			}
+		})
+	}
+}
+
+func TestInMemoryTransactionContext_EndTransactionCommitsStorage(t *testing.T) {
+	address := Address{1}
+	key := Key{2}
+	context := NewInMemoryTransactionContext(R13_Cancun, nil)
+	context.SetStorage(address, key, Word{3})
+	if want, got := (Word{}), context.GetCommittedStorage(address, key); want != got {
+		t.Errorf("unexpected committed value, wanted %v, got %v", want, got)
+	}
+	context.EndTransaction()
+	if want, got := (Word{3}), context.GetCommittedStorage(address, key); want != got {
+		t.Errorf("unexpected committed value, wanted %v, got %v", want, got)
+	}
+	if want, got := StorageModified, context.SetStorage(address, key, Word{4}); want != got {
+		t.Errorf("unexpected storage status, wanted %v, got %v", want, got)
+	}
+}
+
+func TestInMemoryTransactionContext_NestedSnapshotsCanBeRestored(t *testing.T) {
+	address := Address{1}
+	key := Key{2}
+	context := NewInMemoryTransactionContext(R13_Cancun, nil)
+
+	outer := context.CreateSnapshot()
+	context.SetBalance(address, NewValue(1))
+	context.SetStorage(address, key, Word{1})
+	context.SetTransientStorage(address, key, Word{1})
+	context.AccessStorage(address, key)
+	context.EmitLog(Log{Address: address})
+
+	inner := context.CreateSnapshot()
+	context.SetBalance(address, NewValue(2))
+	context.SetNonce(address, 2)
+	context.SetCode(address, Code{2})
+	context.SetStorage(address, key, Word{2})
+	context.SetTransientStorage(address, key, Word{2})
+	context.AccessAccount(Address{2})
+	context.EmitLog(Log{Address: Address{2}})
+	context.SelfDestruct(address, Address{3})
+
+	context.RestoreSnapshot(inner)
+	if want, got := NewValue(1), context.GetBalance(address); want != got {
+		t.Errorf("unexpected balance, wanted %v, got %v", want, got)
+	}
+	if want, got := uint64(0), context.GetNonce(address); want != got {
+		t.Errorf("unexpected nonce, wanted %v, got %v", want, got)
+	}
+	if want, got := 0, context.GetCodeSize(address); want != got {
+		t.Errorf("unexpected code size, wanted %v, got %v", want, got)
+	}
+	if want, got := (Word{1}), context.GetStorage(address, key); want != got {
+		t.Errorf("unexpected storage, wanted %v, got %v", want, got)
+	}
+	if want, got := (Word{1}), context.GetTransientStorage(address, key); want != got {
+		t.Errorf("unexpected transient storage, wanted %v, got %v", want, got)
+	}
+	if context.IsAddressInAccessList(Address{2}) {
+		t.Errorf("access of account should have been reverted")
+	}
+	if _, slot := context.IsSlotInAccessList(address, key); !slot {
+		t.Errorf("slot access should have been retained")
+	}
+	if want, got := []Log{{Address: address}}, context.GetLogs(); !reflect.DeepEqual(want, got) {
+		t.Errorf("unexpected logs, wanted %v, got %v", want, got)
+	}
+	if context.HasSelfDestructed(address) || context.AccountExists(Address{3}) {
+		t.Errorf("self-destruct should have been reverted")
+	}
+
+	context.RestoreSnapshot(outer)
+	if context.AccountExists(address) {
+		t.Errorf("account creation should have been reverted")
+	}
+	if want, got := (Word{}), context.GetTransientStorage(address, key); want != got {
+		t.Errorf("unexpected transient storage, wanted %v, got %v", want, got)
+	}
+	if address, slot := context.IsSlotInAccessList(address, key); address || slot {
+		t.Errorf("access list should be empty")
+	}
+	if want, got := 0, len(context.GetLogs()); want != got {
+		t.Errorf("unexpected number of logs, wanted %d, got %d", want, got)
+	}
+}
+
+func TestInMemoryTransactionContext_AccessListDistinguishesColdAndWarmAccesses(t *testing.T) {
+	context := NewInMemoryTransactionContext(R13_Cancun, nil)
+	address := Address{1}
+	key := Key{2}
+
+	if want, got := ColdAccess, context.AccessStorage(address, key); want != got {
+		t.Errorf("unexpected access status, wanted %v, got %v", want, got)
+	}
+	if want, got := WarmAccess, context.AccessStorage(address, key); want != got {
+		t.Errorf("unexpected access status, wanted %v, got %v", want, got)
+	}
+	if want, got := WarmAccess, context.AccessAccount(address); want != got {
+		t.Errorf("unexpected access status, wanted %v, got %v", want, got)
+	}
+	if want, got := ColdAccess, context.AccessStorage(address, Key{3}); want != got {
+		t.Errorf("unexpected access status, wanted %v, got %v", want, got)
+	}
+
+	context.EndTransaction()
+	if want, got := ColdAccess, context.AccessAccount(address); want != got {
+		t.Errorf("unexpected access status, wanted %v, got %v", want, got)
+	}
+}
+
+func TestInMemoryTransactionContext_TransientStorageIsResetAtEndOfTransaction(t *testing.T) {
+	context := NewInMemoryTransactionContext(R13_Cancun, nil)
+	context.SetTransientStorage(Address{1}, Key{2}, Word{3})
+	if want, got := (Word{3}), context.GetTransientStorage(Address{1}, Key{2}); want != got {
+		t.Errorf("unexpected transient storage, wanted %v, got %v", want, got)
+	}
+	context.EndTransaction()
+	if want, got := (Word{}), context.GetTransientStorage(Address{1}, Key{2}); want != got {
+		t.Errorf("unexpected transient storage, wanted %v, got %v", want, got)
+	}
+}
+
+func TestInMemoryTransactionContext_LogsAreCopiedAndResetAtEndOfTransaction(t *testing.T) {
+	context := NewInMemoryTransactionContext(R13_Cancun, nil)
+	log := Log{Address: Address{1}, Topics: []Hash{{2}}, Data: Data{3}}
+	context.EmitLog(log)
+	log.Data[0] = 4
+
+	want := []Log{{Address: Address{1}, Topics: []Hash{{2}}, Data: Data{3}}}
+	if got := context.GetLogs(); !reflect.DeepEqual(want, got) {
+		t.Errorf("unexpected logs, wanted %v, got %v", want, got)
+	}
+	context.EndTransaction()
+	if got := context.GetLogs(); len(got) != 0 {
+		t.Errorf("unexpected logs: %v", got)
+	}
+}
+
+func TestInMemoryTransactionContext_SelfDestructTransfersBalanceAndReportsFirstDestruction(t *testing.T) {
+	address := Address{1}
+	beneficiary := Address{2}
+	context := NewInMemoryTransactionContext(R13_Cancun, map[Address]Account{
+		address:     {Balance: NewValue(5), Code: Code{1}},
+		beneficiary: {Balance: NewValue(1)},
+	})
+
+	if !context.SelfDestruct(address, beneficiary) {
+		t.Errorf("first self-destruct should be reported")
+	}
+	if context.SelfDestruct(address, beneficiary) {
+		t.Errorf("second self-destruct should not be reported")
+	}
+	if !context.HasSelfDestructed(address) {
+		t.Errorf("account should be marked as self-destructed")
+	}
+	if want, got := NewValue(0), context.GetBalance(address); want != got {
+		t.Errorf("unexpected balance, wanted %v, got %v", want, got)
+	}
+	if want, got := NewValue(6), context.GetBalance(beneficiary); want != got {
+		t.Errorf("unexpected balance, wanted %v, got %v", want, got)
+	}
+}
+
+func TestInMemoryTransactionContext_SelfDestructDeletesAccountsDependingOnRevision(t *testing.T) {
+	tests := map[string]struct {
+		revision      Revision
+		createdInTx   bool
+		wantedDeleted bool
+	}{
+		"shanghai-existing": {R12_Shanghai, false, true},
+		"shanghai-created":  {R12_Shanghai, true, true},
+		"cancun-existing":   {R13_Cancun, false, false},
+		"cancun-created":    {R13_Cancun, true, true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			address := Address{1}
+			accounts := map[Address]Account{}
+			if !test.createdInTx {
+				accounts[address] = Account{Nonce: 1, Code: Code{1}}
+			}
+			context := NewInMemoryTransactionContext(test.revision, accounts)
+			if test.createdInTx {
+				context.CreateContract(address)
+				context.SetNonce(address, 1)
+			}
+			context.SelfDestruct(address, Address{2})
+			context.EndTransaction()
+
+			if want, got := !test.wantedDeleted, context.AccountExists(address); want != got {
+				t.Errorf("unexpected account existence, wanted %t, got %t", want, got)
+			}
+			if context.HasSelfDestructed(address) {
+				t.Errorf("self-destruct markers should be reset at the end of the transaction")
+			}
+		})
+	}
+}
+
+// createAndSelfDestruct creates a contract at the given address through the
+// given context and self-destructs it within the same transaction. The result
+// is true if the contract got deleted at the end of the transaction in the
+// given underlying context.
+func createAndSelfDestruct(context TransactionContext, inner *InMemoryTransactionContext, address Address) bool {
+	context.CreateContract(address)
+	context.SetNonce(address, 1)
+	context.SetCode(address, Code{0x00})
+	context.SelfDestruct(address, Address{0xbe})
+	inner.EndTransaction()
+	return !inner.AccountExists(address)
+}
+
+func TestInMemoryTransactionContext_SettingNonceOfNewAccountDoesNotMarkItAsCreated(t *testing.T) {
+	address := Address{1}
+	context := NewInMemoryTransactionContext(R13_Cancun, nil)
+	context.SetNonce(address, 1)
+	context.SelfDestruct(address, Address{2})
+	context.EndTransaction()
+
+	if !context.AccountExists(address) {
+		t.Errorf("account not created by a contract creation was deleted")
+	}
+}
+
+func TestInMemoryTransactionContext_RestoredContractCreationIsForgotten(t *testing.T) {
+	address := Address{1}
+	context := NewInMemoryTransactionContext(R13_Cancun, map[Address]Account{
+		address: {Balance: NewValue(1)},
+	})
+	snapshot := context.CreateSnapshot()
+	context.CreateContract(address)
+	context.RestoreSnapshot(snapshot)
+	context.SelfDestruct(address, Address{2})
+	context.EndTransaction()
+
+	if !context.AccountExists(address) {
+		t.Errorf("account of reverted contract creation was deleted")
+	}
+}
+
+func TestInMemoryTransactionContext_SelfDestructToItselfKeepsBalanceOnlyForExistingAccountsSinceCancun(t *testing.T) {
+	address := Address{1}
+	for _, revision := range []Revision{R12_Shanghai, R13_Cancun} {
+		context := NewInMemoryTransactionContext(revision, map[Address]Account{
+			address: {Balance: NewValue(5), Nonce: 1, Code: Code{1}},
+		})
+		context.SelfDestruct(address, address)
+		want := NewValue(0)
+		if revision >= R13_Cancun {
+			want = NewValue(5)
+		}
+		if got := context.GetBalance(address); want != got {
+			t.Errorf("unexpected balance in %v, wanted %v, got %v", revision, want, got)
+		}
+	}
+}
+
+func TestInMemoryTransactionContext_GetBlockHashReturnsRegisteredHashes(t *testing.T) {
+	context := NewInMemoryTransactionContext(R13_Cancun, nil)
+	context.SetBlockHash(12, Hash{1})
+	if want, got := (Hash{1}), context.GetBlockHash(12); want != got {
+		t.Errorf("unexpected block hash, wanted %v, got %v", want, got)
+	}
+	if want, got := (Hash{}), context.GetBlockHash(13); want != got {
+		t.Errorf("unexpected block hash, wanted %v, got %v", want, got)
+	}
+}
+
+func TestInMemoryTransactionContext_GetAccountsReturnsIndependentCopy(t *testing.T) {
+	context := NewInMemoryTransactionContext(R13_Cancun, nil)
+	context.SetStorage(Address{1}, Key{2}, Word{3})
+	accounts := context.GetAccounts()
+	want := map[Address]Account{{1}: {Storage: map[Key]Word{{2}: {3}}}}
+	if !reflect.DeepEqual(want, accounts) {
+		t.Errorf("unexpected accounts, wanted %v, got %v", want, accounts)
+	}
+	accounts[Address{1}].Storage[Key{2}] = Word{4}
+	if want, got := (Word{3}), context.GetStorage(Address{1}, Key{2}); want != got {
+		t.Errorf("context was modified through returned accounts, wanted %v, got %v", want, got)
+	}
+}
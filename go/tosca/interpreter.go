@@ -47,6 +47,7 @@ type Parameters struct {
 	Value     Value
 	CodeHash  *Hash
 	Code      Code
+	Tracer    Tracer // < optional, nil if no tracing is requested
 }
 
 // BlockParameters contains information about the current block.
@@ -101,6 +102,12 @@ type TransactionContext interface {
 	// GetBlockHash returns the hash of the block with the given number.
 	GetBlockHash(number int64) Hash
 
+	// CreateContract marks the given account as being created by a contract
+	// creation in the current transaction, as required for the self-destruct
+	// semantics of EIP-6780. Processors report each created contract before
+	// initializing its nonce.
+	CreateContract(Address)
+
 	// -- legacy API needed by LFVM and Geth, to be removed in the future ---
 
 	// Deprecated: should not be needed when using result of SetStorage(..)
@@ -124,10 +131,11 @@ const (
 
 // Result summarizes the result of a EVM code computation.
 type Result struct {
-	Success   bool // false if the execution ended in a revert, true otherwise
-	Output    Data
-	GasLeft   Gas
-	GasRefund Gas
+	Success      bool // false if the execution ended in a revert, true otherwise
+	Output       Data
+	GasLeft      Gas
+	GasRefund    Gas
+	FailureCause FailureCause // < the reason of an unsuccessful execution, FailureNone if unknown
 }
 
 // Data represents the input or output of contract invocations.
@@ -175,10 +183,29 @@ type CallResult struct {
 	Output         Data
 	GasLeft        Gas
 	GasRefund      Gas
-	CreatedAddress Address // < only meaningful for CREATE and CREATE2
-	Success        bool    // false if the execution ended in a revert, true otherwise
+	CreatedAddress Address      // < only meaningful for CREATE and CREATE2
+	Success        bool         // false if the execution ended in a revert, true otherwise
+	FailureCause   FailureCause // < the reason of an unsuccessful call, FailureNone if unknown
+	RevertReason   string       // < the decoded reason of a revert, if available
 }
 
+// FailureCause is an enum describing why the execution of a call or a
+// transaction was not successful.
+type FailureCause int
+
+const (
+	FailureNone            FailureCause = iota // < the execution succeeded or the cause is unknown
+	FailureRevert                              // < the code ended with a REVERT
+	FailureOutOfGas                            // < the provided gas was not sufficient
+	FailureInvalidOpCode                       // < an undefined or disabled instruction was encountered
+	FailureInvalidJump                         // < a jump targeted an invalid destination
+	FailureStackViolation                      // < the stack was underflowing or overflowing
+	FailureStaticViolation                     // < the state was to be modified in a static call
+	FailureCodeSizeLimit                       // < the code or init code exceeded its size limit
+	FailurePrecompile                          // < a precompiled contract rejected its input
+	FailureOther                               // < any other reason, e.g. an exceeded call depth
+)
+
 // Revision is an enumeration for EVM specification revisions (aka. Hard-Forks).
 type Revision int
 
@@ -190,6 +217,7 @@ const (
 	R11_Paris
 	R12_Shanghai
 	R13_Cancun
+	R14_Prague
 	numRevisions int = iota
 )
 
@@ -214,9 +242,9 @@ type ProfilingInterpreter interface {
 	// Interpreter in parallel.
 	ResetProfile()
 
-	// DumpProfile prints a snapshot of the profiling data collected since the
-	// last reset to stdout. In the future this interface will be changed to
-	// return the result instead of printing it.
-	// TODO: produce the result as a string
-	DumpProfile()
+	// GetProfile returns a snapshot of the profiling data collected since
+	// the last reset. The result is independent of the internal state of the
+	// Interpreter and is not affected by subsequent executions. It should
+	// not be called while running operations on the Interpreter in parallel.
+	GetProfile() Profile
 }
@@ -143,6 +143,18 @@ func (mr *MockRunContextMockRecorder) Call(kind, parameter any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Call", reflect.TypeOf((*MockRunContext)(nil).Call), kind, parameter)
 }
 
+// CreateContract mocks base method.
+func (m *MockRunContext) CreateContract(arg0 Address) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "CreateContract", arg0)
+}
+
+// CreateContract indicates an expected call of CreateContract.
+func (mr *MockRunContextMockRecorder) CreateContract(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateContract", reflect.TypeOf((*MockRunContext)(nil).CreateContract), arg0)
+}
+
 // CreateSnapshot mocks base method.
 func (m *MockRunContext) CreateSnapshot() Snapshot {
 	m.ctrl.T.Helper()
@@ -505,6 +517,18 @@ func (mr *MockTransactionContextMockRecorder) AccountExists(arg0 any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccountExists", reflect.TypeOf((*MockTransactionContext)(nil).AccountExists), arg0)
 }
 
+// CreateContract mocks base method.
+func (m *MockTransactionContext) CreateContract(arg0 Address) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "CreateContract", arg0)
+}
+
+// CreateContract indicates an expected call of CreateContract.
+func (mr *MockTransactionContextMockRecorder) CreateContract(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateContract", reflect.TypeOf((*MockTransactionContext)(nil).CreateContract), arg0)
+}
+
 // CreateSnapshot mocks base method.
 func (m *MockTransactionContext) CreateSnapshot() Snapshot {
 	m.ctrl.T.Helper()
@@ -825,16 +849,18 @@ func (m *MockProfilingInterpreter) EXPECT() *MockProfilingInterpreterMockRecorde
 	return m.recorder
 }
 
-// DumpProfile mocks base method.
-func (m *MockProfilingInterpreter) DumpProfile() {
+// GetProfile mocks base method.
+func (m *MockProfilingInterpreter) GetProfile() Profile {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "DumpProfile")
+	ret := m.ctrl.Call(m, "GetProfile")
+	ret0, _ := ret[0].(Profile)
+	return ret0
 }
 
-// DumpProfile indicates an expected call of DumpProfile.
-func (mr *MockProfilingInterpreterMockRecorder) DumpProfile() *gomock.Call {
+// GetProfile indicates an expected call of GetProfile.
+func (mr *MockProfilingInterpreterMockRecorder) GetProfile() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DumpProfile", reflect.TypeOf((*MockProfilingInterpreter)(nil).DumpProfile))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProfile", reflect.TypeOf((*MockProfilingInterpreter)(nil).GetProfile))
 }
 
 // ResetProfile mocks base method.
@@ -69,6 +69,32 @@ func GetInterpreterFactory(name string) InterpreterFactory {
 	return interpreterRegistry[strings.ToLower(name)]
 }
 
+// GetInterpreterInfo performs a lookup for the given name (case-insensitive)
+// in the registry and returns the capabilities of the registered
+// implementation. The second result is false if no factory was registered
+// under the given name.
+func GetInterpreterInfo(name string) (InterpreterInfo, bool) {
+	interpreterRegistryLock.Lock()
+	defer interpreterRegistryLock.Unlock()
+	key := strings.ToLower(name)
+	if _, found := interpreterRegistry[key]; !found {
+		return InterpreterInfo{}, false
+	}
+	return interpreterInfos[key], true
+}
+
+// GetAllRegisteredInterpreterInfos obtains the capabilities of all registered
+// implementations.
+func GetAllRegisteredInterpreterInfos() map[string]InterpreterInfo {
+	interpreterRegistryLock.Lock()
+	defer interpreterRegistryLock.Unlock()
+	res := make(map[string]InterpreterInfo, len(interpreterRegistry))
+	for name := range interpreterRegistry {
+		res[name] = interpreterInfos[name]
+	}
+	return res
+}
+
 // GetAllRegisteredInterpreters obtains all registered implementations.
 func GetAllRegisteredInterpreters() map[string]InterpreterFactory {
 	interpreterRegistryLock.Lock()
@@ -95,19 +121,26 @@ func RegisterInterpreter(name string, impl Interpreter) {
 // RegisterInterpreterFactory registers a new Interpreter implementation
 // to be exported for general use in the binary. The name is not case-sensitive,
 // and a panic is triggered if a factory was bound to the same name before, or
-// the factory is nil. This function is mainly intended to be used by package
-// initialization code.
-func RegisterInterpreterFactory(name string, factory InterpreterFactory) error {
+// the factory is nil. Optionally, the capabilities of the implementation may
+// be provided. If omitted, the zero value of InterpreterInfo is recorded.
+// This function is mainly intended to be used by package initialization code.
+func RegisterInterpreterFactory(name string, factory InterpreterFactory, info ...InterpreterInfo) error {
 	key := strings.ToLower(name)
 	if factory == nil {
 		return fmt.Errorf("invalid initialization: cannot register nil-factory using `%s`", key)
 	}
+	if len(info) > 1 {
+		return fmt.Errorf("invalid initialization: multiple infos provided for `%s`", key)
+	}
 	interpreterRegistryLock.Lock()
 	defer interpreterRegistryLock.Unlock()
 	if _, found := interpreterRegistry[key]; found {
 		return fmt.Errorf("invalid initialization: multiple factories registered for `%s`", key)
 	}
 	interpreterRegistry[key] = factory
+	if len(info) > 0 {
+		interpreterInfos[key] = info[0]
+	}
 	return nil
 }
 
@@ -116,8 +149,8 @@ func RegisterInterpreterFactory(name string, factory InterpreterFactory) error {
 // This function panics if the registration fails. This function is intended to
 // be used exclusively in package initialization code, where error handling is
 // limited.
-func MustRegisterInterpreterFactory(name string, factory InterpreterFactory) {
-	if err := RegisterInterpreterFactory(name, factory); err != nil {
+func MustRegisterInterpreterFactory(name string, factory InterpreterFactory, info ...InterpreterInfo) {
+	if err := RegisterInterpreterFactory(name, factory, info...); err != nil {
 		panic(fmt.Errorf("failed to register interpreter factory: %s", err))
 	}
 }
@@ -126,9 +159,40 @@ func MustRegisterInterpreterFactory(name string, factory InterpreterFactory) {
 // using a interpreter specific configuration.
 type InterpreterFactory func(config any) (Interpreter, error)
 
+// InterpreterInfo describes the capabilities of a registered Interpreter
+// implementation. It enables clients to select implementations based on the
+// features they need instead of hard-coding implementation names.
+type InterpreterInfo struct {
+	// OldestSupportedRevision and NewestSupportedRevision define the range
+	// of revisions supported by the implementation, both inclusive.
+	OldestSupportedRevision Revision
+	NewestSupportedRevision Revision
+	// Steppable is true if the implementation supports the step-wise
+	// execution of code required for conformance testing.
+	Steppable bool
+	// Profiling is true if instances implement the ProfilingInterpreter
+	// interface and collect profiling data.
+	Profiling bool
+	// Tracing is true if instances report executed instructions to the
+	// tracer provided through the run parameters.
+	Tracing bool
+	// NativeDependency names the native library required by the
+	// implementation. It is empty for pure Go implementations.
+	NativeDependency string
+}
+
+// SupportsRevision returns true if the given revision is within the range of
+// revisions supported by the described implementation.
+func (i InterpreterInfo) SupportsRevision(revision Revision) bool {
+	return i.OldestSupportedRevision <= revision && revision <= i.NewestSupportedRevision
+}
+
 // interpreterRegistry is a global registry for Interpreter factories of
 // different implementations and configurations.
 var interpreterRegistry = map[string]InterpreterFactory{}
 
+// interpreterInfos records the capabilities of the registered implementations.
+var interpreterInfos = map[string]InterpreterInfo{}
+
 // interpreterRegistryLock to protect access to the registry.
 var interpreterRegistryLock sync.Mutex
@@ -31,3 +31,77 @@ func TestInterpreterRegistry_NilFactoriesAreRejected(t *testing.T) {
 		t.Fatalf("expected error, got nil")
 	}
 }
+
+func TestInterpreterRegistry_InfoOfRegisteredFactoryCanBeRetrieved(t *testing.T) {
+	const name = "something-with-info"
+	factory := func(any) (Interpreter, error) {
+		return nil, nil
+	}
+	info := InterpreterInfo{
+		NewestSupportedRevision: R13_Cancun,
+		Steppable:               true,
+		Profiling:               true,
+		Tracing:                 true,
+		NativeDependency:        "libsomething.so",
+	}
+	if err := RegisterInterpreterFactory(name, factory, info); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, found := GetInterpreterInfo(name)
+	if !found {
+		t.Fatalf("info of %v not found", name)
+	}
+	if got != info {
+		t.Errorf("unexpected info, wanted %v, got %v", info, got)
+	}
+	if got := GetAllRegisteredInterpreterInfos()[name]; got != info {
+		t.Errorf("unexpected info in list of all infos, wanted %v, got %v", info, got)
+	}
+}
+
+func TestInterpreterRegistry_InfoDefaultsToZeroValue(t *testing.T) {
+	const name = "something-without-info"
+	factory := func(any) (Interpreter, error) {
+		return nil, nil
+	}
+	if err := RegisterInterpreterFactory(name, factory); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, found := GetInterpreterInfo(name)
+	if !found {
+		t.Fatalf("info of %v not found", name)
+	}
+	if got != (InterpreterInfo{}) {
+		t.Errorf("unexpected info, got %v", got)
+	}
+}
+
+func TestInterpreterRegistry_GetInterpreterInfoReportsUnknownInterpreters(t *testing.T) {
+	if _, found := GetInterpreterInfo("something odd"); found {
+		t.Errorf("expected no info for unknown interpreter")
+	}
+}
+
+func TestInterpreterRegistry_MultipleInfosAreRejected(t *testing.T) {
+	factory := func(any) (Interpreter, error) {
+		return nil, nil
+	}
+	err := RegisterInterpreterFactory("something-with-two-infos", factory, InterpreterInfo{}, InterpreterInfo{})
+	if err == nil {
+		t.Fatalf("expected error, got nil")
+	}
+}
+
+func TestInterpreterInfo_SupportsRevision(t *testing.T) {
+	info := InterpreterInfo{
+		OldestSupportedRevision: R09_Berlin,
+		NewestSupportedRevision: R12_Shanghai,
+	}
+	for _, revision := range GetAllKnownRevisions() {
+		want := R09_Berlin <= revision && revision <= R12_Shanghai
+		if got := info.SupportsRevision(revision); want != got {
+			t.Errorf("unexpected support for %v, wanted %t, got %t", revision, want, got)
+		}
+	}
+}
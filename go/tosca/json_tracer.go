@@ -0,0 +1,198 @@
+// Copyright (c) 2024 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package tosca
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/Fantom-foundation/Tosca/go/tosca/vm"
+)
+
+// JsonTracer is a Tracer writing a trace of all executed instructions in the
+// standard JSON format defined by EIP-3155 to an io.Writer. Each instruction
+// is written as a single line, followed by a summary line at the end of the
+// outermost call frame. Thus, the produced traces can be compared with traces
+// of other EVM implementations using standard tooling.
+//
+// The gas cost of an instruction is derived from the gas available before the
+// following instruction of the same call frame. For instructions starting a
+// nested call, the cost covers all gas not passed to the callee, excluding the
+// call stipend. Since costs are only known after the execution of an
+// instruction, lines are written with a delay of one instruction. Thus, the
+// outermost call frame must be reported through OnEnter and OnExit for the
+// trace to be complete.
+//
+// A JsonTracer is not thread-safe and must not be used for concurrent
+// executions.
+type JsonTracer struct {
+	writer  io.Writer
+	err     error     // < the first error reported by the writer
+	frames  []Gas     // < the gas provided to the active call frames
+	pending *jsonStep // < the last instruction not written yet, nil if none
+}
+
+// NewJsonTracer creates a tracer writing EIP-3155 traces to the given writer.
+func NewJsonTracer(writer io.Writer) *JsonTracer {
+	return &JsonTracer{writer: writer}
+}
+
+// Err returns the first error reported by the writer, or nil if all lines
+// have been written successfully. After an error, no further lines are
+// written.
+func (t *JsonTracer) Err() error {
+	return t.err
+}
+
+// jsonStep is the representation of a single instruction in EIP-3155 traces.
+type jsonStep struct {
+	Pc      uint64    `json:"pc"`
+	Op      vm.OpCode `json:"op"`
+	Gas     string    `json:"gas"`
+	GasCost string    `json:"gasCost"`
+	MemSize int       `json:"memSize"`
+	Stack   []string  `json:"stack"`
+	Depth   int       `json:"depth"`
+	Refund  uint64    `json:"refund"`
+	OpName  string    `json:"opName"`
+	Error   string    `json:"error,omitempty"`
+
+	gas Gas // < the gas available before the instruction
+}
+
+// jsonSummary is the representation of the summary line in EIP-3155 traces.
+type jsonSummary struct {
+	Output  string `json:"output"`
+	GasUsed string `json:"gasUsed"`
+	Pass    bool   `json:"pass"`
+	Error   string `json:"error,omitempty"`
+}
+
+func (t *JsonTracer) OnStep(info StepInfo) {
+	if t.pending != nil {
+		var cost Gas
+		if t.pending.Depth == info.Depth+1 {
+			cost = t.pending.gas - info.Gas
+		}
+		t.writeStep(cost, "")
+	}
+
+	stack := make([]string, len(info.Stack))
+	for i, value := range info.Stack {
+		stack[i] = formatQuantity(value[:])
+	}
+	t.pending = &jsonStep{
+		Pc:      uint64(info.Pc),
+		Op:      info.Op,
+		Gas:     fmt.Sprintf("0x%x", uint64(info.Gas)),
+		MemSize: len(info.Memory),
+		Stack:   stack,
+		Depth:   info.Depth + 1, // < EIP-3155 depths start at 1
+		Refund:  uint64(info.Refund),
+		OpName:  info.Op.String(),
+		gas:     info.Gas,
+	}
+}
+
+func (t *JsonTracer) OnEnter(depth int, kind CallKind, parameters CallParameters) {
+	if t.pending != nil && t.pending.Depth <= depth {
+		// The stipend granted to value transfers is not paid by the caller.
+		gas := parameters.Gas
+		if (kind == Call || kind == CallCode) && parameters.Value != (Value{}) {
+			gas -= min(gas, callStipend)
+		}
+		t.writeStep(t.pending.gas-gas, "")
+	}
+	t.frames = append(t.frames, parameters.Gas)
+}
+
+func (t *JsonTracer) OnExit(depth int, result CallResult, err error) {
+	failure := ""
+	if err != nil {
+		failure = err.Error()
+	} else if !result.Success {
+		cause := result.FailureCause
+		if cause == FailureNone {
+			cause = FailureOther
+			if t.pending != nil && t.pending.Op == vm.REVERT {
+				cause = FailureRevert
+			}
+		}
+		failure = cause.String()
+	}
+
+	if t.pending != nil && t.pending.Depth == depth+1 {
+		t.writeStep(t.pending.gas-min(t.pending.gas, result.GasLeft), failure)
+	}
+
+	if len(t.frames) == 0 {
+		return
+	}
+	gas := t.frames[len(t.frames)-1]
+	t.frames = t.frames[:len(t.frames)-1]
+	if len(t.frames) > 0 {
+		return
+	}
+	t.write(jsonSummary{
+		Output:  fmt.Sprintf("%x", []byte(result.Output)),
+		GasUsed: fmt.Sprintf("0x%x", uint64(gas-min(gas, result.GasLeft))),
+		Pass:    err == nil && result.Success,
+		Error:   failure,
+	})
+}
+
+func (t *JsonTracer) OnStorageChange(Address, Key, Word, Word) {}
+
+func (t *JsonTracer) OnBalanceChange(Address, Value, Value) {}
+
+func (t *JsonTracer) OnLog(Log) {}
+
+// writeStep writes the pending instruction with the given cost and error.
+func (t *JsonTracer) writeStep(cost Gas, failure string) {
+	step := t.pending
+	t.pending = nil
+	step.GasCost = fmt.Sprintf("0x%x", uint64(cost))
+	step.Error = failure
+	t.write(step)
+}
+
+// write writes the JSON encoding of the given line to the writer, unless a
+// previous write failed.
+func (t *JsonTracer) write(line any) {
+	if t.err != nil {
+		return
+	}
+	data, err := json.Marshal(line)
+	if err != nil {
+		t.err = err
+		return
+	}
+	if _, err := t.writer.Write(append(data, '\n')); err != nil {
+		t.err = err
+	}
+}
+
+// formatQuantity formats the given big-endian integer as a hex number
+// without leading zeros, as used for quantities in JSON-RPC.
+func formatQuantity(data []byte) string {
+	for len(data) > 0 && data[0] == 0 {
+		data = data[1:]
+	}
+	if len(data) == 0 {
+		return "0x0"
+	}
+	res := fmt.Sprintf("%x", data)
+	if res[0] == '0' {
+		res = res[1:]
+	}
+	return "0x" + res
+}
@@ -0,0 +1,164 @@
+// Copyright (c) 2024 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package tosca
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Fantom-foundation/Tosca/go/tosca/vm"
+)
+
+func TestJsonTracer_ImplementsTracer(t *testing.T) {
+	var _ Tracer = &JsonTracer{}
+}
+
+func TestJsonTracer_WritesStepsAndSummary(t *testing.T) {
+	log := &bytes.Buffer{}
+	tracer := NewJsonTracer(log)
+
+	tracer.OnEnter(0, Call, CallParameters{Gas: 100})
+	tracer.OnStep(StepInfo{Pc: 0, Op: vm.PUSH1, Gas: 100, Stack: []Word{}})
+	tracer.OnStep(StepInfo{Pc: 2, Op: vm.MSTORE, Gas: 97, Refund: 5, Stack: []Word{{31: 0x10}, {30: 1}}})
+	tracer.OnStep(StepInfo{Pc: 3, Op: vm.RETURN, Gas: 91, Memory: make(Data, 32), Stack: []Word{}})
+	tracer.OnExit(0, CallResult{Output: Data{0xab}, GasLeft: 90, Success: true}, nil)
+
+	want := []string{
+		`{"pc":0,"op":96,"gas":"0x64","gasCost":"0x3","memSize":0,"stack":[],"depth":1,"refund":0,"opName":"PUSH1"}`,
+		`{"pc":2,"op":82,"gas":"0x61","gasCost":"0x6","memSize":0,"stack":["0x10","0x100"],"depth":1,"refund":5,"opName":"MSTORE"}`,
+		`{"pc":3,"op":243,"gas":"0x5b","gasCost":"0x1","memSize":32,"stack":[],"depth":1,"refund":0,"opName":"RETURN"}`,
+		`{"output":"ab","gasUsed":"0xa","pass":true}`,
+	}
+	if got := strings.Split(strings.TrimSpace(log.String()), "\n"); strings.Join(want, "\n") != strings.Join(got, "\n") {
+		t.Errorf("unexpected trace\nwanted %v\n   got %v", want, got)
+	}
+}
+
+func TestJsonTracer_WritesStepsOfNestedCallsInOrder(t *testing.T) {
+	log := &bytes.Buffer{}
+	tracer := NewJsonTracer(log)
+
+	tracer.OnEnter(0, Call, CallParameters{Gas: 10_000})
+	tracer.OnStep(StepInfo{Pc: 7, Op: vm.CALL, Gas: 10_000})
+	tracer.OnEnter(1, Call, CallParameters{Gas: 3_300, Value: NewValue(1)})
+	tracer.OnStep(StepInfo{Depth: 1, Pc: 0, Op: vm.STOP, Gas: 3_300})
+	tracer.OnExit(1, CallResult{GasLeft: 3_300, Success: true}, nil)
+	tracer.OnStep(StepInfo{Pc: 8, Op: vm.STOP, Gas: 9_000})
+	tracer.OnExit(0, CallResult{GasLeft: 9_000, Success: true}, nil)
+
+	lines := strings.Split(strings.TrimSpace(log.String()), "\n")
+	if want, got := 4, len(lines); want != got {
+		t.Fatalf("unexpected number of lines, wanted %d, got %d", want, got)
+	}
+	wantParts := []string{
+		// The stipend of 2300 is not paid by the caller.
+		`"pc":7,"op":241,"gas":"0x2710","gasCost":"0x2328"`,
+		`"pc":0,"op":0,"gas":"0xce4","gasCost":"0x0"`,
+		`"pc":8,"op":0,"gas":"0x2328","gasCost":"0x0"`,
+		`"gasUsed":"0x3e8"`,
+	}
+	for i, want := range wantParts {
+		if !strings.Contains(lines[i], want) {
+			t.Errorf("unexpected line %d, wanted it to contain %s, got %s", i, want, lines[i])
+		}
+	}
+}
+
+func TestJsonTracer_ReportsFailures(t *testing.T) {
+	tests := map[string]struct {
+		op      vm.OpCode
+		result  CallResult
+		err     error
+		wantErr string
+	}{
+		"revert": {
+			op:      vm.REVERT,
+			result:  CallResult{GasLeft: 50},
+			wantErr: "execution reverted",
+		},
+		"failure": {
+			op:      vm.ADD,
+			wantErr: "execution failed",
+		},
+		"error": {
+			op:      vm.ADD,
+			err:     errors.New("injected error"),
+			wantErr: "injected error",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			log := &bytes.Buffer{}
+			tracer := NewJsonTracer(log)
+			tracer.OnEnter(0, Call, CallParameters{Gas: 100})
+			tracer.OnStep(StepInfo{Op: test.op, Gas: 100})
+			tracer.OnExit(0, test.result, test.err)
+
+			lines := strings.Split(strings.TrimSpace(log.String()), "\n")
+			if want, got := 2, len(lines); want != got {
+				t.Fatalf("unexpected number of lines, wanted %d, got %d", want, got)
+			}
+			if want := `"error":"` + test.wantErr + `"`; !strings.Contains(lines[0], want) {
+				t.Errorf("step does not report error %s: %s", want, lines[0])
+			}
+			if want := `"pass":false,"error":"` + test.wantErr + `"`; !strings.Contains(lines[1], want) {
+				t.Errorf("summary does not report error %s: %s", want, lines[1])
+			}
+		})
+	}
+}
+
+func TestJsonTracer_StopsWritingAfterWriterError(t *testing.T) {
+	writer := &failingWriter{}
+	tracer := NewJsonTracer(writer)
+	tracer.OnEnter(0, Call, CallParameters{Gas: 100})
+	tracer.OnStep(StepInfo{Op: vm.PUSH1, Gas: 100})
+	tracer.OnStep(StepInfo{Op: vm.STOP, Gas: 97})
+	tracer.OnExit(0, CallResult{GasLeft: 97, Success: true}, nil)
+
+	if tracer.Err() == nil {
+		t.Errorf("error of writer was not reported")
+	}
+	if want, got := 1, writer.calls; want != got {
+		t.Errorf("unexpected number of writes, wanted %d, got %d", want, got)
+	}
+}
+
+func TestFormatQuantity(t *testing.T) {
+	tests := map[string]struct {
+		data []byte
+		want string
+	}{
+		"empty":        {nil, "0x0"},
+		"zero":         {[]byte{0, 0}, "0x0"},
+		"small":        {[]byte{0, 1}, "0x1"},
+		"leading zero": {[]byte{0x01, 0x00}, "0x100"},
+		"large":        {[]byte{0xff, 0x00}, "0xff00"},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := formatQuantity(test.data); test.want != got {
+				t.Errorf("unexpected result, wanted %s, got %s", test.want, got)
+			}
+		})
+	}
+}
+
+type failingWriter struct {
+	calls int
+}
+
+func (w *failingWriter) Write([]byte) (int, error) {
+	w.calls++
+	return 0, errors.New("injected error")
+}
@@ -0,0 +1,309 @@
+// Copyright (c) 2024 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package tosca
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// PrestateTracer is a TransactionContext recording the state of all accounts
+// and storage slots read or written through it before their first access.
+// Combined with the state of the wrapped context after a transaction, the
+// effects of the transaction are derived. The results serialize to the JSON
+// format produced by geth's prestateTracer, in plain and diff mode.
+//
+// The tracer is intended to wrap the context passed to a processor for running
+// a single transaction. The results are to be obtained after the transaction,
+// before any further modifications of the wrapped context.
+type PrestateTracer struct {
+	TransactionContext
+	revision     Revision
+	accounts     map[Address]*prestateAccount
+	destructions map[Address]struct{} // < accounts on which SelfDestruct was called
+	creations    map[Address]struct{} // < accounts on which CreateContract was called
+}
+
+type prestateAccount struct {
+	exists  bool
+	balance Value
+	nonce   uint64
+	code    Code
+	storage map[Key]Word
+}
+
+// NewPrestateTracer creates a tracer recording the accesses on the given
+// context during a transaction processed in the given revision.
+func NewPrestateTracer(context TransactionContext, revision Revision) *PrestateTracer {
+	return &PrestateTracer{
+		TransactionContext: context,
+		revision:           revision,
+		accounts:           map[Address]*prestateAccount{},
+		destructions:       map[Address]struct{}{},
+		creations:          map[Address]struct{}{},
+	}
+}
+
+// PrestateAccount is the state of an account as reported by the prestate
+// tracer. Fields not relevant for a report are left at their zero value, in
+// which case they are omitted in the JSON representation.
+type PrestateAccount struct {
+	Balance *Value       // < nil if not reported
+	Nonce   uint64       // < 0 if not reported
+	Code    Code         // < nil if not reported
+	Storage map[Key]Word // < storage slots to be reported
+}
+
+// Prestate maps accounts to their reported state.
+type Prestate map[Address]PrestateAccount
+
+// StateDiff summarizes the modifications of a transaction. Pre lists the
+// original state of modified accounts, Post lists modified account fields
+// and storage slots with their new values. Accounts deleted by the
+// transaction are only listed in Pre.
+type StateDiff struct {
+	Post Prestate `json:"post"`
+	Pre  Prestate `json:"pre"`
+}
+
+// GetPrestate returns the state of all accessed accounts before the
+// transaction. Contracts created by the transaction are not included.
+func (t *PrestateTracer) GetPrestate() Prestate {
+	res := Prestate{}
+	for address, account := range t.accounts {
+		if t.isCreated(address, account) {
+			continue
+		}
+		res[address] = account.toPrestateAccount()
+	}
+	return res
+}
+
+// GetStateDiff returns the modifications of the world state conducted by the
+// transaction. Unmodified accounts and storage slots are not included, nor
+// are storage slots with a zero value.
+func (t *PrestateTracer) GetStateDiff() StateDiff {
+	res := StateDiff{Pre: Prestate{}, Post: Prestate{}}
+	for address, account := range t.accounts {
+		pre := account.toPrestateAccount()
+		if t.isDeleted(address) {
+			if !t.isCreated(address, account) {
+				res.Pre[address] = pre
+			}
+			continue
+		}
+
+		modified := false
+		post := PrestateAccount{Storage: map[Key]Word{}}
+		if balance := t.TransactionContext.GetBalance(address); balance != account.balance {
+			modified = true
+			post.Balance = &balance
+		}
+		if nonce := t.TransactionContext.GetNonce(address); nonce != account.nonce {
+			modified = true
+			post.Nonce = nonce
+		}
+		if code := t.TransactionContext.GetCode(address); !bytes.Equal(code, account.code) {
+			modified = true
+			post.Code = code
+		}
+		for key, value := range account.storage {
+			if value == (Word{}) {
+				delete(pre.Storage, key)
+			}
+			current := t.TransactionContext.GetStorage(address, key)
+			if current == value {
+				delete(pre.Storage, key)
+				continue
+			}
+			modified = true
+			if current != (Word{}) {
+				post.Storage[key] = current
+			}
+		}
+
+		if !modified {
+			continue
+		}
+		res.Post[address] = post
+		if !t.isCreated(address, account) {
+			res.Pre[address] = pre
+		}
+	}
+	return res
+}
+
+// isCreated determines whether the given account did not exist before the
+// transaction and became a contract during the transaction.
+func (t *PrestateTracer) isCreated(address Address, account *prestateAccount) bool {
+	if account.exists {
+		return false
+	}
+	return t.TransactionContext.GetNonce(address) != 0 || t.TransactionContext.GetCodeSize(address) != 0
+}
+
+// isDeleted determines whether the given account is deleted at the end of
+// the transaction due to a self-destruct (EIP-6780 since Cancun).
+func (t *PrestateTracer) isDeleted(address Address) bool {
+	if _, found := t.destructions[address]; !found {
+		return false
+	}
+	if !t.TransactionContext.HasSelfDestructed(address) {
+		return false
+	}
+	_, created := t.creations[address]
+	return t.revision < R13_Cancun || created
+}
+
+func (a *prestateAccount) toPrestateAccount() PrestateAccount {
+	balance := a.balance
+	res := PrestateAccount{
+		Balance: &balance,
+		Nonce:   a.nonce,
+		Code:    a.code,
+	}
+	if len(a.storage) > 0 {
+		res.Storage = make(map[Key]Word, len(a.storage))
+		for key, value := range a.storage {
+			res.Storage[key] = value
+		}
+	}
+	return res
+}
+
+// MarshalJSON produces the JSON representation of an account used by geth's
+// prestateTracer. Balances and byte strings are hex encoded.
+func (a PrestateAccount) MarshalJSON() ([]byte, error) {
+	type accountJson struct {
+		Balance string            `json:"balance,omitempty"`
+		Code    string            `json:"code,omitempty"`
+		Nonce   uint64            `json:"nonce,omitempty"`
+		Storage map[string]string `json:"storage,omitempty"`
+	}
+	res := accountJson{Nonce: a.Nonce}
+	if a.Balance != nil {
+		res.Balance = fmt.Sprintf("0x%x", a.Balance.ToBig())
+	}
+	if len(a.Code) > 0 {
+		res.Code = fmt.Sprintf("0x%x", []byte(a.Code))
+	}
+	if len(a.Storage) > 0 {
+		res.Storage = make(map[string]string, len(a.Storage))
+		for key, value := range a.Storage {
+			res.Storage[key.String()] = value.String()
+		}
+	}
+	return json.Marshal(res)
+}
+
+// touch records the state of the given account if it is accessed for the
+// first time.
+func (t *PrestateTracer) touch(address Address) *prestateAccount {
+	if account, found := t.accounts[address]; found {
+		return account
+	}
+	account := &prestateAccount{
+		exists:  t.TransactionContext.AccountExists(address),
+		balance: t.TransactionContext.GetBalance(address),
+		nonce:   t.TransactionContext.GetNonce(address),
+		code:    t.TransactionContext.GetCode(address),
+		storage: map[Key]Word{},
+	}
+	t.accounts[address] = account
+	return account
+}
+
+// touchSlot records the value of the given storage slot if it is accessed
+// for the first time.
+func (t *PrestateTracer) touchSlot(address Address, key Key) {
+	account := t.touch(address)
+	if _, found := account.storage[key]; !found {
+		account.storage[key] = t.TransactionContext.GetStorage(address, key)
+	}
+}
+
+func (t *PrestateTracer) AccountExists(address Address) bool {
+	t.touch(address)
+	return t.TransactionContext.AccountExists(address)
+}
+
+func (t *PrestateTracer) GetBalance(address Address) Value {
+	t.touch(address)
+	return t.TransactionContext.GetBalance(address)
+}
+
+func (t *PrestateTracer) SetBalance(address Address, value Value) {
+	t.touch(address)
+	t.TransactionContext.SetBalance(address, value)
+}
+
+func (t *PrestateTracer) GetNonce(address Address) uint64 {
+	t.touch(address)
+	return t.TransactionContext.GetNonce(address)
+}
+
+func (t *PrestateTracer) SetNonce(address Address, nonce uint64) {
+	t.touch(address)
+	t.TransactionContext.SetNonce(address, nonce)
+}
+
+func (t *PrestateTracer) GetCode(address Address) Code {
+	t.touch(address)
+	return t.TransactionContext.GetCode(address)
+}
+
+func (t *PrestateTracer) GetCodeHash(address Address) Hash {
+	t.touch(address)
+	return t.TransactionContext.GetCodeHash(address)
+}
+
+func (t *PrestateTracer) GetCodeSize(address Address) int {
+	t.touch(address)
+	return t.TransactionContext.GetCodeSize(address)
+}
+
+func (t *PrestateTracer) SetCode(address Address, code Code) {
+	t.touch(address)
+	t.TransactionContext.SetCode(address, code)
+}
+
+func (t *PrestateTracer) GetStorage(address Address, key Key) Word {
+	t.touchSlot(address, key)
+	return t.TransactionContext.GetStorage(address, key)
+}
+
+func (t *PrestateTracer) GetCommittedStorage(address Address, key Key) Word {
+	t.touchSlot(address, key)
+	return t.TransactionContext.GetCommittedStorage(address, key)
+}
+
+func (t *PrestateTracer) SetStorage(address Address, key Key, value Word) StorageStatus {
+	t.touchSlot(address, key)
+	return t.TransactionContext.SetStorage(address, key, value)
+}
+
+func (t *PrestateTracer) SelfDestruct(address Address, beneficiary Address) bool {
+	t.touch(address)
+	t.touch(beneficiary)
+	t.destructions[address] = struct{}{}
+	return t.TransactionContext.SelfDestruct(address, beneficiary)
+}
+
+func (t *PrestateTracer) CreateContract(address Address) {
+	t.touch(address)
+	t.creations[address] = struct{}{}
+	t.TransactionContext.CreateContract(address)
+}
+
+func (t *PrestateTracer) GetTracer() Tracer {
+	return GetTracer(t.TransactionContext)
+}
@@ -0,0 +1,247 @@
+// Copyright (c) 2024 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package tosca
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestPrestateTracer_ImplementsTracingContext(t *testing.T) {
+	var _ TracingContext = &PrestateTracer{}
+}
+
+func TestPrestateTracer_RecordsStateBeforeFirstAccess(t *testing.T) {
+	context := NewInMemoryTransactionContext(R13_Cancun, map[Address]Account{
+		{1}: {Balance: NewValue(10), Nonce: 2, Code: Code{0x00}, Storage: map[Key]Word{{1}: {1}}},
+		{2}: {Balance: NewValue(20)},
+	})
+	tracer := NewPrestateTracer(context, R13_Cancun)
+
+	tracer.SetBalance(Address{1}, NewValue(5))
+	tracer.SetStorage(Address{1}, Key{1}, Word{7})
+	tracer.GetStorage(Address{1}, Key{2})
+	tracer.GetBalance(Address{2})
+	tracer.SetBalance(Address{2}, NewValue(25))
+
+	balance1 := NewValue(10)
+	balance2 := NewValue(20)
+	want := Prestate{
+		{1}: {Balance: &balance1, Nonce: 2, Code: Code{0x00}, Storage: map[Key]Word{{1}: {1}, {2}: {}}},
+		{2}: {Balance: &balance2},
+	}
+	if got := tracer.GetPrestate(); !reflect.DeepEqual(want, got) {
+		t.Errorf("unexpected prestate, wanted %v, got %v", want, got)
+	}
+}
+
+func TestPrestateTracer_PrestateExcludesCreatedContracts(t *testing.T) {
+	context := NewInMemoryTransactionContext(R13_Cancun, nil)
+	tracer := NewPrestateTracer(context, R13_Cancun)
+
+	tracer.SetNonce(Address{1}, 1)
+	tracer.SetCode(Address{1}, Code{0x00})
+	tracer.SetBalance(Address{2}, NewValue(1))
+
+	prestate := tracer.GetPrestate()
+	if _, found := prestate[Address{1}]; found {
+		t.Errorf("created contract is listed in prestate")
+	}
+	if _, found := prestate[Address{2}]; !found {
+		t.Errorf("account receiving funds is not listed in prestate")
+	}
+}
+
+func TestPrestateTracer_StateDiffListsModifiedFieldsAndSlots(t *testing.T) {
+	context := NewInMemoryTransactionContext(R13_Cancun, map[Address]Account{
+		{1}: {Balance: NewValue(10), Nonce: 2, Storage: map[Key]Word{{1}: {1}, {2}: {2}, {3}: {3}}},
+		{2}: {Balance: NewValue(20)},
+	})
+	tracer := NewPrestateTracer(context, R13_Cancun)
+
+	tracer.SetNonce(Address{1}, 3)
+	tracer.SetStorage(Address{1}, Key{1}, Word{4}) // < modified
+	tracer.SetStorage(Address{1}, Key{2}, Word{})  // < cleared
+	tracer.GetStorage(Address{1}, Key{3})          // < unmodified
+	tracer.SetStorage(Address{1}, Key{4}, Word{5}) // < added
+	tracer.GetBalance(Address{2})                  // < unmodified account
+
+	balance := NewValue(10)
+	want := StateDiff{
+		Pre: Prestate{
+			{1}: {Balance: &balance, Nonce: 2, Storage: map[Key]Word{{1}: {1}, {2}: {2}}},
+		},
+		Post: Prestate{
+			{1}: {Nonce: 3, Storage: map[Key]Word{{1}: {4}, {4}: {5}}},
+		},
+	}
+	if got := tracer.GetStateDiff(); !reflect.DeepEqual(want, got) {
+		t.Errorf("unexpected state diff\nwanted %v\n   got %v", want, got)
+	}
+}
+
+func TestPrestateTracer_StateDiffIgnoresRevertedModifications(t *testing.T) {
+	context := NewInMemoryTransactionContext(R13_Cancun, map[Address]Account{
+		{1}: {Balance: NewValue(10)},
+	})
+	tracer := NewPrestateTracer(context, R13_Cancun)
+
+	snapshot := tracer.CreateSnapshot()
+	tracer.SetBalance(Address{1}, NewValue(5))
+	tracer.SetStorage(Address{1}, Key{1}, Word{1})
+	tracer.RestoreSnapshot(snapshot)
+
+	diff := tracer.GetStateDiff()
+	if len(diff.Pre) != 0 || len(diff.Post) != 0 {
+		t.Errorf("unexpected state diff: %v", diff)
+	}
+}
+
+func TestPrestateTracer_StateDiffListsDeletedAccountsOnlyInPrestate(t *testing.T) {
+	tests := map[string]struct {
+		revision Revision
+		deleted  bool
+	}{
+		"shanghai": {R12_Shanghai, true},
+		"cancun":   {R13_Cancun, false},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			context := NewInMemoryTransactionContext(test.revision, map[Address]Account{
+				{1}: {Balance: NewValue(10), Code: Code{0x00}},
+			})
+			tracer := NewPrestateTracer(context, test.revision)
+			tracer.SelfDestruct(Address{1}, Address{2})
+
+			diff := tracer.GetStateDiff()
+			if _, found := diff.Pre[Address{1}]; !found {
+				t.Errorf("self-destructed account is not listed in prestate")
+			}
+			if _, found := diff.Post[Address{1}]; found == test.deleted {
+				t.Errorf("unexpected listing of self-destructed account in poststate: %t", found)
+			}
+			if _, found := diff.Post[Address{2}]; !found {
+				t.Errorf("beneficiary is not listed in poststate")
+			}
+		})
+	}
+}
+
+func TestPrestateTracer_ForwardsContractCreationsToContext(t *testing.T) {
+	address := Address{1}
+	context := NewInMemoryTransactionContext(R13_Cancun, nil)
+	tracer := NewPrestateTracer(context, R13_Cancun)
+
+	tracer.CreateContract(address)
+	tracer.SetNonce(address, 1)
+	tracer.SelfDestruct(address, Address{2})
+	diff := tracer.GetStateDiff()
+	if _, found := diff.Post[address]; found {
+		t.Errorf("self-destructed contract is listed in poststate")
+	}
+
+	context.EndTransaction()
+	if context.AccountExists(address) {
+		t.Errorf("contract created and self-destructed in the same transaction was not deleted")
+	}
+}
+
+func TestPrestateTracer_SelfDestructedAccountWithoutContractCreationIsNotDeletedSinceCancun(t *testing.T) {
+	address := Address{1}
+	context := NewInMemoryTransactionContext(R13_Cancun, nil)
+	tracer := NewPrestateTracer(context, R13_Cancun)
+
+	// Setting the nonce of a new account, e.g. by a code delegation, does
+	// not make it a created contract.
+	tracer.SetNonce(address, 1)
+	tracer.SelfDestruct(address, Address{2})
+	diff := tracer.GetStateDiff()
+	if _, found := diff.Post[address]; !found {
+		t.Errorf("account not created by a contract creation is not listed in poststate")
+	}
+
+	context.EndTransaction()
+	if !context.AccountExists(address) {
+		t.Errorf("account not created by a contract creation was deleted")
+	}
+}
+
+func TestPrestateTracer_StateDiffListsCreatedContractsOnlyInPoststate(t *testing.T) {
+	context := NewInMemoryTransactionContext(R13_Cancun, nil)
+	tracer := NewPrestateTracer(context, R13_Cancun)
+
+	tracer.SetNonce(Address{1}, 1)
+	tracer.SetCode(Address{1}, Code{0x00})
+
+	want := StateDiff{
+		Pre:  Prestate{},
+		Post: Prestate{{1}: {Nonce: 1, Code: Code{0x00}, Storage: map[Key]Word{}}},
+	}
+	if got := tracer.GetStateDiff(); !reflect.DeepEqual(want, got) {
+		t.Errorf("unexpected state diff\nwanted %v\n   got %v", want, got)
+	}
+}
+
+func TestPrestateAccount_MarshalJSONProducesGethFormat(t *testing.T) {
+	balance := NewValue(256)
+	tests := map[string]struct {
+		account PrestateAccount
+		want    string
+	}{
+		"empty": {
+			PrestateAccount{},
+			`{}`,
+		},
+		"zero balance": {
+			PrestateAccount{Balance: &Value{}},
+			`{"balance":"0x0"}`,
+		},
+		"full": {
+			PrestateAccount{
+				Balance: &balance,
+				Nonce:   3,
+				Code:    Code{0x60, 0x00},
+				Storage: map[Key]Word{{31: 1}: {31: 2}},
+			},
+			`{"balance":"0x100","code":"0x6000","nonce":3,"storage":{` +
+				`"0x0000000000000000000000000000000000000000000000000000000000000001":` +
+				`"0x0000000000000000000000000000000000000000000000000000000000000002"}}`,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			data, err := json.Marshal(test.account)
+			if err != nil {
+				t.Fatalf("failed to marshal account: %v", err)
+			}
+			if got := string(data); test.want != got {
+				t.Errorf("unexpected JSON encoding\nwanted %s\n   got %s", test.want, got)
+			}
+		})
+	}
+}
+
+func TestStateDiff_MarshalJSONProducesGethFormat(t *testing.T) {
+	balance := NewValue(1)
+	diff := StateDiff{
+		Pre:  Prestate{{1}: {Balance: &balance}},
+		Post: Prestate{},
+	}
+	data, err := json.Marshal(diff)
+	if err != nil {
+		t.Fatalf("failed to marshal state diff: %v", err)
+	}
+	want := `{"post":{},"pre":{"0x0100000000000000000000000000000000000000":{"balance":"0x1"}}}`
+	if got := string(data); want != got {
+		t.Errorf("unexpected JSON encoding\nwanted %s\n   got %s", want, got)
+	}
+}
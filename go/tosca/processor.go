@@ -22,16 +22,106 @@ type Processor interface {
 	Run(BlockParameters, Transaction, TransactionContext) (Receipt, error)
 }
 
+// Simulator is an optional extension of a Processor for executing transactions
+// as eth_call requests do. Simulated transactions are not subject to nonce,
+// sender, and fee checks, and no gas is bought from or refunded to the sender.
+// Effects on the context are not reverted by the simulator; use the Simulate
+// function for running simulations without modifying the context.
+type Simulator interface {
+	// Simulate executes the transaction provided by the parameters in the
+	// specified context without charging the sender for it.
+	Simulate(BlockParameters, Transaction, TransactionContext) (Receipt, error)
+}
+
 // Transaction summarizes the parameters of a transaction to be executed on a chain.
 type Transaction struct {
-	Sender     Address       // the sender of the transaction, paying for its execution
-	Recipient  *Address      // the receiver of a transaction, nil if a new contract is to be created
-	Nonce      uint64        // the nonce of the sender account, used to prevent replay attacks
-	Input      Data          // the input data for the transaction
-	Value      Value         // the amount of network currency to transfer to the recipient
-	GasLimit   Gas           // the maximum amount of gas that can be used by the transaction
-	GasPrice   Value         // the effective price of a unit of gas for this transaction
-	AccessList []AccessTuple // the list of accounts and storage slots expected to be accessed
+	Sender        Address         // the sender of the transaction, paying for its execution
+	Recipient     *Address        // the receiver of a transaction, nil if a new contract is to be created
+	Nonce         uint64          // the nonce of the sender account, used to prevent replay attacks
+	Input         Data            // the input data for the transaction
+	Value         Value           // the amount of network currency to transfer to the recipient
+	GasLimit      Gas             // the maximum amount of gas that can be used by the transaction
+	GasPrice      Value           // the price of a unit of gas for legacy transactions, ignored by dynamic fee transactions
+	GasFeeCap     Value           // the maximum price of a unit of gas for dynamic fee transactions (EIP-1559)
+	GasTipCap     Value           // the maximum priority fee per unit of gas for dynamic fee transactions (EIP-1559)
+	AccessList    []AccessTuple   // the list of accounts and storage slots expected to be accessed
+	BlobHashes    []Hash          // the versioned hashes of the blobs of blob transactions (EIP-4844)
+	BlobGasFeeCap Value           // the maximum price of a unit of blob gas for blob transactions (EIP-4844)
+	Kind          TransactionKind // the kind of the transaction, RegularTransaction by default
+
+	// AuthorizationList is the list of code delegations to be installed by
+	// set-code transactions (EIP-7702). It is nil for all other transactions.
+	AuthorizationList []SetCodeAuthorization
+}
+
+// TransactionKind distinguishes transactions sent by users from transactions
+// issued by the chain itself.
+type TransactionKind int
+
+const (
+	// RegularTransaction is a transaction sent by a user, paying for its
+	// execution and subject to all consensus checks.
+	RegularTransaction TransactionKind = iota
+	// SystemTransaction is a transaction issued by the chain itself, for
+	// instance to seal an epoch. System transactions are processed like
+	// regular transactions, with the following exceptions:
+	//  - the nonce of the sender is not checked, yet still incremented,
+	//  - the sender is not required to be an externally owned account,
+	//  - the fee caps are not checked and no gas is bought or refunded,
+	//  - no share of the unused gas is charged, independent of fee policies,
+	//  - processors may replace the gas limit by a configured allowance.
+	SystemTransaction
+)
+
+func (k TransactionKind) String() string {
+	switch k {
+	case RegularTransaction:
+		return "regular"
+	case SystemTransaction:
+		return "system"
+	default:
+		return "unknown"
+	}
+}
+
+// IsDynamicFee returns true if the gas price of the transaction is defined by
+// a fee cap and a tip cap as introduced by EIP-1559. Transactions with neither
+// a fee cap nor a tip cap are legacy transactions using a fixed gas price.
+func (t Transaction) IsDynamicFee() bool {
+	return t.GasFeeCap != (Value{}) || t.GasTipCap != (Value{})
+}
+
+// GetGasFeeCap returns the maximum price of a unit of gas the sender is
+// willing to pay. For legacy transactions this is the gas price.
+func (t Transaction) GetGasFeeCap() Value {
+	if t.IsDynamicFee() {
+		return t.GasFeeCap
+	}
+	return t.GasPrice
+}
+
+// GetGasTipCap returns the maximum priority fee per unit of gas the sender is
+// willing to pay. For legacy transactions this is the gas price.
+func (t Transaction) GetGasTipCap() Value {
+	if t.IsDynamicFee() {
+		return t.GasTipCap
+	}
+	return t.GasPrice
+}
+
+// GetEffectiveGasPrice computes the price of a unit of gas paid by the
+// transaction in a block with the given base fee. It is the minimum of the
+// fee cap and the sum of the base fee and the tip cap. The result is only
+// meaningful if the fee cap covers the base fee.
+func (t Transaction) GetEffectiveGasPrice(baseFee Value) Value {
+	if !t.IsDynamicFee() {
+		return t.GasPrice
+	}
+	price := Add(baseFee, t.GasTipCap)
+	if price.Cmp(t.GasFeeCap) > 0 {
+		return t.GasFeeCap
+	}
+	return price
 }
 
 // AccessTuple lists a range of accounts and storage slots expected to be accessed
@@ -43,12 +133,28 @@ type AccessTuple struct {
 	Keys    []Key
 }
 
+// SetCodeAuthorization is a signed request of an account, the authority, to
+// delegate the execution of its code to the code of another account, as
+// introduced by EIP-7702. The authority is recovered from the signature by
+// the processor. Authorizations with invalid signatures are ignored.
+type SetCodeAuthorization struct {
+	ChainID Word    // the chain the authorization is valid for, zero for all chains
+	Address Address // the account to delegate to, the zero address to remove a delegation
+	Nonce   uint64  // the nonce of the authority, used to prevent replay attacks
+	V       uint8   // the recovery ID of the signature, 0 or 1
+	R       Word    // the R value of the signature
+	S       Word    // the S value of the signature
+}
+
 // Receipt summarizes the result of the execution of a transaction.
 type Receipt struct {
-	Success         bool     // false if the execution ended in a revert, true otherwise
-	Output          Data     // the output produced by the transaction
-	ContractAddress *Address // filled if a contract was created by this transaction
-	GasUsed         Gas      // gas used by contract calls
-	BlobGasUsed     Gas      // gas used for blob transactions
-	Logs            []Log    // logs produced by the transaction
+	Success           bool         // false if the execution ended in a revert, true otherwise
+	Output            Data         // the output produced by the transaction
+	ContractAddress   *Address     // filled if a contract was created by this transaction
+	GasUsed           Gas          // gas used by contract calls
+	BlobGasUsed       Gas          // gas used for blob transactions
+	EffectiveGasPrice Value        // the price paid per unit of gas
+	Logs              []Log        // logs produced by the transaction
+	FailureCause      FailureCause // the reason of an unsuccessful execution, FailureNone if unknown
+	RevertReason      string       // the decoded reason of a revert, if available
 }
@@ -62,3 +62,41 @@ func (mr *MockProcessorMockRecorder) Run(arg0, arg1, arg2 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Run", reflect.TypeOf((*MockProcessor)(nil).Run), arg0, arg1, arg2)
 }
+
+// MockSimulator is a mock of Simulator interface.
+type MockSimulator struct {
+	ctrl     *gomock.Controller
+	recorder *MockSimulatorMockRecorder
+}
+
+// MockSimulatorMockRecorder is the mock recorder for MockSimulator.
+type MockSimulatorMockRecorder struct {
+	mock *MockSimulator
+}
+
+// NewMockSimulator creates a new mock instance.
+func NewMockSimulator(ctrl *gomock.Controller) *MockSimulator {
+	mock := &MockSimulator{ctrl: ctrl}
+	mock.recorder = &MockSimulatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSimulator) EXPECT() *MockSimulatorMockRecorder {
+	return m.recorder
+}
+
+// Simulate mocks base method.
+func (m *MockSimulator) Simulate(arg0 BlockParameters, arg1 Transaction, arg2 TransactionContext) (Receipt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Simulate", arg0, arg1, arg2)
+	ret0, _ := ret[0].(Receipt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Simulate indicates an expected call of Simulate.
+func (mr *MockSimulatorMockRecorder) Simulate(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Simulate", reflect.TypeOf((*MockSimulator)(nil).Simulate), arg0, arg1, arg2)
+}
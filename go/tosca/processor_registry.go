@@ -30,12 +30,35 @@ import (
 // GetProcessor performs a lookup for the given name (case-insensitive) and
 // creates a processor instance using the given interpreter. The result is
 // nil if no factory was registered under the given name.
+//
+// Deprecated: Use NewProcessor instead.
 func GetProcessor(name string, interpreter Interpreter) Processor {
+	res, err := NewProcessor(name, interpreter)
+	if err != nil {
+		return nil
+	}
+	return res
+}
+
+// NewProcessor performs a lookup for the given name (case-insensitive) in the
+// registry and creates a new Processor using the given interpreter and the
+// given optional configuration. If no configuration is provided, the
+// implementation uses its default configuration. An error is returned if no
+// factory was registered under the given name or the factory failed to
+// create a processor.
+func NewProcessor(name string, interpreter Interpreter, config ...any) (Processor, error) {
+	if len(config) > 1 {
+		return nil, fmt.Errorf("invalid configuration: too many arguments")
+	}
 	factory := GetProcessorFactory(name)
 	if factory == nil {
-		return nil
+		return nil, fmt.Errorf("processor not found: %s", name)
+	}
+	c := any(nil)
+	if len(config) > 0 {
+		c = config[0]
 	}
-	return factory(interpreter)
+	return factory(interpreter, c)
 }
 
 // GetProcessorFactory performs a lookup for the given name (case-insensitive)
@@ -47,6 +70,32 @@ func GetProcessorFactory(name string) ProcessorFactory {
 	return processorRegistry[strings.ToLower(name)]
 }
 
+// GetProcessorInfo performs a lookup for the given name (case-insensitive) in
+// the registry and returns the capabilities of the registered implementation.
+// The second result is false if no factory was registered under the given
+// name.
+func GetProcessorInfo(name string) (ProcessorInfo, bool) {
+	processorRegistryLock.Lock()
+	defer processorRegistryLock.Unlock()
+	key := strings.ToLower(name)
+	if _, found := processorRegistry[key]; !found {
+		return ProcessorInfo{}, false
+	}
+	return processorInfos[key], true
+}
+
+// GetAllRegisteredProcessorInfos obtains the capabilities of all registered
+// implementations.
+func GetAllRegisteredProcessorInfos() map[string]ProcessorInfo {
+	processorRegistryLock.Lock()
+	defer processorRegistryLock.Unlock()
+	res := make(map[string]ProcessorInfo, len(processorRegistry))
+	for name := range processorRegistry {
+		res[name] = processorInfos[name]
+	}
+	return res
+}
+
 // GetAllRegisteredProcessorFactories obtains all registered implementations.
 func GetAllRegisteredProcessorFactories() map[string]ProcessorFactory {
 	processorRegistryLock.Lock()
@@ -54,31 +103,77 @@ func GetAllRegisteredProcessorFactories() map[string]ProcessorFactory {
 	return maps.Clone(processorRegistry)
 }
 
-// RegisterProcessorFactory can be used to register a new Processor implementation
-// to be exported for general use in the binary. The name is not case-sensitive,
-// and a panic is triggered if an implementation was bound to the same name
-// before, or the implementation is nil. This function is mainly intended to be
-// used by package initialization code.
-func RegisterProcessorFactory(name string, impl ProcessorFactory) {
+// RegisterProcessorFactory registers a new Processor implementation to be
+// exported for general use in the binary. The name is not case-sensitive, and
+// an error is returned if a factory was bound to the same name before, or the
+// factory is nil. Optionally, the capabilities of the implementation may be
+// provided. If omitted, the zero value of ProcessorInfo is recorded. This
+// function is mainly intended to be used by package initialization code.
+func RegisterProcessorFactory(name string, factory ProcessorFactory, info ...ProcessorInfo) error {
 	key := strings.ToLower(name)
-	if impl == nil {
-		panic(fmt.Sprintf("invalid initialization: cannot register nil-processor using `%s`", key))
+	if factory == nil {
+		return fmt.Errorf("invalid initialization: cannot register nil-factory using `%s`", key)
+	}
+	if len(info) > 1 {
+		return fmt.Errorf("invalid initialization: multiple infos provided for `%s`", key)
 	}
 	processorRegistryLock.Lock()
 	defer processorRegistryLock.Unlock()
 	if _, found := processorRegistry[key]; found {
-		panic(fmt.Sprintf("invalid initialization: multiple Processors registered for `%s`", key))
+		return fmt.Errorf("invalid initialization: multiple factories registered for `%s`", key)
+	}
+	processorRegistry[key] = factory
+	if len(info) > 0 {
+		processorInfos[key] = info[0]
+	}
+	return nil
+}
+
+// MustRegisterProcessorFactory registers a new Processor implementation.
+// See RegisterProcessorFactory for more information.
+// This function panics if the registration fails. This function is intended to
+// be used exclusively in package initialization code, where error handling is
+// limited.
+func MustRegisterProcessorFactory(name string, factory ProcessorFactory, info ...ProcessorInfo) {
+	if err := RegisterProcessorFactory(name, factory, info...); err != nil {
+		panic(fmt.Errorf("failed to register processor factory: %s", err))
 	}
-	processorRegistry[key] = impl
 }
 
 // ProcessorFactory is the type of a function that creates a new Processor
-// using a given interpreter.
-type ProcessorFactory func(Interpreter) Processor
+// using the given interpreter and a processor specific configuration. A nil
+// configuration requests the default configuration of the implementation.
+type ProcessorFactory func(interpreter Interpreter, config any) (Processor, error)
+
+// ProcessorInfo describes the capabilities of a registered Processor
+// implementation. It enables clients to select implementations based on the
+// features they need instead of hard-coding implementation names.
+type ProcessorInfo struct {
+	// OldestSupportedRevision and NewestSupportedRevision define the range
+	// of revisions supported by the implementation, both inclusive. The
+	// effective range is further limited by the interpreter in use.
+	OldestSupportedRevision Revision
+	NewestSupportedRevision Revision
+	// Tracing is true if the implementation reports the execution of
+	// transactions to tracers requested through a TracingContext.
+	Tracing bool
+	// NativeDependency names the native library required by the
+	// implementation. It is empty for pure Go implementations.
+	NativeDependency string
+}
 
-// processorRegistry is a global registry for Processor instances of
+// SupportsRevision returns true if the given revision is within the range of
+// revisions supported by the described implementation.
+func (i ProcessorInfo) SupportsRevision(revision Revision) bool {
+	return i.OldestSupportedRevision <= revision && revision <= i.NewestSupportedRevision
+}
+
+// processorRegistry is a global registry for Processor factories of
 // different implementations and configurations.
 var processorRegistry = map[string]ProcessorFactory{}
 
+// processorInfos records the capabilities of the registered implementations.
+var processorInfos = map[string]ProcessorInfo{}
+
 // processorRegistryLock to protect access to the registry.
 var processorRegistryLock sync.Mutex
@@ -11,6 +11,8 @@
 package tosca
 
 import (
+	"errors"
+	"fmt"
 	"slices"
 	"testing"
 
@@ -19,12 +21,14 @@ import (
 )
 
 func TestProcessorRegistry_CanListContent(t *testing.T) {
-	myFactory := func(Interpreter) Processor {
-		return nil
+	myFactory := func(Interpreter, any) (Processor, error) {
+		return nil, nil
 	}
 
 	name := "test1"
-	RegisterProcessorFactory(name, myFactory)
+	if err := RegisterProcessorFactory(name, myFactory); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
 
 	factories := maps.Keys(GetAllRegisteredProcessorFactories())
 	if !slices.Contains(factories, name) {
@@ -35,17 +39,19 @@ func TestProcessorRegistry_CanListContent(t *testing.T) {
 func TestProcessorRegistry_RegisteredFactoryCanBeUsed(t *testing.T) {
 	counter := 0
 	name := "test2"
-	myFactory := func(Interpreter) Processor {
+	myFactory := func(Interpreter, any) (Processor, error) {
 		counter++
-		return nil
+		return nil, nil
+	}
+	if err := RegisterProcessorFactory(name, myFactory); err != nil {
+		t.Fatalf("unexpected error: %v", err)
 	}
-	RegisterProcessorFactory(name, myFactory)
 
 	got := GetProcessorFactory(name)
 	if got == nil {
 		t.Fatalf("expected factory, got nil")
 	}
-	got(nil)
+	_, _ = got(nil, nil)
 	if counter != 1 {
 		t.Errorf("expected factory to be called once, got %d", counter)
 	}
@@ -54,17 +60,25 @@ func TestProcessorRegistry_RegisteredFactoryCanBeUsed(t *testing.T) {
 func TestProcessorRegistry_RegisteredFactoryIsUsedByGetProcessor(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	interpreter := NewMockInterpreter(ctrl)
+	processor := NewMockProcessor(ctrl)
 
 	name := "test3"
-	myFactory := func(i Interpreter) Processor {
+	myFactory := func(i Interpreter, config any) (Processor, error) {
 		if i != interpreter {
 			t.Fatalf("unexpected interpreter passed to factory")
 		}
-		return nil
+		if config != nil {
+			t.Fatalf("unexpected configuration passed to factory: %v", config)
+		}
+		return processor, nil
+	}
+	if err := RegisterProcessorFactory(name, myFactory); err != nil {
+		t.Fatalf("unexpected error: %v", err)
 	}
-	RegisterProcessorFactory(name, myFactory)
 
-	GetProcessor(name, interpreter)
+	if got := GetProcessor(name, interpreter); got != processor {
+		t.Errorf("unexpected processor, wanted %v, got %v", processor, got)
+	}
 }
 
 func TestProcessorRegistry_GetProcessorReturnsNilForUnknownProcessor(t *testing.T) {
@@ -73,27 +87,168 @@ func TestProcessorRegistry_GetProcessorReturnsNilForUnknownProcessor(t *testing.
 	}
 }
 
-func TestProcessorRegistry_FailToRegisterNilFactory(t *testing.T) {
-	defer func() {
-		if r := recover(); r == nil {
-			t.Errorf("expected panic, got nil")
+func TestProcessorRegistry_GetProcessorReturnsNilIfFactoryFails(t *testing.T) {
+	name := "test-failing-factory"
+	myFactory := func(Interpreter, any) (Processor, error) {
+		return nil, fmt.Errorf("injected error")
+	}
+	if err := RegisterProcessorFactory(name, myFactory); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if processor := GetProcessor(name, nil); processor != nil {
+		t.Errorf("expected nil processor, got %v", processor)
+	}
+}
+
+func TestProcessorRegistry_NewProcessorForwardsConfiguration(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	interpreter := NewMockInterpreter(ctrl)
+	processor := NewMockProcessor(ctrl)
+
+	type myConfig struct{ value int }
+
+	name := "test-config"
+	myFactory := func(i Interpreter, config any) (Processor, error) {
+		if i != interpreter {
+			t.Fatalf("unexpected interpreter passed to factory")
 		}
-	}()
-	RegisterProcessorFactory("nil", nil)
+		if want, got := (myConfig{12}), config; want != got {
+			t.Fatalf("unexpected configuration, wanted %v, got %v", want, got)
+		}
+		return processor, nil
+	}
+	if err := RegisterProcessorFactory(name, myFactory); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, err := NewProcessor(name, interpreter, myConfig{12})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != processor {
+		t.Errorf("unexpected processor, wanted %v, got %v", processor, got)
+	}
+}
+
+func TestProcessorRegistry_NewProcessorForwardsFactoryErrors(t *testing.T) {
+	injectedError := fmt.Errorf("injected error")
+	name := "test-factory-error"
+	myFactory := func(Interpreter, any) (Processor, error) {
+		return nil, injectedError
+	}
+	if err := RegisterProcessorFactory(name, myFactory); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := NewProcessor(name, nil); !errors.Is(err, injectedError) {
+		t.Errorf("unexpected error, wanted %v, got %v", injectedError, err)
+	}
+}
+
+func TestProcessorRegistry_NewProcessorFailsForUnknownProcessor(t *testing.T) {
+	if _, err := NewProcessor("something odd", nil); err == nil {
+		t.Errorf("expected error, got nil")
+	}
+}
+
+func TestProcessorRegistry_NewProcessorFailsForMultipleConfigurations(t *testing.T) {
+	name := "test-multiple-configs"
+	myFactory := func(Interpreter, any) (Processor, error) {
+		return nil, nil
+	}
+	if err := RegisterProcessorFactory(name, myFactory); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := NewProcessor(name, nil, 1, 2); err == nil {
+		t.Errorf("expected error, got nil")
+	}
+}
+
+func TestProcessorRegistry_FailToRegisterNilFactory(t *testing.T) {
+	if err := RegisterProcessorFactory("nil", nil); err == nil {
+		t.Errorf("expected error, got nil")
+	}
 }
 
 func TestProcessorRegistry_FailToRegisterSameNameMultipleTimes(t *testing.T) {
 	name := "test4"
-	myFactory := func(Interpreter) Processor { return nil }
+	myFactory := func(Interpreter, any) (Processor, error) { return nil, nil }
 
 	// The first time it is fine.
-	RegisterProcessorFactory(name, myFactory)
+	if err := RegisterProcessorFactory(name, myFactory); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
 
-	// The second time it should panic.
+	// The second time it should fail.
+	if err := RegisterProcessorFactory(name, myFactory); err == nil {
+		t.Errorf("expected error, got nil")
+	}
+}
+
+func TestProcessorRegistry_MustRegisterPanicsOnError(t *testing.T) {
 	defer func() {
 		if r := recover(); r == nil {
 			t.Errorf("expected panic, got nil")
 		}
 	}()
-	RegisterProcessorFactory(name, myFactory)
+	MustRegisterProcessorFactory("nil", nil)
+}
+
+func TestProcessorRegistry_InfoOfRegisteredFactoryCanBeRetrieved(t *testing.T) {
+	name := "test5"
+	info := ProcessorInfo{
+		OldestSupportedRevision: R09_Berlin,
+		NewestSupportedRevision: R13_Cancun,
+		Tracing:                 true,
+	}
+	MustRegisterProcessorFactory(name, func(Interpreter, any) (Processor, error) { return nil, nil }, info)
+
+	got, found := GetProcessorInfo(name)
+	if !found {
+		t.Fatalf("info of %v not found", name)
+	}
+	if got != info {
+		t.Errorf("unexpected info, wanted %v, got %v", info, got)
+	}
+	if got := GetAllRegisteredProcessorInfos()[name]; got != info {
+		t.Errorf("unexpected info in list of all infos, wanted %v, got %v", info, got)
+	}
+}
+
+func TestProcessorRegistry_InfoDefaultsToZeroValue(t *testing.T) {
+	name := "test6"
+	MustRegisterProcessorFactory(name, func(Interpreter, any) (Processor, error) { return nil, nil })
+
+	got, found := GetProcessorInfo(name)
+	if !found {
+		t.Fatalf("info of %v not found", name)
+	}
+	if got != (ProcessorInfo{}) {
+		t.Errorf("unexpected info, got %v", got)
+	}
+}
+
+func TestProcessorRegistry_GetProcessorInfoReportsUnknownProcessors(t *testing.T) {
+	if _, found := GetProcessorInfo("something odd"); found {
+		t.Errorf("expected no info for unknown processor")
+	}
+}
+
+func TestProcessorRegistry_FailToRegisterMultipleInfos(t *testing.T) {
+	factory := func(Interpreter, any) (Processor, error) { return nil, nil }
+	if err := RegisterProcessorFactory("test7", factory, ProcessorInfo{}, ProcessorInfo{}); err == nil {
+		t.Errorf("expected error, got nil")
+	}
+}
+
+func TestProcessorInfo_SupportsRevision(t *testing.T) {
+	info := ProcessorInfo{
+		OldestSupportedRevision: R09_Berlin,
+		NewestSupportedRevision: R12_Shanghai,
+	}
+	for _, revision := range GetAllKnownRevisions() {
+		want := R09_Berlin <= revision && revision <= R12_Shanghai
+		if got := info.SupportsRevision(revision); want != got {
+			t.Errorf("unexpected support for %v, wanted %t, got %t", revision, want, got)
+		}
+	}
 }
@@ -0,0 +1,79 @@
+// Copyright (c) 2024 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package tosca
+
+import "testing"
+
+func TestTransaction_LegacyTransactionsUseGasPrice(t *testing.T) {
+	transaction := Transaction{GasPrice: NewValue(5)}
+	if transaction.IsDynamicFee() {
+		t.Errorf("legacy transaction reported as dynamic fee transaction")
+	}
+	if want, got := NewValue(5), transaction.GetGasFeeCap(); want != got {
+		t.Errorf("unexpected fee cap, wanted %v, got %v", want, got)
+	}
+	if want, got := NewValue(5), transaction.GetGasTipCap(); want != got {
+		t.Errorf("unexpected tip cap, wanted %v, got %v", want, got)
+	}
+	if want, got := NewValue(5), transaction.GetEffectiveGasPrice(NewValue(3)); want != got {
+		t.Errorf("unexpected effective gas price, wanted %v, got %v", want, got)
+	}
+}
+
+func TestTransaction_DynamicFeeTransactionsUseFeeCaps(t *testing.T) {
+	transaction := Transaction{GasPrice: NewValue(1), GasFeeCap: NewValue(10), GasTipCap: NewValue(2)}
+	if !transaction.IsDynamicFee() {
+		t.Errorf("dynamic fee transaction reported as legacy transaction")
+	}
+	if want, got := NewValue(10), transaction.GetGasFeeCap(); want != got {
+		t.Errorf("unexpected fee cap, wanted %v, got %v", want, got)
+	}
+	if want, got := NewValue(2), transaction.GetGasTipCap(); want != got {
+		t.Errorf("unexpected tip cap, wanted %v, got %v", want, got)
+	}
+}
+
+func TestTransaction_GetEffectiveGasPriceIsCappedByFeeCap(t *testing.T) {
+	transaction := Transaction{GasFeeCap: NewValue(10), GasTipCap: NewValue(2)}
+	tests := []struct {
+		baseFee Value
+		want    Value
+	}{
+		{NewValue(0), NewValue(2)},
+		{NewValue(3), NewValue(5)},
+		{NewValue(8), NewValue(10)},
+		{NewValue(9), NewValue(10)},
+	}
+	for _, test := range tests {
+		if got := transaction.GetEffectiveGasPrice(test.baseFee); test.want != got {
+			t.Errorf("unexpected effective gas price for base fee %v, wanted %v, got %v", test.baseFee, test.want, got)
+		}
+	}
+}
+
+func TestTransaction_KindDefaultsToRegularTransaction(t *testing.T) {
+	if want, got := RegularTransaction, (Transaction{}).Kind; want != got {
+		t.Errorf("unexpected default kind, wanted %v, got %v", want, got)
+	}
+}
+
+func TestTransactionKind_String(t *testing.T) {
+	tests := map[TransactionKind]string{
+		RegularTransaction:  "regular",
+		SystemTransaction:   "system",
+		TransactionKind(-1): "unknown",
+	}
+	for kind, want := range tests {
+		if got := kind.String(); want != got {
+			t.Errorf("unexpected name of kind %d, wanted %s, got %s", int(kind), want, got)
+		}
+	}
+}
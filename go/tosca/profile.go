@@ -0,0 +1,86 @@
+// Copyright (c) 2024 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package tosca
+
+import (
+	"maps"
+	"time"
+)
+
+// Profile is a summary of the statistical data collected by a
+// ProfilingInterpreter. Profiles of independent runs can be merged and are
+// exported in JSON format, such that profiles obtained from different
+// versions of an interpreter can be compared by external tools. The JSON
+// encoding is obtained using the standard encoding/json package.
+//
+// Instructions are identified by their names as reported by the profiled
+// interpreter. Thus, interpreter specific instructions like super
+// instructions may be included. Sequences of instructions are identified by
+// the names of the involved instructions separated by a single space, in
+// execution order. Statistics not collected by an interpreter are left empty.
+type Profile struct {
+	// Steps is the total number of executed instructions.
+	Steps uint64 `json:"steps"`
+	// Interpreter summarizes the top-level invocations of the interpreter.
+	Interpreter ExecutionStats `json:"interpreter"`
+	// Instructions summarizes the execution of individual instructions.
+	Instructions map[string]ExecutionStats `json:"instructions,omitempty"`
+	// Pairs counts the executions of sequences of two instructions.
+	Pairs map[string]uint64 `json:"pairs,omitempty"`
+	// Triples counts the executions of sequences of three instructions.
+	Triples map[string]uint64 `json:"triples,omitempty"`
+	// Quads counts the executions of sequences of four instructions.
+	Quads map[string]uint64 `json:"quads,omitempty"`
+}
+
+// ExecutionStats summarizes the executions of an instruction or interpreter.
+type ExecutionStats struct {
+	Count    uint64        `json:"count"`
+	Duration time.Duration `json:"duration,omitempty"` // < zero if not measured by the interpreter
+}
+
+// Merge adds the statistics of the given profile to this profile.
+func (p *Profile) Merge(other Profile) {
+	p.Steps += other.Steps
+	p.Interpreter.Count += other.Interpreter.Count
+	p.Interpreter.Duration += other.Interpreter.Duration
+	if len(other.Instructions) > 0 && p.Instructions == nil {
+		p.Instructions = make(map[string]ExecutionStats, len(other.Instructions))
+	}
+	for name, stats := range other.Instructions {
+		cur := p.Instructions[name]
+		cur.Count += stats.Count
+		cur.Duration += stats.Duration
+		p.Instructions[name] = cur
+	}
+	p.Pairs = mergeCounters(p.Pairs, other.Pairs)
+	p.Triples = mergeCounters(p.Triples, other.Triples)
+	p.Quads = mergeCounters(p.Quads, other.Quads)
+}
+
+// Clone creates an independent copy of this profile.
+func (p Profile) Clone() Profile {
+	p.Instructions = maps.Clone(p.Instructions)
+	p.Pairs = maps.Clone(p.Pairs)
+	p.Triples = maps.Clone(p.Triples)
+	p.Quads = maps.Clone(p.Quads)
+	return p
+}
+
+func mergeCounters(trg, src map[string]uint64) map[string]uint64 {
+	if len(src) > 0 && trg == nil {
+		trg = make(map[string]uint64, len(src))
+	}
+	for key, count := range src {
+		trg[key] += count
+	}
+	return trg
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2024 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package tosca
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestProfile_MergeAddsUpStatistics(t *testing.T) {
+	a := Profile{
+		Steps:        3,
+		Interpreter:  ExecutionStats{Count: 1, Duration: time.Second},
+		Instructions: map[string]ExecutionStats{"PUSH1": {Count: 2}, "STOP": {Count: 1}},
+		Pairs:        map[string]uint64{"PUSH1 PUSH1": 1, "PUSH1 STOP": 1},
+		Triples:      map[string]uint64{"PUSH1 PUSH1 STOP": 1},
+	}
+	b := Profile{
+		Steps:        2,
+		Interpreter:  ExecutionStats{Count: 2, Duration: time.Millisecond},
+		Instructions: map[string]ExecutionStats{"PUSH1": {Count: 1, Duration: time.Microsecond}, "ADD": {Count: 1}},
+		Pairs:        map[string]uint64{"PUSH1 ADD": 1},
+		Quads:        map[string]uint64{"PUSH1 PUSH1 PUSH1 ADD": 1},
+	}
+
+	a.Merge(b)
+
+	want := Profile{
+		Steps:       5,
+		Interpreter: ExecutionStats{Count: 3, Duration: time.Second + time.Millisecond},
+		Instructions: map[string]ExecutionStats{
+			"PUSH1": {Count: 3, Duration: time.Microsecond},
+			"STOP":  {Count: 1},
+			"ADD":   {Count: 1},
+		},
+		Pairs:   map[string]uint64{"PUSH1 PUSH1": 1, "PUSH1 STOP": 1, "PUSH1 ADD": 1},
+		Triples: map[string]uint64{"PUSH1 PUSH1 STOP": 1},
+		Quads:   map[string]uint64{"PUSH1 PUSH1 PUSH1 ADD": 1},
+	}
+	if !reflect.DeepEqual(want, a) {
+		t.Errorf("unexpected merge result, wanted %v, got %v", want, a)
+	}
+}
+
+func TestProfile_MergeIntoEmptyProfile(t *testing.T) {
+	profile := Profile{Steps: 1, Instructions: map[string]ExecutionStats{"STOP": {Count: 1}}}
+	merged := Profile{}
+	merged.Merge(profile)
+	if !reflect.DeepEqual(profile, merged) {
+		t.Errorf("unexpected merge result, wanted %v, got %v", profile, merged)
+	}
+	merged.Merge(profile)
+	if want, got := uint64(1), profile.Instructions["STOP"].Count; want != got {
+		t.Errorf("merged profile was modified, wanted %d, got %d", want, got)
+	}
+}
+
+func TestProfile_CloneIsIndependent(t *testing.T) {
+	profile := Profile{Steps: 1, Pairs: map[string]uint64{"PUSH1 STOP": 1}}
+	clone := profile.Clone()
+	clone.Pairs["PUSH1 STOP"]++
+	if want, got := uint64(1), profile.Pairs["PUSH1 STOP"]; want != got {
+		t.Errorf("original profile was modified, wanted %d, got %d", want, got)
+	}
+}
+
+func TestProfile_CanBeEncodedAndDecodedUsingJson(t *testing.T) {
+	profile := Profile{
+		Steps:        2,
+		Interpreter:  ExecutionStats{Count: 1, Duration: 12 * time.Nanosecond},
+		Instructions: map[string]ExecutionStats{"PUSH1": {Count: 1}, "STOP": {Count: 1}},
+		Pairs:        map[string]uint64{"PUSH1 STOP": 1},
+	}
+	data, err := json.Marshal(profile)
+	if err != nil {
+		t.Fatalf("failed to encode profile: %v", err)
+	}
+	want := `{"steps":2,"interpreter":{"count":1,"duration":12},` +
+		`"instructions":{"PUSH1":{"count":1},"STOP":{"count":1}},` +
+		`"pairs":{"PUSH1 STOP":1}}`
+	if got := string(data); want != got {
+		t.Errorf("unexpected encoding, wanted %s, got %s", want, got)
+	}
+
+	var restored Profile
+	if err := json.Unmarshal(data, &restored); err != nil {
+		t.Fatalf("failed to decode profile: %v", err)
+	}
+	if !reflect.DeepEqual(profile, restored) {
+		t.Errorf("unexpected decoded profile, wanted %v, got %v", profile, restored)
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2024 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package tosca
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/big"
+)
+
+var (
+	// revertSelector is the selector of Error(string), used by Solidity for
+	// the encoding of revert messages.
+	revertSelector = []byte{0x08, 0xc3, 0x79, 0xa0}
+	// panicSelector is the selector of Panic(uint256), used by Solidity for
+	// the encoding of failed assertions and other internal errors.
+	panicSelector = []byte{0x4e, 0x48, 0x7b, 0x71}
+)
+
+// panicReasons maps the codes of Solidity panics to their meaning.
+var panicReasons = map[uint64]string{
+	0x00: "generic panic",
+	0x01: "assert(false)",
+	0x11: "arithmetic underflow or overflow",
+	0x12: "division or modulo by zero",
+	0x21: "enum overflow",
+	0x22: "invalid encoded storage byte array accessed",
+	0x31: "out-of-bounds array access; popping on an empty array",
+	0x32: "out-of-bounds access of an array or bytesN",
+	0x41: "out of memory",
+	0x51: "uninitialized function",
+}
+
+// DecodeRevertReason decodes the reason of a revert from the output of a call
+// if it is ABI encoded as an Error(string) or a Panic(uint256), as produced
+// by Solidity. The second result is false if the output is not decodable.
+func DecodeRevertReason(output Data) (string, bool) {
+	if len(output) < 4+32 {
+		return "", false
+	}
+	selector, data := output[:4], output[4:]
+	switch {
+	case string(selector) == string(revertSelector):
+		offset, ok := decodeAbiUint(data)
+		if !ok || offset > uint64(len(data))-32 {
+			return "", false
+		}
+		length, ok := decodeAbiUint(data[offset:])
+		if !ok || length > uint64(len(data))-offset-32 {
+			return "", false
+		}
+		start := offset + 32
+		return string(data[start : start+length]), true
+	case string(selector) == string(panicSelector):
+		code := new(big.Int).SetBytes(data[:32])
+		if code.IsUint64() {
+			if reason, found := panicReasons[code.Uint64()]; found {
+				return reason, true
+			}
+		}
+		return fmt.Sprintf("unknown panic code: %#x", code), true
+	}
+	return "", false
+}
+
+// decodeAbiUint decodes a 32-byte ABI encoded integer fitting into 64 bits.
+func decodeAbiUint(data []byte) (uint64, bool) {
+	if len(data) < 32 {
+		return 0, false
+	}
+	for _, cur := range data[:24] {
+		if cur != 0 {
+			return 0, false
+		}
+	}
+	return binary.BigEndian.Uint64(data[24:32]), true
+}
@@ -0,0 +1,53 @@
+// Copyright (c) 2024 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package tosca
+
+import "testing"
+
+func TestDecodeRevertReason(t *testing.T) {
+	panicCode := func(code byte) Data {
+		word := Word{31: code}
+		return append(append(Data{}, panicSelector...), word[:]...)
+	}
+	tests := map[string]struct {
+		output Data
+		want   string
+		ok     bool
+	}{
+		"error":             {append(append(Data{}, revertSelector...), encodeAbiString("failed")...), "failed", true},
+		"empty error":       {append(append(Data{}, revertSelector...), encodeAbiString("")...), "", true},
+		"assertion":         {panicCode(0x01), "assert(false)", true},
+		"unknown panic":     {panicCode(0x99), "unknown panic code: 0x99", true},
+		"unknown selector":  {append(Data{1, 2, 3, 4}, encodeAbiString("failed")...), "", false},
+		"too short":         {Data{0x08, 0xc3, 0x79, 0xa0}, "", false},
+		"truncated message": {append(append(Data{}, revertSelector...), encodeAbiString("failed")[:64]...), "", false},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, ok := DecodeRevertReason(test.output)
+			if test.want != got || test.ok != ok {
+				t.Errorf("unexpected result, wanted (%q, %t), got (%q, %t)", test.want, test.ok, got, ok)
+			}
+		})
+	}
+}
+
+// encodeAbiString produces the ABI encoding of a single string argument.
+func encodeAbiString(s string) []byte {
+	res := make([]byte, 64, 64+len(s)+32)
+	res[31] = 32
+	res[63] = byte(len(s))
+	res = append(res, s...)
+	for len(res)%32 != 0 {
+		res = append(res, 0)
+	}
+	return res
+}
@@ -0,0 +1,225 @@
+// Copyright (c) 2024 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package tosca
+
+import "fmt"
+
+// ErrSimulationNotSupported is returned by Simulate for processors not
+// implementing the Simulator interface.
+const ErrSimulationNotSupported = ConstError("processor does not support simulation")
+
+// AccountOverride defines modifications of an account applied before running
+// a simulation. Nil fields retain the value of the account. State and
+// StateDiff are mutually exclusive.
+type AccountOverride struct {
+	Balance   *Value       // < the balance to be used instead of the current balance
+	Nonce     *uint64      // < the nonce to be used instead of the current nonce
+	Code      Code         // < if not nil, the code to be used instead of the current code
+	State     map[Key]Word // < if not nil, replaces the entire storage of the account
+	StateDiff map[Key]Word // < individual storage slots to be replaced
+}
+
+// StateOverrides defines modifications of accounts applied before running a
+// simulation.
+type StateOverrides map[Address]AccountOverride
+
+// BlockOverrides defines modifications of the parameters of the block a
+// simulation is running in. Nil fields retain the original parameter.
+type BlockOverrides struct {
+	BlockNumber *int64
+	Timestamp   *int64
+	Coinbase    *Address
+	GasLimit    *Gas
+	PrevRandao  *Hash
+	BaseFee     *Value
+	BlobBaseFee *Value
+}
+
+// Apply returns a copy of the given block parameters with the overrides applied.
+func (o *BlockOverrides) Apply(blockParameters BlockParameters) BlockParameters {
+	if o == nil {
+		return blockParameters
+	}
+	if o.BlockNumber != nil {
+		blockParameters.BlockNumber = *o.BlockNumber
+	}
+	if o.Timestamp != nil {
+		blockParameters.Timestamp = *o.Timestamp
+	}
+	if o.Coinbase != nil {
+		blockParameters.Coinbase = *o.Coinbase
+	}
+	if o.GasLimit != nil {
+		blockParameters.GasLimit = *o.GasLimit
+	}
+	if o.PrevRandao != nil {
+		blockParameters.PrevRandao = *o.PrevRandao
+	}
+	if o.BaseFee != nil {
+		blockParameters.BaseFee = *o.BaseFee
+	}
+	if o.BlobBaseFee != nil {
+		blockParameters.BlobBaseFee = *o.BlobBaseFee
+	}
+	return blockParameters
+}
+
+// Simulate executes the given transaction on the given processor in the style
+// of an eth_call request. The state and block overrides are applied before the
+// execution, the sender is not charged for the transaction, and all effects on
+// the context, including the overrides, are reverted afterwards. If the
+// transaction does not specify a gas limit, the gas limit of the block is used.
+func Simulate(
+	processor Processor,
+	blockParameters BlockParameters,
+	transaction Transaction,
+	context TransactionContext,
+	stateOverrides StateOverrides,
+	blockOverrides *BlockOverrides,
+) (Receipt, error) {
+	simulator, ok := processor.(Simulator)
+	if !ok {
+		return Receipt{}, ErrSimulationNotSupported
+	}
+
+	blockParameters = blockOverrides.Apply(blockParameters)
+	if transaction.GasLimit == 0 {
+		transaction.GasLimit = blockParameters.GasLimit
+	}
+
+	snapshot := context.CreateSnapshot()
+	defer context.RestoreSnapshot(snapshot)
+
+	overridden, err := newOverrideContext(context, stateOverrides)
+	if err != nil {
+		return Receipt{}, err
+	}
+	return simulator.Simulate(blockParameters, transaction, overridden)
+}
+
+// overrideContext is a TransactionContext serving the storage of overridden
+// slots from the overrides instead of the underlying context. Overridden slots
+// are considered to be committed, such that the gas costs of storage
+// operations are the same as if the overrides were part of the world state.
+type overrideContext struct {
+	TransactionContext
+	storage   map[Address]*storageOverride
+	journal   []func()
+	snapshots []overrideSnapshot
+}
+
+// overrideSnapshot combines a snapshot of the underlying context with the
+// state of the journal of overridden storage slots.
+type overrideSnapshot struct {
+	inner       Snapshot
+	journalSize int
+}
+
+type storageOverride struct {
+	full     bool // < true if all slots not in original are zero
+	original map[Key]Word
+	current  map[Key]Word
+}
+
+func (o *storageOverride) covers(key Key) bool {
+	_, found := o.original[key]
+	return o.full || found
+}
+
+func newOverrideContext(context TransactionContext, overrides StateOverrides) (TransactionContext, error) {
+	res := &overrideContext{
+		TransactionContext: context,
+		storage:            map[Address]*storageOverride{},
+	}
+	for address, override := range overrides {
+		if override.State != nil && override.StateDiff != nil {
+			return nil, fmt.Errorf("account %v has both state and state diff overrides", address)
+		}
+		if override.Balance != nil {
+			context.SetBalance(address, *override.Balance)
+		}
+		if override.Code != nil {
+			context.SetCode(address, override.Code)
+		}
+		if override.Nonce != nil {
+			context.SetNonce(address, *override.Nonce)
+		}
+		storage := override.StateDiff
+		if override.State != nil {
+			storage = override.State
+		}
+		if storage == nil {
+			continue
+		}
+		original := make(map[Key]Word, len(storage))
+		current := make(map[Key]Word, len(storage))
+		for key, value := range storage {
+			original[key] = value
+			current[key] = value
+		}
+		res.storage[address] = &storageOverride{
+			full:     override.State != nil,
+			original: original,
+			current:  current,
+		}
+	}
+	return res, nil
+}
+
+func (c *overrideContext) GetStorage(address Address, key Key) Word {
+	if override, found := c.storage[address]; found && override.covers(key) {
+		return override.current[key]
+	}
+	return c.TransactionContext.GetStorage(address, key)
+}
+
+func (c *overrideContext) GetCommittedStorage(address Address, key Key) Word {
+	if override, found := c.storage[address]; found && override.covers(key) {
+		return override.original[key]
+	}
+	return c.TransactionContext.GetCommittedStorage(address, key)
+}
+
+func (c *overrideContext) SetStorage(address Address, key Key, value Word) StorageStatus {
+	override, found := c.storage[address]
+	if !found || !override.covers(key) {
+		return c.TransactionContext.SetStorage(address, key, value)
+	}
+	current := override.current[key]
+	override.current[key] = value
+	c.journal = append(c.journal, func() { override.current[key] = current })
+	return GetStorageStatus(override.original[key], current, value)
+}
+
+func (c *overrideContext) CreateSnapshot() Snapshot {
+	c.snapshots = append(c.snapshots, overrideSnapshot{
+		inner:       c.TransactionContext.CreateSnapshot(),
+		journalSize: len(c.journal),
+	})
+	return Snapshot(len(c.snapshots) - 1)
+}
+
+func (c *overrideContext) RestoreSnapshot(snapshot Snapshot) {
+	if int(snapshot) < 0 || int(snapshot) >= len(c.snapshots) {
+		return
+	}
+	entry := c.snapshots[snapshot]
+	c.snapshots = c.snapshots[:snapshot+1]
+	c.TransactionContext.RestoreSnapshot(entry.inner)
+	for len(c.journal) > entry.journalSize {
+		c.journal[len(c.journal)-1]()
+		c.journal = c.journal[:len(c.journal)-1]
+	}
+}
+
+func (c *overrideContext) GetTracer() Tracer {
+	return GetTracer(c.TransactionContext)
+}
@@ -0,0 +1,215 @@
+// Copyright (c) 2024 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package tosca
+
+import (
+	"errors"
+	"testing"
+
+	"go.uber.org/mock/gomock"
+)
+
+// simulatingProcessor is a processor supporting simulations.
+type simulatingProcessor struct {
+	*MockProcessor
+	*MockSimulator
+}
+
+func newSimulatingProcessor(ctrl *gomock.Controller) simulatingProcessor {
+	return simulatingProcessor{NewMockProcessor(ctrl), NewMockSimulator(ctrl)}
+}
+
+func TestBlockOverrides_Apply(t *testing.T) {
+	number := int64(12)
+	coinbase := Address{3}
+	baseFee := NewValue(7)
+
+	original := BlockParameters{BlockNumber: 1, Timestamp: 2, Coinbase: Address{1}, BaseFee: NewValue(1)}
+	overrides := &BlockOverrides{BlockNumber: &number, Coinbase: &coinbase, BaseFee: &baseFee}
+
+	want := BlockParameters{BlockNumber: 12, Timestamp: 2, Coinbase: Address{3}, BaseFee: NewValue(7)}
+	if got := overrides.Apply(original); want != got {
+		t.Errorf("unexpected block parameters, wanted %v, got %v", want, got)
+	}
+	if got := (*BlockOverrides)(nil).Apply(original); original != got {
+		t.Errorf("nil overrides modified block parameters, wanted %v, got %v", original, got)
+	}
+}
+
+func TestSimulate_RejectsProcessorsNotSupportingSimulation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	processor := NewMockProcessor(ctrl)
+
+	context := NewInMemoryTransactionContext(R13_Cancun, nil)
+	_, err := Simulate(processor, BlockParameters{}, Transaction{}, context, nil, nil)
+	if !errors.Is(err, ErrSimulationNotSupported) {
+		t.Errorf("unexpected error, wanted %v, got %v", ErrSimulationNotSupported, err)
+	}
+}
+
+func TestSimulate_AppliesOverridesAndRevertsAllEffects(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	processor := newSimulatingProcessor(ctrl)
+
+	account := Address{1}
+	context := NewInMemoryTransactionContext(R13_Cancun, map[Address]Account{
+		account: {Balance: NewValue(1), Storage: map[Key]Word{{1}: {1}}},
+	})
+
+	balance := NewValue(100)
+	nonce := uint64(5)
+	timestamp := int64(42)
+	stateOverrides := StateOverrides{
+		account: {
+			Balance:   &balance,
+			Nonce:     &nonce,
+			Code:      Code{0x00},
+			StateDiff: map[Key]Word{{2}: {2}},
+		},
+	}
+	blockOverrides := &BlockOverrides{Timestamp: &timestamp}
+
+	processor.MockSimulator.EXPECT().Simulate(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(block BlockParameters, transaction Transaction, context TransactionContext) (Receipt, error) {
+			if want, got := timestamp, block.Timestamp; want != got {
+				t.Errorf("unexpected timestamp, wanted %d, got %d", want, got)
+			}
+			if want, got := Gas(1_000), transaction.GasLimit; want != got {
+				t.Errorf("unexpected gas limit, wanted %d, got %d", want, got)
+			}
+			if want, got := balance, context.GetBalance(account); want != got {
+				t.Errorf("unexpected balance, wanted %v, got %v", want, got)
+			}
+			if want, got := nonce, context.GetNonce(account); want != got {
+				t.Errorf("unexpected nonce, wanted %d, got %d", want, got)
+			}
+			if want, got := 1, context.GetCodeSize(account); want != got {
+				t.Errorf("unexpected code size, wanted %d, got %d", want, got)
+			}
+			if want, got := (Word{1}), context.GetStorage(account, Key{1}); want != got {
+				t.Errorf("slot not covered by state diff was modified, wanted %v, got %v", want, got)
+			}
+			if want, got := (Word{2}), context.GetStorage(account, Key{2}); want != got {
+				t.Errorf("unexpected storage value, wanted %v, got %v", want, got)
+			}
+			context.SetStorage(account, Key{3}, Word{3})
+			return Receipt{Success: true}, nil
+		})
+
+	block := BlockParameters{GasLimit: 1_000}
+	receipt, err := Simulate(processor, block, Transaction{}, context, stateOverrides, blockOverrides)
+	if err != nil || !receipt.Success {
+		t.Fatalf("failed to simulate transaction, receipt %v, error %v", receipt, err)
+	}
+
+	if want, got := NewValue(1), context.GetBalance(account); want != got {
+		t.Errorf("balance override was not reverted, wanted %v, got %v", want, got)
+	}
+	if want, got := uint64(0), context.GetNonce(account); want != got {
+		t.Errorf("nonce override was not reverted, wanted %d, got %d", want, got)
+	}
+	if want, got := 0, context.GetCodeSize(account); want != got {
+		t.Errorf("code override was not reverted, wanted %d, got %d", want, got)
+	}
+	if want, got := (Word{}), context.GetStorage(account, Key{3}); want != got {
+		t.Errorf("storage modification was not reverted, wanted %v, got %v", want, got)
+	}
+}
+
+func TestSimulate_RejectsConflictingStorageOverrides(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	processor := newSimulatingProcessor(ctrl)
+
+	context := NewInMemoryTransactionContext(R13_Cancun, nil)
+	stateOverrides := StateOverrides{
+		{1}: {State: map[Key]Word{}, StateDiff: map[Key]Word{}},
+	}
+	if _, err := Simulate(processor, BlockParameters{}, Transaction{}, context, stateOverrides, nil); err == nil {
+		t.Errorf("conflicting storage overrides were not detected")
+	}
+}
+
+func TestOverrideContext_FullStateOverrideHidesOriginalStorage(t *testing.T) {
+	account := Address{1}
+	inner := NewInMemoryTransactionContext(R13_Cancun, map[Address]Account{
+		account: {Storage: map[Key]Word{{1}: {1}, {2}: {2}}},
+	})
+	context, err := newOverrideContext(inner, StateOverrides{
+		account: {State: map[Key]Word{{2}: {4}}},
+	})
+	if err != nil {
+		t.Fatalf("failed to create override context: %v", err)
+	}
+
+	if want, got := (Word{}), context.GetStorage(account, Key{1}); want != got {
+		t.Errorf("unexpected storage value, wanted %v, got %v", want, got)
+	}
+	if want, got := (Word{4}), context.GetStorage(account, Key{2}); want != got {
+		t.Errorf("unexpected storage value, wanted %v, got %v", want, got)
+	}
+	if want, got := (Word{4}), context.GetCommittedStorage(account, Key{2}); want != got {
+		t.Errorf("unexpected committed storage value, wanted %v, got %v", want, got)
+	}
+}
+
+func TestOverrideContext_StorageStatusIsRelativeToOverrides(t *testing.T) {
+	account := Address{1}
+	inner := NewInMemoryTransactionContext(R13_Cancun, nil)
+	context, err := newOverrideContext(inner, StateOverrides{
+		account: {StateDiff: map[Key]Word{{1}: {1}}},
+	})
+	if err != nil {
+		t.Fatalf("failed to create override context: %v", err)
+	}
+
+	if want, got := StorageModified, context.SetStorage(account, Key{1}, Word{2}); want != got {
+		t.Errorf("unexpected storage status, wanted %v, got %v", want, got)
+	}
+	if want, got := StorageAdded, context.SetStorage(account, Key{2}, Word{2}); want != got {
+		t.Errorf("unexpected storage status, wanted %v, got %v", want, got)
+	}
+}
+
+func TestOverrideContext_RestoreSnapshotRevertsOverriddenStorage(t *testing.T) {
+	account := Address{1}
+	inner := NewInMemoryTransactionContext(R13_Cancun, nil)
+	context, err := newOverrideContext(inner, StateOverrides{
+		account: {State: map[Key]Word{{1}: {1}}},
+	})
+	if err != nil {
+		t.Fatalf("failed to create override context: %v", err)
+	}
+
+	snapshot := context.CreateSnapshot()
+	context.SetStorage(account, Key{1}, Word{2})
+	nested := context.CreateSnapshot()
+	context.SetStorage(account, Key{1}, Word{3})
+
+	context.RestoreSnapshot(nested)
+	if want, got := (Word{2}), context.GetStorage(account, Key{1}); want != got {
+		t.Errorf("unexpected storage value, wanted %v, got %v", want, got)
+	}
+	context.RestoreSnapshot(snapshot)
+	if want, got := (Word{1}), context.GetStorage(account, Key{1}); want != got {
+		t.Errorf("unexpected storage value, wanted %v, got %v", want, got)
+	}
+}
+
+func TestOverrideContext_ForwardsContractCreationsToContext(t *testing.T) {
+	inner := NewInMemoryTransactionContext(R13_Cancun, nil)
+	context, err := newOverrideContext(inner, StateOverrides{})
+	if err != nil {
+		t.Fatalf("failed to create override context: %v", err)
+	}
+	if !createAndSelfDestruct(context, inner, Address{1}) {
+		t.Errorf("contract created and self-destructed in the same transaction was not deleted")
+	}
+}
@@ -0,0 +1,170 @@
+// Copyright (c) 2024 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package tosca
+
+import "github.com/Fantom-foundation/Tosca/go/tosca/vm"
+
+//go:generate mockgen -source tracer.go -destination tracer_mock.go -package tosca
+
+// Tracer is an observer of the execution of transactions and contract code.
+// Tracers may be used to build debuggers, call tracers, gas profilers, or
+// similar tools on top of Tosca's interpreters and processors.
+//
+// Interpreters report the execution of individual instructions through the
+// OnStep callback. Processors report the entering and leaving of call frames
+// and all modifications of the world state performed during a transaction.
+// Callbacks are issued synchronously on the executing go-routine. Tracers
+// must not modify or retain any of the slices passed to them.
+type Tracer interface {
+	// OnStep is called before the execution of each instruction.
+	OnStep(StepInfo)
+
+	// OnEnter is called when a new call frame is entered. The depth is
+	// the depth of the new call frame, starting with 0 for the outermost
+	// call of a transaction.
+	OnEnter(depth int, kind CallKind, parameters CallParameters)
+
+	// OnExit is called when a call frame is left. The depth matches the
+	// depth reported by the corresponding OnEnter call.
+	OnExit(depth int, result CallResult, err error)
+
+	// OnStorageChange is called whenever a storage slot is updated.
+	OnStorageChange(address Address, key Key, previous, current Word)
+
+	// OnBalanceChange is called whenever the balance of an account is updated.
+	OnBalanceChange(address Address, previous, current Value)
+
+	// OnLog is called whenever a log message is emitted.
+	OnLog(Log)
+}
+
+// StepInfo summarizes the state of an interpreter before the execution of
+// an individual instruction.
+type StepInfo struct {
+	Depth  int       // the depth of the current call frame
+	Pc     int       // the position of the instruction in the EVM byte-code
+	Op     vm.OpCode // the instruction to be executed
+	Gas    Gas       // the gas available before executing the instruction
+	Refund Gas       // the gas refund accumulated by the current call frame
+	Stack  []Word    // the stack content, ordered from bottom to top
+	Memory Data      // the memory content
+}
+
+// TracingContext is an optional extension to the TransactionContext interface
+// which may be implemented by contexts passed to a Processor to request the
+// tracing of the execution of a transaction.
+type TracingContext interface {
+	TransactionContext
+
+	// GetTracer returns the tracer to be informed about the execution of
+	// the transaction run in this context. If nil, no tracing is conducted.
+	GetTracer() Tracer
+}
+
+// GetTracer obtains the tracer requested by the given context. The result is
+// nil if the context does not request tracing.
+func GetTracer(context TransactionContext) Tracer {
+	if tracingContext, ok := context.(TracingContext); ok {
+		return tracingContext.GetTracer()
+	}
+	return nil
+}
+
+// NewTracedTransactionContext wraps the given context such that all world
+// state modifications and emitted logs are reported to the given tracer. If
+// the tracer is nil, the context is returned unmodified.
+func NewTracedTransactionContext(context TransactionContext, tracer Tracer) TransactionContext {
+	if tracer == nil {
+		return context
+	}
+	return &tracedTransactionContext{
+		TransactionContext: context,
+		tracer:             tracer,
+	}
+}
+
+type tracedTransactionContext struct {
+	TransactionContext
+	tracer Tracer
+}
+
+func (c *tracedTransactionContext) GetTracer() Tracer {
+	return c.tracer
+}
+
+func (c *tracedTransactionContext) SetBalance(address Address, value Value) {
+	previous := c.TransactionContext.GetBalance(address)
+	c.TransactionContext.SetBalance(address, value)
+	if previous != value {
+		c.tracer.OnBalanceChange(address, previous, value)
+	}
+}
+
+func (c *tracedTransactionContext) SetStorage(address Address, key Key, value Word) StorageStatus {
+	previous := c.TransactionContext.GetStorage(address, key)
+	status := c.TransactionContext.SetStorage(address, key, value)
+	if previous != value {
+		c.tracer.OnStorageChange(address, key, previous, value)
+	}
+	return status
+}
+
+func (c *tracedTransactionContext) SelfDestruct(address Address, beneficiary Address) bool {
+	// The balance transfer is conducted by the underlying context, thus
+	// the effective changes are derived by comparing the balances.
+	previous := c.TransactionContext.GetBalance(address)
+	previousBeneficiary := c.TransactionContext.GetBalance(beneficiary)
+	res := c.TransactionContext.SelfDestruct(address, beneficiary)
+	if current := c.TransactionContext.GetBalance(address); current != previous {
+		c.tracer.OnBalanceChange(address, previous, current)
+	}
+	if address != beneficiary {
+		if current := c.TransactionContext.GetBalance(beneficiary); current != previousBeneficiary {
+			c.tracer.OnBalanceChange(beneficiary, previousBeneficiary, current)
+		}
+	}
+	return res
+}
+
+func (c *tracedTransactionContext) EmitLog(log Log) {
+	c.TransactionContext.EmitLog(log)
+	c.tracer.OnLog(log)
+}
+
+// RunTraced runs the given interpreter with the given parameters, reporting
+// the execution to the given tracer. Unlike setting the tracer in the
+// parameters, the beginning and end of the executed call frame are reported
+// to the tracer as well. This is intended for tracing executions of
+// interpreters outside of processors, which would otherwise report them.
+func RunTraced(interpreter Interpreter, parameters Parameters, tracer Tracer) (Result, error) {
+	parameters.Tracer = tracer
+	tracer.OnEnter(parameters.Depth, parameters.Kind, CallParameters{
+		Sender:      parameters.Sender,
+		Recipient:   parameters.Recipient,
+		Value:       parameters.Value,
+		Input:       parameters.Input,
+		Gas:         parameters.Gas,
+		CodeAddress: parameters.Recipient,
+	})
+	result, err := interpreter.Run(parameters)
+	callResult := CallResult{
+		Output:       result.Output,
+		GasLeft:      result.GasLeft,
+		GasRefund:    result.GasRefund,
+		Success:      result.Success,
+		FailureCause: result.FailureCause,
+	}
+	if result.FailureCause == FailureRevert {
+		callResult.RevertReason, _ = DecodeRevertReason(result.Output)
+	}
+	tracer.OnExit(parameters.Depth, callResult, err)
+	return result, err
+}
@@ -0,0 +1,509 @@
+// Copyright (c) 2024 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+// Code generated by MockGen. DO NOT EDIT.
+// Source: tracer.go
+//
+// Generated by this command:
+//
+//	mockgen -source tracer.go -destination tracer_mock.go -package tosca
+//
+
+// Package tosca is a generated GoMock package.
+package tosca
+
+import (
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockTracer is a mock of Tracer interface.
+type MockTracer struct {
+	ctrl     *gomock.Controller
+	recorder *MockTracerMockRecorder
+}
+
+// MockTracerMockRecorder is the mock recorder for MockTracer.
+type MockTracerMockRecorder struct {
+	mock *MockTracer
+}
+
+// NewMockTracer creates a new mock instance.
+func NewMockTracer(ctrl *gomock.Controller) *MockTracer {
+	mock := &MockTracer{ctrl: ctrl}
+	mock.recorder = &MockTracerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTracer) EXPECT() *MockTracerMockRecorder {
+	return m.recorder
+}
+
+// OnBalanceChange mocks base method.
+func (m *MockTracer) OnBalanceChange(address Address, previous, current Value) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "OnBalanceChange", address, previous, current)
+}
+
+// OnBalanceChange indicates an expected call of OnBalanceChange.
+func (mr *MockTracerMockRecorder) OnBalanceChange(address, previous, current any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnBalanceChange", reflect.TypeOf((*MockTracer)(nil).OnBalanceChange), address, previous, current)
+}
+
+// OnEnter mocks base method.
+func (m *MockTracer) OnEnter(depth int, kind CallKind, parameters CallParameters) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "OnEnter", depth, kind, parameters)
+}
+
+// OnEnter indicates an expected call of OnEnter.
+func (mr *MockTracerMockRecorder) OnEnter(depth, kind, parameters any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnEnter", reflect.TypeOf((*MockTracer)(nil).OnEnter), depth, kind, parameters)
+}
+
+// OnExit mocks base method.
+func (m *MockTracer) OnExit(depth int, result CallResult, err error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "OnExit", depth, result, err)
+}
+
+// OnExit indicates an expected call of OnExit.
+func (mr *MockTracerMockRecorder) OnExit(depth, result, err any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnExit", reflect.TypeOf((*MockTracer)(nil).OnExit), depth, result, err)
+}
+
+// OnLog mocks base method.
+func (m *MockTracer) OnLog(arg0 Log) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "OnLog", arg0)
+}
+
+// OnLog indicates an expected call of OnLog.
+func (mr *MockTracerMockRecorder) OnLog(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnLog", reflect.TypeOf((*MockTracer)(nil).OnLog), arg0)
+}
+
+// OnStep mocks base method.
+func (m *MockTracer) OnStep(arg0 StepInfo) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "OnStep", arg0)
+}
+
+// OnStep indicates an expected call of OnStep.
+func (mr *MockTracerMockRecorder) OnStep(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnStep", reflect.TypeOf((*MockTracer)(nil).OnStep), arg0)
+}
+
+// OnStorageChange mocks base method.
+func (m *MockTracer) OnStorageChange(address Address, key Key, previous, current Word) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "OnStorageChange", address, key, previous, current)
+}
+
+// OnStorageChange indicates an expected call of OnStorageChange.
+func (mr *MockTracerMockRecorder) OnStorageChange(address, key, previous, current any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnStorageChange", reflect.TypeOf((*MockTracer)(nil).OnStorageChange), address, key, previous, current)
+}
+
+// MockTracingContext is a mock of TracingContext interface.
+type MockTracingContext struct {
+	ctrl     *gomock.Controller
+	recorder *MockTracingContextMockRecorder
+}
+
+// MockTracingContextMockRecorder is the mock recorder for MockTracingContext.
+type MockTracingContextMockRecorder struct {
+	mock *MockTracingContext
+}
+
+// NewMockTracingContext creates a new mock instance.
+func NewMockTracingContext(ctrl *gomock.Controller) *MockTracingContext {
+	mock := &MockTracingContext{ctrl: ctrl}
+	mock.recorder = &MockTracingContextMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTracingContext) EXPECT() *MockTracingContextMockRecorder {
+	return m.recorder
+}
+
+// AccessAccount mocks base method.
+func (m *MockTracingContext) AccessAccount(arg0 Address) AccessStatus {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AccessAccount", arg0)
+	ret0, _ := ret[0].(AccessStatus)
+	return ret0
+}
+
+// AccessAccount indicates an expected call of AccessAccount.
+func (mr *MockTracingContextMockRecorder) AccessAccount(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccessAccount", reflect.TypeOf((*MockTracingContext)(nil).AccessAccount), arg0)
+}
+
+// AccessStorage mocks base method.
+func (m *MockTracingContext) AccessStorage(arg0 Address, arg1 Key) AccessStatus {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AccessStorage", arg0, arg1)
+	ret0, _ := ret[0].(AccessStatus)
+	return ret0
+}
+
+// AccessStorage indicates an expected call of AccessStorage.
+func (mr *MockTracingContextMockRecorder) AccessStorage(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccessStorage", reflect.TypeOf((*MockTracingContext)(nil).AccessStorage), arg0, arg1)
+}
+
+// AccountExists mocks base method.
+func (m *MockTracingContext) AccountExists(arg0 Address) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AccountExists", arg0)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// AccountExists indicates an expected call of AccountExists.
+func (mr *MockTracingContextMockRecorder) AccountExists(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccountExists", reflect.TypeOf((*MockTracingContext)(nil).AccountExists), arg0)
+}
+
+// CreateContract mocks base method.
+func (m *MockTracingContext) CreateContract(arg0 Address) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "CreateContract", arg0)
+}
+
+// CreateContract indicates an expected call of CreateContract.
+func (mr *MockTracingContextMockRecorder) CreateContract(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateContract", reflect.TypeOf((*MockTracingContext)(nil).CreateContract), arg0)
+}
+
+// CreateSnapshot mocks base method.
+func (m *MockTracingContext) CreateSnapshot() Snapshot {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSnapshot")
+	ret0, _ := ret[0].(Snapshot)
+	return ret0
+}
+
+// CreateSnapshot indicates an expected call of CreateSnapshot.
+func (mr *MockTracingContextMockRecorder) CreateSnapshot() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSnapshot", reflect.TypeOf((*MockTracingContext)(nil).CreateSnapshot))
+}
+
+// EmitLog mocks base method.
+func (m *MockTracingContext) EmitLog(arg0 Log) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "EmitLog", arg0)
+}
+
+// EmitLog indicates an expected call of EmitLog.
+func (mr *MockTracingContextMockRecorder) EmitLog(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EmitLog", reflect.TypeOf((*MockTracingContext)(nil).EmitLog), arg0)
+}
+
+// GetBalance mocks base method.
+func (m *MockTracingContext) GetBalance(arg0 Address) Value {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBalance", arg0)
+	ret0, _ := ret[0].(Value)
+	return ret0
+}
+
+// GetBalance indicates an expected call of GetBalance.
+func (mr *MockTracingContextMockRecorder) GetBalance(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBalance", reflect.TypeOf((*MockTracingContext)(nil).GetBalance), arg0)
+}
+
+// GetBlockHash mocks base method.
+func (m *MockTracingContext) GetBlockHash(number int64) Hash {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlockHash", number)
+	ret0, _ := ret[0].(Hash)
+	return ret0
+}
+
+// GetBlockHash indicates an expected call of GetBlockHash.
+func (mr *MockTracingContextMockRecorder) GetBlockHash(number any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockHash", reflect.TypeOf((*MockTracingContext)(nil).GetBlockHash), number)
+}
+
+// GetCode mocks base method.
+func (m *MockTracingContext) GetCode(arg0 Address) Code {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCode", arg0)
+	ret0, _ := ret[0].(Code)
+	return ret0
+}
+
+// GetCode indicates an expected call of GetCode.
+func (mr *MockTracingContextMockRecorder) GetCode(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCode", reflect.TypeOf((*MockTracingContext)(nil).GetCode), arg0)
+}
+
+// GetCodeHash mocks base method.
+func (m *MockTracingContext) GetCodeHash(arg0 Address) Hash {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCodeHash", arg0)
+	ret0, _ := ret[0].(Hash)
+	return ret0
+}
+
+// GetCodeHash indicates an expected call of GetCodeHash.
+func (mr *MockTracingContextMockRecorder) GetCodeHash(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCodeHash", reflect.TypeOf((*MockTracingContext)(nil).GetCodeHash), arg0)
+}
+
+// GetCodeSize mocks base method.
+func (m *MockTracingContext) GetCodeSize(arg0 Address) int {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCodeSize", arg0)
+	ret0, _ := ret[0].(int)
+	return ret0
+}
+
+// GetCodeSize indicates an expected call of GetCodeSize.
+func (mr *MockTracingContextMockRecorder) GetCodeSize(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCodeSize", reflect.TypeOf((*MockTracingContext)(nil).GetCodeSize), arg0)
+}
+
+// GetCommittedStorage mocks base method.
+func (m *MockTracingContext) GetCommittedStorage(addr Address, key Key) Word {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCommittedStorage", addr, key)
+	ret0, _ := ret[0].(Word)
+	return ret0
+}
+
+// GetCommittedStorage indicates an expected call of GetCommittedStorage.
+func (mr *MockTracingContextMockRecorder) GetCommittedStorage(addr, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCommittedStorage", reflect.TypeOf((*MockTracingContext)(nil).GetCommittedStorage), addr, key)
+}
+
+// GetLogs mocks base method.
+func (m *MockTracingContext) GetLogs() []Log {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLogs")
+	ret0, _ := ret[0].([]Log)
+	return ret0
+}
+
+// GetLogs indicates an expected call of GetLogs.
+func (mr *MockTracingContextMockRecorder) GetLogs() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLogs", reflect.TypeOf((*MockTracingContext)(nil).GetLogs))
+}
+
+// GetNonce mocks base method.
+func (m *MockTracingContext) GetNonce(arg0 Address) uint64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNonce", arg0)
+	ret0, _ := ret[0].(uint64)
+	return ret0
+}
+
+// GetNonce indicates an expected call of GetNonce.
+func (mr *MockTracingContextMockRecorder) GetNonce(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNonce", reflect.TypeOf((*MockTracingContext)(nil).GetNonce), arg0)
+}
+
+// GetStorage mocks base method.
+func (m *MockTracingContext) GetStorage(arg0 Address, arg1 Key) Word {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStorage", arg0, arg1)
+	ret0, _ := ret[0].(Word)
+	return ret0
+}
+
+// GetStorage indicates an expected call of GetStorage.
+func (mr *MockTracingContextMockRecorder) GetStorage(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStorage", reflect.TypeOf((*MockTracingContext)(nil).GetStorage), arg0, arg1)
+}
+
+// GetTracer mocks base method.
+func (m *MockTracingContext) GetTracer() Tracer {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTracer")
+	ret0, _ := ret[0].(Tracer)
+	return ret0
+}
+
+// GetTracer indicates an expected call of GetTracer.
+func (mr *MockTracingContextMockRecorder) GetTracer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTracer", reflect.TypeOf((*MockTracingContext)(nil).GetTracer))
+}
+
+// GetTransientStorage mocks base method.
+func (m *MockTracingContext) GetTransientStorage(arg0 Address, arg1 Key) Word {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransientStorage", arg0, arg1)
+	ret0, _ := ret[0].(Word)
+	return ret0
+}
+
+// GetTransientStorage indicates an expected call of GetTransientStorage.
+func (mr *MockTracingContextMockRecorder) GetTransientStorage(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransientStorage", reflect.TypeOf((*MockTracingContext)(nil).GetTransientStorage), arg0, arg1)
+}
+
+// HasSelfDestructed mocks base method.
+func (m *MockTracingContext) HasSelfDestructed(addr Address) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasSelfDestructed", addr)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// HasSelfDestructed indicates an expected call of HasSelfDestructed.
+func (mr *MockTracingContextMockRecorder) HasSelfDestructed(addr any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasSelfDestructed", reflect.TypeOf((*MockTracingContext)(nil).HasSelfDestructed), addr)
+}
+
+// IsAddressInAccessList mocks base method.
+func (m *MockTracingContext) IsAddressInAccessList(addr Address) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsAddressInAccessList", addr)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsAddressInAccessList indicates an expected call of IsAddressInAccessList.
+func (mr *MockTracingContextMockRecorder) IsAddressInAccessList(addr any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsAddressInAccessList", reflect.TypeOf((*MockTracingContext)(nil).IsAddressInAccessList), addr)
+}
+
+// IsSlotInAccessList mocks base method.
+func (m *MockTracingContext) IsSlotInAccessList(addr Address, key Key) (bool, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsSlotInAccessList", addr, key)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// IsSlotInAccessList indicates an expected call of IsSlotInAccessList.
+func (mr *MockTracingContextMockRecorder) IsSlotInAccessList(addr, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsSlotInAccessList", reflect.TypeOf((*MockTracingContext)(nil).IsSlotInAccessList), addr, key)
+}
+
+// RestoreSnapshot mocks base method.
+func (m *MockTracingContext) RestoreSnapshot(arg0 Snapshot) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RestoreSnapshot", arg0)
+}
+
+// RestoreSnapshot indicates an expected call of RestoreSnapshot.
+func (mr *MockTracingContextMockRecorder) RestoreSnapshot(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreSnapshot", reflect.TypeOf((*MockTracingContext)(nil).RestoreSnapshot), arg0)
+}
+
+// SelfDestruct mocks base method.
+func (m *MockTracingContext) SelfDestruct(addr, beneficiary Address) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelfDestruct", addr, beneficiary)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// SelfDestruct indicates an expected call of SelfDestruct.
+func (mr *MockTracingContextMockRecorder) SelfDestruct(addr, beneficiary any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelfDestruct", reflect.TypeOf((*MockTracingContext)(nil).SelfDestruct), addr, beneficiary)
+}
+
+// SetBalance mocks base method.
+func (m *MockTracingContext) SetBalance(arg0 Address, arg1 Value) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetBalance", arg0, arg1)
+}
+
+// SetBalance indicates an expected call of SetBalance.
+func (mr *MockTracingContextMockRecorder) SetBalance(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBalance", reflect.TypeOf((*MockTracingContext)(nil).SetBalance), arg0, arg1)
+}
+
+// SetCode mocks base method.
+func (m *MockTracingContext) SetCode(arg0 Address, arg1 Code) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetCode", arg0, arg1)
+}
+
+// SetCode indicates an expected call of SetCode.
+func (mr *MockTracingContextMockRecorder) SetCode(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCode", reflect.TypeOf((*MockTracingContext)(nil).SetCode), arg0, arg1)
+}
+
+// SetNonce mocks base method.
+func (m *MockTracingContext) SetNonce(arg0 Address, arg1 uint64) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetNonce", arg0, arg1)
+}
+
+// SetNonce indicates an expected call of SetNonce.
+func (mr *MockTracingContextMockRecorder) SetNonce(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetNonce", reflect.TypeOf((*MockTracingContext)(nil).SetNonce), arg0, arg1)
+}
+
+// SetStorage mocks base method.
+func (m *MockTracingContext) SetStorage(arg0 Address, arg1 Key, arg2 Word) StorageStatus {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetStorage", arg0, arg1, arg2)
+	ret0, _ := ret[0].(StorageStatus)
+	return ret0
+}
+
+// SetStorage indicates an expected call of SetStorage.
+func (mr *MockTracingContextMockRecorder) SetStorage(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetStorage", reflect.TypeOf((*MockTracingContext)(nil).SetStorage), arg0, arg1, arg2)
+}
+
+// SetTransientStorage mocks base method.
+func (m *MockTracingContext) SetTransientStorage(arg0 Address, arg1 Key, arg2 Word) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetTransientStorage", arg0, arg1, arg2)
+}
+
+// SetTransientStorage indicates an expected call of SetTransientStorage.
+func (mr *MockTracingContextMockRecorder) SetTransientStorage(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTransientStorage", reflect.TypeOf((*MockTracingContext)(nil).SetTransientStorage), arg0, arg1, arg2)
+}
@@ -0,0 +1,201 @@
+// Copyright (c) 2024 Fantom Foundation
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at fantom.foundation/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package tosca
+
+import (
+	"reflect"
+	"testing"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestGetTracer_ReturnsNilForNonTracingContexts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	context := NewMockTransactionContext(ctrl)
+	if tracer := GetTracer(context); tracer != nil {
+		t.Errorf("unexpected tracer: %v", tracer)
+	}
+}
+
+func TestGetTracer_ReturnsTracerOfTracingContext(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	tracer := NewMockTracer(ctrl)
+	context := NewMockTracingContext(ctrl)
+	context.EXPECT().GetTracer().Return(tracer)
+	if got := GetTracer(context); got != tracer {
+		t.Errorf("unexpected tracer, wanted %v, got %v", tracer, got)
+	}
+}
+
+func TestNewTracedTransactionContext_ReturnsInputIfNoTracerIsGiven(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	context := NewMockTransactionContext(ctrl)
+	if got := NewTracedTransactionContext(context, nil); got != context {
+		t.Errorf("unexpected context, wanted %v, got %v", context, got)
+	}
+}
+
+func TestTracedTransactionContext_ReportsBalanceChanges(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	tracer := NewMockTracer(ctrl)
+	inner := NewMockTransactionContext(ctrl)
+	context := NewTracedTransactionContext(inner, tracer)
+
+	address := Address{1}
+	inner.EXPECT().GetBalance(address).Return(NewValue(1))
+	inner.EXPECT().SetBalance(address, NewValue(2))
+	tracer.EXPECT().OnBalanceChange(address, NewValue(1), NewValue(2))
+	context.SetBalance(address, NewValue(2))
+
+	// Unchanged balances are not reported.
+	inner.EXPECT().GetBalance(address).Return(NewValue(2))
+	inner.EXPECT().SetBalance(address, NewValue(2))
+	context.SetBalance(address, NewValue(2))
+}
+
+func TestTracedTransactionContext_ReportsStorageChanges(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	tracer := NewMockTracer(ctrl)
+	inner := NewMockTransactionContext(ctrl)
+	context := NewTracedTransactionContext(inner, tracer)
+
+	address := Address{1}
+	key := Key{2}
+	inner.EXPECT().GetStorage(address, key).Return(Word{3})
+	inner.EXPECT().SetStorage(address, key, Word{4}).Return(StorageModified)
+	tracer.EXPECT().OnStorageChange(address, key, Word{3}, Word{4})
+	if got := context.SetStorage(address, key, Word{4}); got != StorageModified {
+		t.Errorf("unexpected storage status, wanted %v, got %v", StorageModified, got)
+	}
+}
+
+func TestTracedTransactionContext_ReportsSelfDestructBalanceTransfer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	tracer := NewMockTracer(ctrl)
+	inner := NewMockTransactionContext(ctrl)
+	context := NewTracedTransactionContext(inner, tracer)
+
+	address := Address{1}
+	beneficiary := Address{2}
+	gomock.InOrder(
+		inner.EXPECT().GetBalance(address).Return(NewValue(5)),
+		inner.EXPECT().GetBalance(beneficiary).Return(NewValue(1)),
+		inner.EXPECT().SelfDestruct(address, beneficiary).Return(true),
+		inner.EXPECT().GetBalance(address).Return(NewValue(0)),
+		inner.EXPECT().GetBalance(beneficiary).Return(NewValue(6)),
+	)
+	tracer.EXPECT().OnBalanceChange(address, NewValue(5), NewValue(0))
+	tracer.EXPECT().OnBalanceChange(beneficiary, NewValue(1), NewValue(6))
+
+	if !context.SelfDestruct(address, beneficiary) {
+		t.Errorf("self-destruct result was not forwarded")
+	}
+}
+
+func TestTracedTransactionContext_ReportsLogs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	tracer := NewMockTracer(ctrl)
+	inner := NewMockTransactionContext(ctrl)
+	context := NewTracedTransactionContext(inner, tracer)
+
+	log := Log{Address: Address{1}, Data: []byte{2}}
+	inner.EXPECT().EmitLog(log)
+	tracer.EXPECT().OnLog(log)
+	context.EmitLog(log)
+}
+
+func TestRunTraced_ReportsCallFrameAndForwardsTracer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	tracer := NewMockTracer(ctrl)
+	interpreter := NewMockInterpreter(ctrl)
+
+	parameters := Parameters{
+		Kind:      Call,
+		Depth:     2,
+		Gas:       100,
+		Sender:    Address{1},
+		Recipient: Address{2},
+		Value:     NewValue(3),
+		Input:     Data{4},
+	}
+	result := Result{Output: Data{5}, GasLeft: 20, GasRefund: 10, Success: true}
+
+	gomock.InOrder(
+		tracer.EXPECT().OnEnter(2, Call, CallParameters{
+			Sender:      Address{1},
+			Recipient:   Address{2},
+			Value:       NewValue(3),
+			Input:       Data{4},
+			Gas:         100,
+			CodeAddress: Address{2},
+		}),
+		interpreter.EXPECT().Run(gomock.Any()).DoAndReturn(func(parameters Parameters) (Result, error) {
+			if parameters.Tracer != tracer {
+				t.Errorf("tracer is not forwarded to interpreter")
+			}
+			return result, nil
+		}),
+		tracer.EXPECT().OnExit(2, CallResult{Output: Data{5}, GasLeft: 20, GasRefund: 10, Success: true}, nil),
+	)
+
+	got, err := RunTraced(interpreter, parameters, tracer)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(result, got) {
+		t.Errorf("unexpected result, wanted %v, got %v", result, got)
+	}
+}
+
+func TestRunTraced_ReportsFailureCauseAndRevertReason(t *testing.T) {
+	// Reverts with Error("nope").
+	output := make(Data, 4+3*32)
+	copy(output, []byte{0x08, 0xc3, 0x79, 0xa0})
+	output[4+31] = 32
+	output[4+63] = 4
+	copy(output[4+64:], "nope")
+
+	tests := map[string]struct {
+		result Result
+		want   CallResult
+	}{
+		"revert": {
+			result: Result{Output: output, GasLeft: 20, FailureCause: FailureRevert},
+			want:   CallResult{Output: output, GasLeft: 20, FailureCause: FailureRevert, RevertReason: "nope"},
+		},
+		"revert without reason": {
+			result: Result{Output: Data{1}, FailureCause: FailureRevert},
+			want:   CallResult{Output: Data{1}, FailureCause: FailureRevert},
+		},
+		"out of gas": {
+			result: Result{FailureCause: FailureOutOfGas},
+			want:   CallResult{FailureCause: FailureOutOfGas},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			tracer := NewMockTracer(ctrl)
+			interpreter := NewMockInterpreter(ctrl)
+
+			gomock.InOrder(
+				tracer.EXPECT().OnEnter(0, Call, gomock.Any()),
+				interpreter.EXPECT().Run(gomock.Any()).Return(test.result, nil),
+				tracer.EXPECT().OnExit(0, test.want, nil),
+			)
+
+			if _, err := RunTraced(interpreter, Parameters{}, tracer); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
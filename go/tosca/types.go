@@ -168,6 +168,33 @@ func (k CallKind) String() string {
 	}
 }
 
+func (c FailureCause) String() string {
+	switch c {
+	case FailureNone:
+		return "none"
+	case FailureRevert:
+		return "execution reverted"
+	case FailureOutOfGas:
+		return "out of gas"
+	case FailureInvalidOpCode:
+		return "invalid opcode"
+	case FailureInvalidJump:
+		return "invalid jump destination"
+	case FailureStackViolation:
+		return "stack violation"
+	case FailureStaticViolation:
+		return "write protection"
+	case FailureCodeSizeLimit:
+		return "max code size exceeded"
+	case FailurePrecompile:
+		return "precompiled contract failed"
+	case FailureOther:
+		return "execution failed"
+	default:
+		return "unknown"
+	}
+}
+
 func (k CallKind) MarshalJSON() ([]byte, error) {
 	var res string
 	switch k {
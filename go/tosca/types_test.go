@@ -416,3 +416,22 @@ func BenchmarkValue_Add(b *testing.B) {
 		Add(x, y)
 	}
 }
+
+func TestFailureCause_String(t *testing.T) {
+	causes := []FailureCause{
+		FailureNone, FailureRevert, FailureOutOfGas, FailureInvalidOpCode,
+		FailureInvalidJump, FailureStackViolation, FailureStaticViolation,
+		FailureCodeSizeLimit, FailurePrecompile, FailureOther,
+	}
+	seen := map[string]bool{}
+	for _, cause := range causes {
+		name := cause.String()
+		if name == "unknown" || seen[name] {
+			t.Errorf("invalid or duplicate name for cause %d: %s", cause, name)
+		}
+		seen[name] = true
+	}
+	if want, got := "unknown", FailureCause(-1).String(); want != got {
+		t.Errorf("unexpected name of invalid cause, wanted %s, got %s", want, got)
+	}
+}